@@ -0,0 +1,67 @@
+package runner
+
+import (
+	"net/url"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/authprovider/authx"
+	"github.com/khulnasoft-lab/vulmap/pkg/output"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/templates"
+)
+
+// GetLoginCallback returns the callback running the login templates of
+// dynamic secrets with the executer options of the scan.
+//
+//nolint:gocritic // executer options are copied to be modified
+func GetLoginCallback(executorOpts protocols.ExecutorOptions) authx.LoginCallback {
+	// login requests are not authenticated themselves and the
+	// login template is not shared with the scanned templates
+	executorOpts.AuthProvider = nil
+	executorOpts.DoNotCache = true
+	// login requests are sent while the requests waiting for the
+	// session hold the slots of the host
+	executorOpts.HostConcurrency = nil
+
+	return func(d *authx.Dynamic, input string) (*authx.LoginResult, error) {
+		template, err := templates.Parse(d.TemplatePath, nil, executorOpts)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse login template")
+		}
+		if template == nil || template.Executer == nil {
+			return nil, errors.New("login template has no requests")
+		}
+
+		ctx := contextargs.NewWithInput(input)
+		for _, variable := range d.Variables {
+			ctx.Set(variable.Key, variable.Value)
+		}
+		values := make(map[string]interface{})
+		err = template.Executer.ExecuteWithResults(ctx, func(event *output.InternalWrappedEvent) {
+			if event.OperatorsResult == nil {
+				return
+			}
+			for _, extracted := range []map[string][]string{event.OperatorsResult.DynamicValues, event.OperatorsResult.Extracts} {
+				for name, value := range extracted {
+					if len(value) > 0 {
+						values[name] = value[0]
+					}
+				}
+			}
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "could not execute login template")
+		}
+
+		result := &authx.LoginResult{Values: values}
+		if parsed, err := url.Parse(input); err == nil && parsed.Host != "" {
+			result.Cookies = ctx.CookieJar.Cookies(parsed)
+		}
+		if len(result.Values) == 0 && len(result.Cookies) == 0 {
+			return nil, errors.New("no values were extracted by login template")
+		}
+		return result, nil
+	}
+}
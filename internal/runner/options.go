@@ -17,9 +17,11 @@ import (
 	"github.com/khulnasoft-lab/gologger/formatter"
 	"github.com/khulnasoft-lab/gologger/levels"
 	"github.com/khulnasoft-lab/vulmap/pkg/catalog/config"
+	"github.com/khulnasoft-lab/vulmap/pkg/core/inputs/hybrid"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolinit"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/utils/vardump"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/headless/engine"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http/httpclientpool"
 	protocoltypes "github.com/khulnasoft-lab/vulmap/pkg/templates/types"
 	"github.com/khulnasoft-lab/vulmap/pkg/types"
 	fileutil "github.com/khulnasoft-lab/utils/file"
@@ -120,12 +122,22 @@ func ValidateOptions(options *types.Options) error {
 		return errors.New("headless mode (-headless) is required if -ho, -sb, -sc or -lha are set")
 	}
 
+	if !hybrid.IsSupportedInputMode(options.InputFileMode) {
+		return fmt.Errorf("unsupported input mode: %s (supported: %s)", options.InputFileMode, hybrid.SupportedInputModes())
+	}
+	if options.InputFileMode != "" && options.InputFileMode != hybrid.DefaultInputMode && options.TargetsFilePath == "" {
+		return errors.New("input mode (-im) requires an input file (-l) to be specified")
+	}
+
 	if options.FollowHostRedirects && options.FollowRedirects {
 		return errors.New("both follow host redirects and follow redirects specified")
 	}
 	if options.ShouldFollowHTTPRedirects() && options.DisableRedirects {
 		return errors.New("both follow redirects and disable redirects specified")
 	}
+	if !httpclientpool.IsSupportedProtocol(options.HTTPProtocol) {
+		return fmt.Errorf("unsupported http protocol: %s (supported: %s)", options.HTTPProtocol, httpclientpool.SupportedProtocols())
+	}
 	// loading the proxy server list from file or cli and test the connectivity
 	if err := loadProxyServers(options); err != nil {
 		return err
@@ -24,6 +24,7 @@ import (
 
 	"github.com/khulnasoft-lab/gologger"
 	"github.com/khulnasoft-lab/vulmap/internal/colorizer"
+	"github.com/khulnasoft-lab/vulmap/pkg/authprovider"
 	"github.com/khulnasoft-lab/vulmap/pkg/catalog"
 	"github.com/khulnasoft-lab/vulmap/pkg/catalog/config"
 	"github.com/khulnasoft-lab/vulmap/pkg/catalog/disk"
@@ -39,7 +40,9 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/automaticscan"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/hostconcurrency"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/hosterrorscache"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/hostratelimit"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/interactsh"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolinit"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/uncover"
@@ -73,11 +76,14 @@ type Runner struct {
 	hmapInputProvider *hybrid.Input
 	browser           *engine.Browser
 	rateLimiter       *ratelimit.Limiter
+	hostRateLimiter   *hostratelimit.Limiter
+	hostConcurrency   *hostconcurrency.Limiter
 	hostErrors        hosterrorscache.CacheInterface
 	resumeCfg         *types.ResumeCfg
 	pprofServer       *http.Server
 	cloudClient       *vulmapcloud.Client
 	cloudTargets      []string
+	authProvider      authprovider.AuthProvider
 }
 
 const pprofServerAddress = "127.0.0.1:8086"
@@ -248,6 +254,23 @@ func New(options *types.Options) (*Runner, error) {
 	}
 	runner.output = outputWriter
 
+	if len(options.SecretsFile) > 0 {
+		// token requests of secrets honour the proxy options
+		authClient, err := httpclientpool.Get(options, &httpclientpool.Configuration{})
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create http client for auth provider")
+		}
+		authProvider, err := authprovider.NewAuthProvider(&authprovider.AuthProviderOptions{
+			SecretsFiles: options.SecretsFile,
+			HTTPClient:   authClient.HTTPClient,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create auth provider")
+		}
+		runner.authProvider = authProvider
+		outputWriter.SetRedactor(authProvider.Redact)
+	}
+
 	if options.JSONL && options.EnableProgressBar {
 		options.StatsJSON = true
 	}
@@ -333,6 +356,16 @@ func New(options *types.Options) (*Runner, error) {
 	} else {
 		runner.rateLimiter = ratelimit.NewUnlimited(context.Background())
 	}
+	if options.RateLimitHost > 0 || !options.DisableAdaptiveRateLimit {
+		runner.hostRateLimiter = hostratelimit.New(&hostratelimit.Options{
+			MaxRate:  options.RateLimitHost,
+			Adaptive: !options.DisableAdaptiveRateLimit,
+			Progress: runner.progress,
+		})
+	}
+	if options.MaxHostConcurrency > 0 {
+		runner.hostConcurrency = hostconcurrency.New(options.MaxHostConcurrency)
+	}
 	return runner, nil
 }
 
@@ -392,6 +425,7 @@ func (r *Runner) Close() {
 	if r.rateLimiter != nil {
 		r.rateLimiter.Stop()
 	}
+	r.hostRateLimiter.Close()
 }
 
 // RunEnumeration sets up the input layer for giving input vulmap.
@@ -424,6 +458,8 @@ func (r *Runner) RunEnumeration() error {
 		Catalog:         r.catalog,
 		IssuesClient:    r.issuesClient,
 		RateLimiter:     r.rateLimiter,
+		HostRateLimiter: r.hostRateLimiter,
+		HostConcurrency: r.hostConcurrency,
 		Interactsh:      r.interactsh,
 		ProjectFile:     r.projectFile,
 		Browser:         r.browser,
@@ -431,15 +467,24 @@ func (r *Runner) RunEnumeration() error {
 		ResumeCfg:       r.resumeCfg,
 		ExcludeMatchers: excludematchers.New(r.options.ExcludeMatchers),
 		InputHelper:     input.NewHelper(),
+		AuthProvider:    r.authProvider,
 	}
 
 	if r.options.ShouldUseHostError() {
 		cache := hosterrorscache.New(r.options.MaxHostError, hosterrorscache.DefaultMaxHostsCount, r.options.TrackError)
 		cache.SetVerbose(r.options.Verbose)
+		cache.SetThrottled(r.hostRateLimiter.Throttled)
 		r.hostErrors = cache
 		executorOpts.HostErrorsCache = cache
 	}
 
+	if r.authProvider != nil {
+		loginCallback := GetLoginCallback(executorOpts)
+		for _, secret := range r.authProvider.DynamicSecrets() {
+			secret.SetLoginCallback(loginCallback)
+		}
+	}
+
 	executorEngine := core.New(r.options)
 	executorEngine.SetExecuterOptions(executorOpts)
 
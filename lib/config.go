@@ -5,6 +5,9 @@ import (
 	"time"
 
 	"github.com/khulnasoft-lab/gologger"
+	"github.com/khulnasoft-lab/ratelimit"
+	errorutil "github.com/khulnasoft-lab/utils/errors"
+	"github.com/khulnasoft-lab/vulmap/pkg/authprovider"
 	"github.com/khulnasoft-lab/vulmap/pkg/model/types/severity"
 	"github.com/khulnasoft-lab/vulmap/pkg/output"
 	"github.com/khulnasoft-lab/vulmap/pkg/progress"
@@ -12,8 +15,8 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/interactsh"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/utils/vardump"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/headless/engine"
+	httpsigner "github.com/khulnasoft-lab/vulmap/pkg/protocols/http/signer"
 	"github.com/khulnasoft-lab/vulmap/pkg/templates/types"
-	"github.com/khulnasoft-lab/ratelimit"
 )
 
 // TemplateSources contains template sources
@@ -254,6 +257,68 @@ func WithProxy(proxy []string, proxyInternalRequests bool) VulmapSDKOptions {
 	}
 }
 
+// WithSecretsFile allows setting secrets files used to authenticate
+// requests to the targets matching the domains of the secrets
+func WithSecretsFile(files ...string) VulmapSDKOptions {
+	return func(e *VulmapEngine) error {
+		if e.mode == threadSafe {
+			return ErrOptionsNotSupported.Msgf("WithSecretsFile")
+		}
+		e.opts.SecretsFile = append(e.opts.SecretsFile, files...)
+		return nil
+	}
+}
+
+// WithAuthProvider allows setting a custom auth provider
+// which returns the auth strategies of the targets
+func WithAuthProvider(provider authprovider.AuthProvider) VulmapSDKOptions {
+	return func(e *VulmapEngine) error {
+		if e.mode == threadSafe {
+			return ErrOptionsNotSupported.Msgf("WithAuthProvider")
+		}
+		e.authProvider = provider
+		return nil
+	}
+}
+
+// SignerOptions contains the signer of the http requests
+type SignerOptions struct {
+	// Signature is the name of the signer used for templates not setting one
+	// (e.g. aws, azure-shared-key, gcp-hmac, hmac or a registered signer)
+	Signature string
+	// Vars are the variables of the signer such as credentials (e.g. hmac-secret)
+	Vars map[string]string
+}
+
+// WithSigner allows signing the requests of http templates
+// not setting a signature with the signer
+func WithSigner(opts SignerOptions) VulmapSDKOptions {
+	return func(e *VulmapEngine) error {
+		if e.mode == threadSafe {
+			return ErrOptionsNotSupported.Msgf("WithSigner")
+		}
+		if _, ok := httpsigner.Get(opts.Signature); opts.Signature != "" && !ok {
+			return errorutil.New("unknown signature %s", opts.Signature)
+		}
+		e.opts.Signature = opts.Signature
+		for key, value := range opts.Vars {
+			if err := e.opts.Vars.Set(key + "=" + value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// SignerDefinition is a signer which can be registered
+type SignerDefinition = httpsigner.Definition
+
+// RegisterSigner registers a custom signer which can then be selected
+// with the signature field of templates or WithSigner
+func RegisterSigner(definition SignerDefinition) error {
+	return httpsigner.Register(definition)
+}
+
 // WithScanStrategy allows setting scan strategy options
 func WithScanStrategy(strategy string) VulmapSDKOptions {
 	return func(e *VulmapEngine) error {
@@ -36,6 +36,8 @@ func createEphemeralObjects(base *VulmapEngine, opts *types.Options) (*unsafeOpt
 		Catalog:         base.catalog,
 		IssuesClient:    base.rc,
 		RateLimiter:     base.rateLimiter,
+		HostRateLimiter: base.executerOpts.HostRateLimiter,
+		HostConcurrency: base.executerOpts.HostConcurrency,
 		Interactsh:      base.interactshClient,
 		HostErrorsCache: base.hostErrCache,
 		Colorizer:       aurora.NewAurora(true),
@@ -6,6 +6,7 @@ import (
 	"io"
 
 	"github.com/khulnasoft-lab/httpx/common/httpx"
+	"github.com/khulnasoft-lab/vulmap/pkg/authprovider"
 	"github.com/khulnasoft-lab/vulmap/pkg/catalog/disk"
 	"github.com/khulnasoft-lab/vulmap/pkg/catalog/loader"
 	"github.com/khulnasoft-lab/vulmap/pkg/core"
@@ -71,6 +72,7 @@ type VulmapEngine struct {
 	mode             engineMode
 	browserInstance  *engine.Browser
 	httpClient       *retryablehttp.Client
+	authProvider     authprovider.AuthProvider
 
 	// unexported meta options
 	opts           *types.Options
@@ -169,6 +171,7 @@ func (e *VulmapEngine) Close() {
 	e.customWriter.Close()
 	e.hostErrCache.Close()
 	e.executerOpts.RateLimiter.Stop()
+	e.executerOpts.HostRateLimiter.Close()
 }
 
 // ExecuteWithCallback executes templates on targets and calls callback on each result(only if results are found)
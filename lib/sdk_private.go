@@ -12,6 +12,7 @@ import (
 	"github.com/khulnasoft-lab/gologger/levels"
 	"github.com/khulnasoft-lab/httpx/common/httpx"
 	"github.com/khulnasoft-lab/vulmap/internal/runner"
+	"github.com/khulnasoft-lab/vulmap/pkg/authprovider"
 	"github.com/khulnasoft-lab/vulmap/pkg/catalog/config"
 	"github.com/khulnasoft-lab/vulmap/pkg/catalog/disk"
 	"github.com/khulnasoft-lab/vulmap/pkg/core"
@@ -21,7 +22,9 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/progress"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/hostconcurrency"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/hosterrorscache"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/hostratelimit"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/interactsh"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolinit"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolstate"
@@ -111,6 +114,23 @@ func (e *VulmapEngine) init() error {
 	e.applyRequiredDefaults()
 	var err error
 
+	if e.authProvider == nil && len(e.opts.SecretsFile) > 0 {
+		// token requests of secrets honour the proxy options
+		authClient, err := httpclientpool.Get(e.opts, &httpclientpool.Configuration{})
+		if err != nil {
+			return err
+		}
+		if e.authProvider, err = authprovider.NewAuthProvider(&authprovider.AuthProviderOptions{
+			SecretsFiles: e.opts.SecretsFile,
+			HTTPClient:   authClient.HTTPClient,
+		}); err != nil {
+			return err
+		}
+	}
+	if writer, ok := e.customWriter.(*output.StandardWriter); ok && e.authProvider != nil {
+		writer.SetRedactor(e.authProvider.Redact)
+	}
+
 	// setup progressbar
 	if e.enableStats {
 		progressInstance, progressErr := progress.NewStatsTicker(e.opts.StatsInterval, e.enableStats, e.opts.StatsJSON, false, e.opts.MetricsPort)
@@ -150,6 +170,7 @@ func (e *VulmapEngine) init() error {
 		Colorizer:       aurora.NewAurora(true),
 		ResumeCfg:       types.NewResumeCfg(),
 		Browser:         e.browserInstance,
+		AuthProvider:    e.authProvider,
 	}
 
 	if e.opts.RateLimitMinute > 0 {
@@ -159,6 +180,24 @@ func (e *VulmapEngine) init() error {
 	} else {
 		e.executerOpts.RateLimiter = ratelimit.NewUnlimited(context.Background())
 	}
+	if e.opts.RateLimitHost > 0 || !e.opts.DisableAdaptiveRateLimit {
+		e.executerOpts.HostRateLimiter = hostratelimit.New(&hostratelimit.Options{
+			MaxRate:  e.opts.RateLimitHost,
+			Adaptive: !e.opts.DisableAdaptiveRateLimit,
+			Progress: e.customProgress,
+		})
+		e.hostErrCache.SetThrottled(e.executerOpts.HostRateLimiter.Throttled)
+	}
+	if e.opts.MaxHostConcurrency > 0 {
+		e.executerOpts.HostConcurrency = hostconcurrency.New(e.opts.MaxHostConcurrency)
+	}
+
+	if e.authProvider != nil {
+		loginCallback := runner.GetLoginCallback(e.executerOpts)
+		for _, secret := range e.authProvider.DynamicSecrets() {
+			secret.SetLoginCallback(loginCallback)
+		}
+	}
 
 	e.engine = core.New(e.opts)
 	e.engine.SetExecuterOptions(e.executerOpts)
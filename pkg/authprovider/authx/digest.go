@@ -0,0 +1,177 @@
+package authx
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DigestAuthStrategy answers digest challenges of servers. Challenges
+// are cached per host so only the first request to a host is retried.
+type DigestAuthStrategy struct {
+	Data *Secret
+
+	mu         sync.Mutex
+	challenges map[string]*digestChallenge
+}
+
+// digestChallenge is a digest challenge received from a host
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       string
+	count     int
+}
+
+// NewDigestAuthStrategy creates a new digest auth strategy
+func NewDigestAuthStrategy(data *Secret) *DigestAuthStrategy {
+	return &DigestAuthStrategy{Data: data, challenges: make(map[string]*digestChallenge)}
+}
+
+// Apply applies the digest auth strategy to the request if
+// a challenge was received from the host.
+func (s *DigestAuthStrategy) Apply(req *http.Request) {
+	s.mu.Lock()
+	challenge, ok := s.challenges[req.URL.Host]
+	if !ok {
+		s.mu.Unlock()
+		return
+	}
+	challenge.count++
+	current := *challenge
+	s.mu.Unlock()
+
+	req.Header.Set("Authorization", current.authorization(s.Data.Username, s.Data.Password, req.Method, req.URL.RequestURI()))
+}
+
+// ApplyOnRR applies the digest auth strategy to the retryable request
+func (s *DigestAuthStrategy) ApplyOnRR(req *retryablehttp.Request) {
+	s.Apply(req.Request)
+}
+
+// Variables returns the username and password
+func (s *DigestAuthStrategy) Variables() map[string]interface{} {
+	return map[string]interface{}{"username": s.Data.Username, "password": s.Data.Password}
+}
+
+// Challenge parses the digest challenge of an unauthorized response
+func (s *DigestAuthStrategy) Challenge(resp *http.Response) bool {
+	if resp == nil || resp.Request == nil || resp.StatusCode != http.StatusUnauthorized {
+		return false
+	}
+	for _, header := range resp.Header.Values("WWW-Authenticate") {
+		if !hasHeaderPrefix(header, "Digest ") {
+			continue
+		}
+		params := parseDigestParams(header[len("Digest "):])
+		challenge := &digestChallenge{
+			realm:     params["realm"],
+			nonce:     params["nonce"],
+			opaque:    params["opaque"],
+			algorithm: strings.ToUpper(params["algorithm"]),
+		}
+		if challenge.algorithm == "" {
+			challenge.algorithm = "MD5"
+		}
+		if challenge.algorithm != "MD5" && challenge.algorithm != "SHA-256" {
+			return false
+		}
+		if qop, ok := params["qop"]; ok {
+			for _, value := range strings.Split(qop, ",") {
+				if strings.TrimSpace(value) == "auth" {
+					challenge.qop = "auth"
+				}
+			}
+			// only auth quality of protection is supported
+			if challenge.qop == "" {
+				return false
+			}
+		}
+
+		s.mu.Lock()
+		previous, ok := s.challenges[resp.Request.URL.Host]
+		s.challenges[resp.Request.URL.Host] = challenge
+		s.mu.Unlock()
+		// do not retry when the credentials of the same challenge are rejected
+		return !ok || previous.nonce != challenge.nonce
+	}
+	return false
+}
+
+// authorization returns the authorization header value for the challenge
+func (c *digestChallenge) authorization(username, password, method, uri string) string {
+	var hasher func() hash.Hash = md5.New
+	if c.algorithm == "SHA-256" {
+		hasher = sha256.New
+	}
+	digest := func(data string) string {
+		h := hasher()
+		h.Write([]byte(data))
+		return hex.EncodeToString(h.Sum(nil))
+	}
+
+	ha1 := digest(fmt.Sprintf("%s:%s:%s", username, c.realm, password))
+	ha2 := digest(fmt.Sprintf("%s:%s", method, uri))
+
+	builder := &strings.Builder{}
+	fmt.Fprintf(builder, `Digest username="%s", realm="%s", nonce="%s", uri="%s", algorithm=%s`, username, c.realm, c.nonce, uri, c.algorithm)
+	if c.qop != "" {
+		nc := fmt.Sprintf("%08x", c.count)
+		cnonce := randomCnonce()
+		fmt.Fprintf(builder, `, qop=%s, nc=%s, cnonce="%s", response="%s"`, c.qop, nc, cnonce, digest(strings.Join([]string{ha1, c.nonce, nc, cnonce, c.qop, ha2}, ":")))
+	} else {
+		fmt.Fprintf(builder, `, response="%s"`, digest(strings.Join([]string{ha1, c.nonce, ha2}, ":")))
+	}
+	if c.opaque != "" {
+		fmt.Fprintf(builder, `, opaque="%s"`, c.opaque)
+	}
+	return builder.String()
+}
+
+// parseDigestParams parses the comma separated parameters of a
+// digest challenge. Values can be quoted and contain commas.
+func parseDigestParams(value string) map[string]string {
+	params := make(map[string]string)
+	for len(value) > 0 {
+		value = strings.TrimLeft(value, " ,")
+		index := strings.Index(value, "=")
+		if index == -1 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(value[:index]))
+		value = strings.TrimLeft(value[index+1:], " ")
+
+		var param string
+		if strings.HasPrefix(value, `"`) {
+			end := strings.Index(value[1:], `"`)
+			if end == -1 {
+				param, value = value[1:], ""
+			} else {
+				param, value = value[1:end+1], value[end+2:]
+			}
+		} else if end := strings.Index(value, ","); end != -1 {
+			param, value = strings.TrimSpace(value[:end]), value[end:]
+		} else {
+			param, value = strings.TrimSpace(value), ""
+		}
+		params[key] = param
+	}
+	return params
+}
+
+// randomCnonce returns a random client nonce
+func randomCnonce() string {
+	data := make([]byte, 8)
+	_, _ = rand.Read(data)
+	return hex.EncodeToString(data)
+}
@@ -0,0 +1,294 @@
+package authx
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/marker"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/replacer"
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// minRefreshInterval is the minimum interval between logins to a host
+// so that concurrent logged-out responses only refresh the session once.
+const minRefreshInterval = 5 * time.Second
+
+// failedLoginRetryInterval is the interval after which a failed login
+// to a host is run again.
+const failedLoginRetryInterval = 30 * time.Second
+
+// minRedactedLength is the minimum length of dynamic values redacted from
+// the output so that short values like status flags are left as they are.
+const minRedactedLength = 4
+
+// LoginResult is the result of running a login template
+type LoginResult struct {
+	// Values are the values extracted by the login template
+	Values map[string]interface{}
+	// Cookies are the cookies set during the login
+	Cookies []*http.Cookie
+}
+
+// LoginCallback runs the login template of the secret for the input
+type LoginCallback func(d *Dynamic, input string) (*LoginResult, error)
+
+// Dynamic is a secret whose values are extracted by running a login
+// template for each matching host. Values of the secret can reference
+// the extracted values as {{name}} placeholders.
+type Dynamic struct {
+	Secret `yaml:",inline"`
+	// TemplatePath is the path of the login template
+	TemplatePath string `yaml:"template"`
+	// Variables are passed to the login template
+	Variables []KV `yaml:"variables"`
+	// Input is the optional input of the login template.
+	// The scheme and host of the target is used by default.
+	// It is required for protocols other than http.
+	Input string `yaml:"input"`
+	// LoggedOut is the condition of responses requiring a new login
+	LoggedOut *LoggedOut `yaml:"logged-out"`
+
+	mu       sync.Mutex
+	sessions map[string]*session
+	login    LoginCallback
+}
+
+// LoggedOut is the condition of logged-out responses
+type LoggedOut struct {
+	// Status are the status codes of logged-out responses
+	Status []int `yaml:"status"`
+	// Location are substrings of the redirect location or
+	// final url of logged-out responses
+	Location []string `yaml:"location"`
+}
+
+// session is the login session of a host
+type session struct {
+	mu          sync.Mutex
+	secret      *Secret
+	values      []string
+	err         error
+	refreshedAt time.Time
+}
+
+// Validate validates the dynamic secret
+func (d *Dynamic) Validate() error {
+	if d.TemplatePath == "" {
+		return errors.New("template is required for dynamic secrets")
+	}
+	if err := d.validateDomains(); err != nil {
+		return err
+	}
+	d.sessions = make(map[string]*session)
+
+	d.Type = SecretType(strings.ToLower(string(d.Type)))
+	switch {
+	case d.Type == DigestAuth, d.Type == OAuth2Auth, d.Type == NTLMAuth, d.Type == NegotiateAuth:
+		return errors.Errorf("%s auth is not supported for dynamic secrets", d.Type)
+	case d.Type == CookiesAuth && len(d.Cookies) == 0:
+		// cookies set during the login are used
+		return nil
+	}
+	return d.validateType()
+}
+
+// SetLoginCallback sets the callback running the login template
+func (d *Dynamic) SetLoginCallback(callback LoginCallback) {
+	d.login = callback
+}
+
+// getSecret returns the secret of the logged-in session of the url
+// running the login template if required. Sessions are kept per scheme
+// and host and failed logins are run again after a retry interval.
+func (d *Dynamic) getSecret(u *url.URL, refresh bool) (*Secret, error) {
+	key := u.Scheme + "://" + u.Host
+
+	d.mu.Lock()
+	current, ok := d.sessions[key]
+	if !ok {
+		current = &session{}
+		d.sessions[key] = current
+	}
+	d.mu.Unlock()
+
+	current.mu.Lock()
+	defer current.mu.Unlock()
+
+	fetched := current.secret != nil || (current.err != nil && time.Since(current.refreshedAt) < failedLoginRetryInterval)
+	if fetched && (!refresh || time.Since(current.refreshedAt) < minRefreshInterval) {
+		return current.secret, current.err
+	}
+	if d.login == nil {
+		return nil, errors.New("login callback is not set for dynamic secret")
+	}
+
+	input := d.Input
+	if input == "" {
+		input = key
+	}
+	result, err := d.login(d, input)
+	current.refreshedAt = time.Now()
+	if err != nil {
+		current.secret, current.err = nil, errors.Wrapf(err, "could not login with %s", d.TemplatePath)
+		gologger.Warning().Msgf("Could not get dynamic secret for %s: %s\n", u.Host, current.err)
+		return nil, current.err
+	}
+	current.secret, current.err = d.resolve(result), nil
+	current.values = d.redactedValues(current.secret, result)
+	return current.secret, nil
+}
+
+// redactedValues returns the values of the resolved secret along with the
+// extracted values substituted into it. Short and numeric values are not
+// returned as replacing them would corrupt the output.
+func (d *Dynamic) redactedValues(resolved *Secret, result *LoginResult) []string {
+	placeholders := strings.Join(append(d.Secret.Values(), d.Username), "\n")
+	values := resolved.Values()
+	for name, value := range result.Values {
+		if strings.Contains(placeholders, marker.ParenthesisOpen+name+marker.ParenthesisClose) || strings.Contains(placeholders, marker.General+name+marker.General) {
+			values = append(values, types.ToString(value))
+		}
+	}
+
+	redacted := values[:0]
+	for _, value := range values {
+		if len(value) < minRedactedLength || isNumeric(value) {
+			continue
+		}
+		redacted = append(redacted, value)
+	}
+	return redacted
+}
+
+// isNumeric returns true if the value is made of digits only
+func isNumeric(value string) bool {
+	return strings.Trim(value, "0123456789") == ""
+}
+
+// resolve returns the secret with placeholders replaced by the login result
+func (d *Dynamic) resolve(result *LoginResult) *Secret {
+	resolved := d.Secret
+	replace := func(values []KV) []KV {
+		replaced := make([]KV, 0, len(values))
+		for _, kv := range values {
+			replaced = append(replaced, KV{Key: kv.Key, Value: replacer.Replace(kv.Value, result.Values)})
+		}
+		return replaced
+	}
+	resolved.Headers = replace(d.Headers)
+	resolved.Params = replace(d.Params)
+	resolved.Cookies = replace(d.Cookies)
+	resolved.Username = replacer.Replace(d.Username, result.Values)
+	resolved.Password = replacer.Replace(d.Password, result.Values)
+	resolved.Token = replacer.Replace(d.Token, result.Values)
+	if d.Type == CookiesAuth && len(d.Cookies) == 0 {
+		for _, cookie := range result.Cookies {
+			resolved.Cookies = append(resolved.Cookies, KV{Key: cookie.Name, Value: cookie.Value})
+		}
+	}
+	return &resolved
+}
+
+// Values returns the secret and extracted values of all logged-in sessions
+func (d *Dynamic) Values() []string {
+	d.mu.Lock()
+	sessions := make([]*session, 0, len(d.sessions))
+	for _, current := range d.sessions {
+		sessions = append(sessions, current)
+	}
+	d.mu.Unlock()
+
+	var values []string
+	for _, current := range sessions {
+		current.mu.Lock()
+		values = append(values, current.values...)
+		current.mu.Unlock()
+	}
+	return values
+}
+
+// Match returns true if the response is a logged-out response
+func (l *LoggedOut) Match(resp *http.Response) bool {
+	for _, status := range l.Status {
+		if resp.StatusCode == status {
+			return true
+		}
+	}
+	locations := []string{resp.Header.Get("Location")}
+	if resp.Request != nil && resp.Request.URL != nil {
+		locations = append(locations, resp.Request.URL.Path)
+	}
+	for _, location := range l.Location {
+		for _, value := range locations {
+			if value != "" && strings.Contains(value, location) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DynamicAuthStrategy applies the secret of the logged-in session of the host
+type DynamicAuthStrategy struct {
+	Dynamic *Dynamic
+	host    string
+}
+
+// NewDynamicAuthStrategy creates a new dynamic auth strategy for the host
+func NewDynamicAuthStrategy(d *Dynamic, host string) *DynamicAuthStrategy {
+	return &DynamicAuthStrategy{Dynamic: d, host: host}
+}
+
+// strategy returns the static strategy of the session of the url
+func (s *DynamicAuthStrategy) strategy(u *url.URL) AuthStrategy {
+	secret, err := s.Dynamic.getSecret(u, false)
+	if err != nil {
+		return nil
+	}
+	return secret.GetStrategy()
+}
+
+// Apply applies the dynamic auth strategy to the request
+func (s *DynamicAuthStrategy) Apply(req *http.Request) {
+	if strategy := s.strategy(req.URL); strategy != nil {
+		strategy.Apply(req)
+	}
+}
+
+// ApplyOnRR applies the dynamic auth strategy to the retryable request
+func (s *DynamicAuthStrategy) ApplyOnRR(req *retryablehttp.Request) {
+	if strategy := s.strategy(req.Request.URL); strategy != nil {
+		strategy.ApplyOnRR(req)
+	}
+}
+
+// Variables returns the variables of the session of the input of the
+// secret. The scheme of the host is unknown to protocols other than http
+// so the input is required to run the login template.
+func (s *DynamicAuthStrategy) Variables() map[string]interface{} {
+	input, err := url.Parse(s.Dynamic.Input)
+	if err != nil || input.Scheme == "" || input.Host == "" {
+		gologger.Debug().Msgf("Could not get dynamic secret variables for %s: input url is required\n", s.host)
+		return nil
+	}
+	if strategy := s.strategy(input); strategy != nil {
+		return strategy.Variables()
+	}
+	return nil
+}
+
+// Challenge runs the login template again for logged-out responses
+func (s *DynamicAuthStrategy) Challenge(resp *http.Response) bool {
+	if s.Dynamic.LoggedOut == nil || resp.Request == nil || !s.Dynamic.LoggedOut.Match(resp) {
+		return false
+	}
+	_, err := s.Dynamic.getSecret(resp.Request.URL, true)
+	return err == nil
+}
@@ -0,0 +1,172 @@
+package authx
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDynamicValidate(t *testing.T) {
+	auth, err := GetAuthDataFromYAML([]byte(`
+dynamic:
+  - template: login.yaml
+    type: cookie
+    domains: [example.com]
+  - template: login.yaml
+    type: header
+    domains: [api.example.com]
+    headers:
+      - key: Authorization
+        value: "Bearer {{token}}"
+    logged-out:
+      status: [401]
+      location: [/login]
+`))
+	require.Nil(t, err, "could not parse dynamic secrets")
+	require.Len(t, auth.Dynamic, 2)
+	require.Equal(t, []int{401}, auth.Dynamic[1].LoggedOut.Status)
+
+	_, err = GetAuthDataFromYAML([]byte("dynamic:\n  - type: bearer\n    domains: [example.com]\n    token: x\n"))
+	require.NotNil(t, err, "could parse dynamic secret without template")
+}
+
+func TestDynamicAuthStrategy(t *testing.T) {
+	dynamic := &Dynamic{
+		Secret: Secret{
+			Type:    HeadersAuth,
+			Domains: []string{"example.com"},
+			Headers: []KV{{Key: "Authorization", Value: "Bearer {{token}}"}},
+		},
+		TemplatePath: "login.yaml",
+		LoggedOut:    &LoggedOut{Location: []string{"/login"}},
+	}
+	require.Nil(t, dynamic.Validate())
+
+	var logins []string
+	dynamic.SetLoginCallback(func(d *Dynamic, input string) (*LoginResult, error) {
+		logins = append(logins, input)
+		return &LoginResult{Values: map[string]interface{}{"token": "session-" + string(rune('0'+len(logins)))}}, nil
+	})
+	strategy := NewDynamicAuthStrategy(dynamic, "example.com")
+
+	req, err := http.NewRequest(http.MethodGet, "https://example.com/api", nil)
+	require.Nil(t, err)
+	strategy.Apply(req)
+	strategy.Apply(req)
+	require.Equal(t, "Bearer session-1", req.Header.Get("Authorization"), "could not apply dynamic secret")
+	require.Equal(t, []string{"https://example.com"}, logins, "could not login once for the host")
+	require.ElementsMatch(t, []string{"Bearer session-1", "session-1"}, dynamic.Values(), "could not get values of session")
+
+	resp := &http.Response{StatusCode: http.StatusFound, Header: http.Header{}, Request: req}
+	require.False(t, strategy.Challenge(resp), "could refresh session of logged-in response")
+
+	resp.Header.Set("Location", "/login?next=/api")
+	dynamic.sessions["https://example.com"].refreshedAt = time.Now().Add(-minRefreshInterval)
+	require.True(t, strategy.Challenge(resp), "could not refresh session of logged-out response")
+	strategy.Apply(req)
+	require.Equal(t, "Bearer session-2", req.Header.Get("Authorization"), "could not apply refreshed secret")
+
+	require.True(t, strategy.Challenge(resp))
+	require.Len(t, logins, 2, "could refresh session again within refresh interval")
+
+	req, err = http.NewRequest(http.MethodGet, "http://example.com/api", nil)
+	require.Nil(t, err)
+	strategy.Apply(req)
+	require.Equal(t, "http://example.com", logins[2], "could not login to other scheme of the host")
+}
+
+func TestDynamicFailedLogin(t *testing.T) {
+	dynamic := &Dynamic{
+		Secret:       Secret{Type: BearerAuth, Domains: []string{"example.com"}, Token: "{{token}}"},
+		TemplatePath: "login.yaml",
+	}
+	require.Nil(t, dynamic.Validate())
+
+	var logins int
+	dynamic.SetLoginCallback(func(d *Dynamic, input string) (*LoginResult, error) {
+		logins++
+		if logins == 1 {
+			return nil, errors.New("login failed")
+		}
+		return &LoginResult{Values: map[string]interface{}{"token": "session-token"}}, nil
+	})
+	strategy := NewDynamicAuthStrategy(dynamic, "example.com")
+
+	req, err := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+	require.Nil(t, err)
+	strategy.Apply(req)
+	strategy.Apply(req)
+	require.Empty(t, req.Header.Get("Authorization"), "could apply secret of failed login")
+	require.Equal(t, 1, logins, "could login again before retry interval")
+
+	dynamic.sessions["https://example.com"].refreshedAt = time.Now().Add(-failedLoginRetryInterval)
+	strategy.Apply(req)
+	require.Equal(t, "Bearer session-token", req.Header.Get("Authorization"), "could not retry failed login")
+}
+
+func TestDynamicRedactedValues(t *testing.T) {
+	dynamic := &Dynamic{
+		Secret: Secret{
+			Type:    HeadersAuth,
+			Domains: []string{"example.com"},
+			Headers: []KV{{Key: "X-Token", Value: "{{token}}"}, {Key: "X-User", Value: "{{id}}"}},
+		},
+		TemplatePath: "login.yaml",
+	}
+	require.Nil(t, dynamic.Validate())
+	dynamic.SetLoginCallback(func(d *Dynamic, input string) (*LoginResult, error) {
+		return &LoginResult{
+			Values:  map[string]interface{}{"token": "session-token", "id": 42, "status": "ok", "csrf": "unused-csrf"},
+			Cookies: []*http.Cookie{{Name: "tracking", Value: "tracking-value"}},
+		}, nil
+	})
+
+	req, err := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+	require.Nil(t, err)
+	NewDynamicAuthStrategy(dynamic, "example.com").Apply(req)
+	values := dynamic.Values()
+	require.Contains(t, values, "session-token", "could not get substituted value")
+	for _, value := range []string{"42", "ok", "unused-csrf", "tracking-value"} {
+		require.NotContains(t, values, value, "could get value not to redact")
+	}
+}
+
+func TestDynamicVariables(t *testing.T) {
+	dynamic := &Dynamic{
+		Secret:       Secret{Type: BearerAuth, Domains: []string{"example.com"}, Token: "{{token}}"},
+		TemplatePath: "login.yaml",
+	}
+	require.Nil(t, dynamic.Validate())
+	var inputs []string
+	dynamic.SetLoginCallback(func(d *Dynamic, input string) (*LoginResult, error) {
+		inputs = append(inputs, input)
+		return &LoginResult{Values: map[string]interface{}{"token": "session-token"}}, nil
+	})
+
+	strategy := NewDynamicAuthStrategy(dynamic, "example.com:22")
+	require.Nil(t, strategy.Variables(), "could get variables without input")
+	require.Empty(t, inputs, "could login without input")
+
+	dynamic.Input = "https://example.com/login"
+	require.Equal(t, map[string]interface{}{"token": "session-token"}, strategy.Variables(), "could not get variables of input")
+	require.Equal(t, []string{"https://example.com/login"}, inputs, "could not login with input")
+}
+
+func TestDynamicLoginCookies(t *testing.T) {
+	dynamic := &Dynamic{
+		Secret:       Secret{Type: CookiesAuth, Domains: []string{"example.com"}},
+		TemplatePath: "login.yaml",
+	}
+	require.Nil(t, dynamic.Validate())
+	dynamic.SetLoginCallback(func(d *Dynamic, input string) (*LoginResult, error) {
+		return &LoginResult{Cookies: []*http.Cookie{{Name: "session", Value: "abc"}}}, nil
+	})
+
+	req, err := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+	require.Nil(t, err)
+	NewDynamicAuthStrategy(dynamic, "example.com").Apply(req)
+	require.Equal(t, "session=abc", req.Header.Get("Cookie"), "could not apply login cookies")
+}
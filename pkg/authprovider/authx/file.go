@@ -0,0 +1,258 @@
+// Package authx implements the secrets file format and the
+// authentication strategies applied to requests of matching targets.
+package authx
+
+import (
+	"net"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// SecretType is the type of authentication of a secret
+type SecretType string
+
+const (
+	BasicAuth     SecretType = "basic"
+	BearerAuth    SecretType = "bearer"
+	HeadersAuth   SecretType = "header"
+	QueryAuth     SecretType = "query"
+	CookiesAuth   SecretType = "cookie"
+	DigestAuth    SecretType = "digest"
+	OAuth2Auth    SecretType = "oauth2"
+	NTLMAuth      SecretType = "ntlm"
+	NegotiateAuth SecretType = "negotiate"
+)
+
+// Authx is the structure of a secrets file
+type Authx struct {
+	ID      string     `yaml:"id"`
+	Info    AuthInfo   `yaml:"info"`
+	Secrets []Secret   `yaml:"static"`
+	Dynamic []*Dynamic `yaml:"dynamic"`
+}
+
+// AuthInfo contains the information of a secrets file
+type AuthInfo struct {
+	Name        string `yaml:"name"`
+	Author      string `yaml:"author"`
+	Description string `yaml:"description"`
+}
+
+// KV is a key value pair of a header, cookie or query parameter
+type KV struct {
+	Key   string `yaml:"key"`
+	Value string `yaml:"value"`
+}
+
+// Secret is a secret applied to requests of the matching domains
+type Secret struct {
+	// Type is the type of authentication of the secret
+	Type SecretType `yaml:"type"`
+	// Domains are the domains the secret is applied to.
+	// host, host:port and *.example.com globs are supported.
+	Domains []string `yaml:"domains"`
+	// DomainsRegex are the regexes matched against the host of targets
+	DomainsRegex []string `yaml:"domains-regex"`
+	Headers      []KV     `yaml:"headers"`
+	Cookies      []KV     `yaml:"cookies"`
+	Params       []KV     `yaml:"params"`
+	Username     string   `yaml:"username"`
+	Password     string   `yaml:"password"`
+	Token        string   `yaml:"token"`
+	// OAuth2 is the token endpoint configuration of oauth2 secrets
+	OAuth2 *OAuth2 `yaml:"oauth2"`
+	// KDC is the kerberos KDC of negotiate secrets. The KDC of the
+	// realm is looked up with DNS if not set.
+	KDC string `yaml:"kdc"`
+	// SPN is the service principal name of negotiate secrets.
+	// HTTP/<host> is used by default.
+	SPN string `yaml:"spn"`
+
+	compiledDomainsRegex []*regexp.Regexp
+}
+
+// GetAuthDataFromFile reads and validates a secrets file
+func GetAuthDataFromFile(file string) (*Authx, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read secrets file")
+	}
+	return GetAuthDataFromYAML(data)
+}
+
+// GetAuthDataFromYAML parses and validates secrets from yaml data
+func GetAuthDataFromYAML(data []byte) (*Authx, error) {
+	var auth Authx
+	if err := yaml.Unmarshal(data, &auth); err != nil {
+		return nil, errors.Wrap(err, "could not parse secrets file")
+	}
+	for i := range auth.Secrets {
+		if err := auth.Secrets[i].Validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid secret at index %d", i)
+		}
+	}
+	for i, dynamic := range auth.Dynamic {
+		if err := dynamic.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid dynamic secret at index %d", i)
+		}
+	}
+	return &auth, nil
+}
+
+// Validate validates the secret and compiles its domain regexes
+func (s *Secret) Validate() error {
+	if err := s.validateDomains(); err != nil {
+		return err
+	}
+	return s.validateType()
+}
+
+// validateDomains validates and compiles the domains of the secret
+func (s *Secret) validateDomains() error {
+	if len(s.Domains) == 0 && len(s.DomainsRegex) == 0 {
+		return errors.New("domains or domains-regex is required")
+	}
+	for _, domain := range s.DomainsRegex {
+		compiled, err := regexp.Compile(domain)
+		if err != nil {
+			return errors.Wrapf(err, "could not compile domain regex %s", domain)
+		}
+		s.compiledDomainsRegex = append(s.compiledDomainsRegex, compiled)
+	}
+	return nil
+}
+
+// validateType validates the fields required by the type of the secret
+func (s *Secret) validateType() error {
+	s.Type = SecretType(strings.ToLower(string(s.Type)))
+	switch s.Type {
+	case BasicAuth, DigestAuth, NTLMAuth:
+		if s.Username == "" || s.Password == "" {
+			return errors.Errorf("username and password are required for %s auth", s.Type)
+		}
+	case NegotiateAuth:
+		if s.Username == "" || s.Password == "" {
+			return errors.New("username and password are required for negotiate auth")
+		}
+		if user, realm, ok := strings.Cut(s.Username, "@"); !ok || user == "" || realm == "" {
+			return errors.New("username must be in user@REALM format for negotiate auth")
+		}
+	case BearerAuth:
+		if s.Token == "" {
+			return errors.New("token is required for bearer auth")
+		}
+	case HeadersAuth:
+		if err := validateKV("headers", s.Headers); err != nil {
+			return err
+		}
+	case CookiesAuth:
+		if err := validateKV("cookies", s.Cookies); err != nil {
+			return err
+		}
+	case QueryAuth:
+		if err := validateKV("params", s.Params); err != nil {
+			return err
+		}
+	case OAuth2Auth:
+		if s.OAuth2 == nil {
+			return errors.New("oauth2 configuration is required for oauth2 auth")
+		}
+		if err := s.OAuth2.Validate(); err != nil {
+			return err
+		}
+	default:
+		return errors.Errorf("unsupported secret type %q", s.Type)
+	}
+	return nil
+}
+
+func validateKV(name string, values []KV) error {
+	if len(values) == 0 {
+		return errors.Errorf("%s are required", name)
+	}
+	for _, kv := range values {
+		if kv.Key == "" {
+			return errors.Errorf("key is required for %s", name)
+		}
+	}
+	return nil
+}
+
+// MatchesHost returns true if the secret is applied to the host.
+// The host can optionally contain a port.
+func (s *Secret) MatchesHost(host string) bool {
+	host = strings.ToLower(host)
+	hostname := strings.Trim(host, "[]")
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+
+	for _, domain := range s.Domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		switch {
+		case domain == host || domain == hostname:
+			return true
+		case strings.HasPrefix(domain, "*."):
+			if strings.HasSuffix(hostname, domain[1:]) {
+				return true
+			}
+		}
+	}
+	for _, regex := range s.compiledDomainsRegex {
+		if regex.MatchString(host) || regex.MatchString(hostname) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetStrategy returns the auth strategy of the secret
+func (s *Secret) GetStrategy() AuthStrategy {
+	switch s.Type {
+	case BasicAuth:
+		return NewBasicAuthStrategy(s)
+	case BearerAuth:
+		return NewBearerTokenAuthStrategy(s)
+	case HeadersAuth:
+		return NewHeadersAuthStrategy(s)
+	case CookiesAuth:
+		return NewCookiesAuthStrategy(s)
+	case QueryAuth:
+		return NewQueryAuthStrategy(s)
+	case DigestAuth:
+		return NewDigestAuthStrategy(s)
+	case OAuth2Auth:
+		return NewOAuth2AuthStrategy(s)
+	case NTLMAuth:
+		return NewNTLMAuthStrategy(s)
+	case NegotiateAuth:
+		return NewNegotiateAuthStrategy(s)
+	}
+	return nil
+}
+
+// Values returns the secret values to be redacted from output.
+// Usernames are not considered secret.
+func (s *Secret) Values() []string {
+	var values []string
+	for _, value := range []string{s.Password, s.Token} {
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	for _, kvs := range [][]KV{s.Headers, s.Cookies, s.Params} {
+		for _, kv := range kvs {
+			if kv.Value != "" {
+				values = append(values, kv.Value)
+			}
+		}
+	}
+	if s.OAuth2 != nil {
+		values = append(values, s.OAuth2.Values()...)
+	}
+	return values
+}
@@ -0,0 +1,140 @@
+package authx
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecrets = `
+id: test-secrets
+info:
+  name: test secrets
+static:
+  - type: basic
+    domains:
+      - example.com
+      - "*.example.org"
+    username: admin
+    password: secret
+  - type: header
+    domains-regex:
+      - ^api\.example\.net$
+    headers:
+      - key: X-API-Key
+        value: api-key
+  - type: cookie
+    domains:
+      - example.com:8443
+    cookies:
+      - key: session
+        value: session-value
+`
+
+func TestGetAuthDataFromYAML(t *testing.T) {
+	auth, err := GetAuthDataFromYAML([]byte(testSecrets))
+	require.Nil(t, err, "could not parse secrets")
+	require.Equal(t, "test-secrets", auth.ID)
+	require.Len(t, auth.Secrets, 3, "could not get secrets")
+
+	_, err = GetAuthDataFromYAML([]byte("static:\n  - type: bearer\n    domains: [example.com]\n"))
+	require.NotNil(t, err, "could parse bearer secret without token")
+
+	_, err = GetAuthDataFromYAML([]byte("static:\n  - type: basic\n    username: a\n    password: b\n"))
+	require.NotNil(t, err, "could parse secret without domains")
+
+	_, err = GetAuthDataFromYAML([]byte("static:\n  - type: unknown\n    domains: [example.com]\n"))
+	require.NotNil(t, err, "could parse secret with unknown type")
+}
+
+func TestSecretMatchesHost(t *testing.T) {
+	auth, err := GetAuthDataFromYAML([]byte(testSecrets))
+	require.Nil(t, err, "could not parse secrets")
+	basic, header, cookie := auth.Secrets[0], auth.Secrets[1], auth.Secrets[2]
+
+	require.True(t, basic.MatchesHost("example.com"))
+	require.True(t, basic.MatchesHost("EXAMPLE.com:443"), "could not match host with port")
+	require.True(t, basic.MatchesHost("a.b.example.org"), "could not match glob")
+	require.False(t, basic.MatchesHost("example.org"), "could match glob without subdomain")
+	require.False(t, basic.MatchesHost("notexample.com"))
+
+	require.True(t, header.MatchesHost("api.example.net:8080"), "could not match regex")
+	require.False(t, header.MatchesHost("www.example.net"))
+
+	require.True(t, cookie.MatchesHost("example.com:8443"), "could not match host and port")
+	require.False(t, cookie.MatchesHost("example.com"), "could match host with different port")
+}
+
+func TestStrategies(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "http://example.com/?a=1", nil)
+	require.Nil(t, err)
+	req.Header.Set("Cookie", "session=old; theme=dark")
+
+	NewBasicAuthStrategy(&Secret{Username: "admin", Password: "secret"}).Apply(req)
+	username, password, ok := req.BasicAuth()
+	require.True(t, ok, "could not apply basic auth")
+	require.Equal(t, "admin", username)
+	require.Equal(t, "secret", password)
+
+	NewCookiesAuthStrategy(&Secret{Cookies: []KV{{Key: "session", Value: "new"}}}).Apply(req)
+	require.Equal(t, "session=new; theme=dark", req.Header.Get("Cookie"), "could not replace cookie")
+
+	NewQueryAuthStrategy(&Secret{Params: []KV{{Key: "token", Value: "t"}}}).Apply(req)
+	require.Equal(t, "a=1&token=t", req.URL.RawQuery, "could not apply query auth")
+
+	t.Run("payloads", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "http://example.com/?z=%27+OR+1=1--&a=<script>&token=old&token=older", nil)
+		require.Nil(t, err)
+		req.Header.Set("Cookie", `theme="dark mode"; id=1\' OR '1'='1; session=old;session=dup`)
+
+		NewCookiesAuthStrategy(&Secret{Cookies: []KV{{Key: "session", Value: "new"}, {Key: "csrf", Value: "c"}}}).Apply(req)
+		require.Equal(t, `theme="dark mode"; id=1\' OR '1'='1; session=new; csrf=c`, req.Header.Get("Cookie"), "could not keep cookie payloads")
+
+		NewQueryAuthStrategy(&Secret{Params: []KV{{Key: "token", Value: "a b"}, {Key: "key", Value: "k"}}}).Apply(req)
+		require.Equal(t, "z=%27+OR+1=1--&a=<script>&token=a+b&key=k", req.URL.RawQuery, "could not keep query payloads")
+	})
+	t.Run("retryable", func(t *testing.T) {
+		req, err := retryablehttp.NewRequest(http.MethodGet, "http://example.com/?z=%27+OR+1=1--&a=<script>&flag&token=old", nil)
+		require.Nil(t, err)
+
+		NewQueryAuthStrategy(&Secret{Params: []KV{{Key: "token", Value: "a b"}}}).ApplyOnRR(req)
+		require.Equal(t, "z=%27+OR+1=1--&a=<script>&flag&token=a+b", req.Request.URL.RawQuery, "could not keep query payloads")
+		require.Equal(t, req.Request.URL.RawQuery, req.URL.RawQuery, "could not keep urls in sync")
+	})
+}
+
+func TestDigestAuthStrategy(t *testing.T) {
+	strategy := NewDigestAuthStrategy(&Secret{Username: "Mufasa", Password: "Circle Of Life"})
+
+	req, err := http.NewRequest(http.MethodGet, "http://example.com/dir/index.html", nil)
+	require.Nil(t, err)
+	strategy.Apply(req)
+	require.Empty(t, req.Header.Get("Authorization"), "could apply digest auth without challenge")
+
+	resp := &http.Response{StatusCode: http.StatusUnauthorized, Header: http.Header{}, Request: req}
+	resp.Header.Set("WWW-Authenticate", `Digest realm="testrealm@host.com", qop="auth,auth-int", nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093", opaque="5ccc069c403ebaf9f0171e9517f40e41"`)
+	require.True(t, strategy.Challenge(resp), "could not parse challenge")
+	require.False(t, strategy.Challenge(resp), "could retry rejected challenge")
+
+	strategy.Apply(req)
+	authorization := req.Header.Get("Authorization")
+	require.True(t, strings.HasPrefix(authorization, `Digest username="Mufasa", realm="testrealm@host.com"`), "could not apply digest auth")
+	require.Contains(t, authorization, `uri="/dir/index.html"`)
+	require.Contains(t, authorization, "qop=auth, nc=00000001")
+	require.Contains(t, authorization, `opaque="5ccc069c403ebaf9f0171e9517f40e41"`)
+
+	other, err := http.NewRequest(http.MethodGet, "http://other.com/", nil)
+	require.Nil(t, err)
+	strategy.Apply(other)
+	require.Empty(t, other.Header.Get("Authorization"), "could apply challenge of other host")
+}
+
+func TestDigestAuthorization(t *testing.T) {
+	// RFC 2617 example without the client nonce dependent qop
+	challenge := &digestChallenge{realm: "testrealm@host.com", nonce: "dcd98b7102dd2f0e8b11d0f600bfb0c093", algorithm: "MD5"}
+	authorization := challenge.authorization("Mufasa", "Circle Of Life", http.MethodGet, "/dir/index.html")
+	require.Contains(t, authorization, `response="670fd8c2df070c60b045671b8b24ff02"`, "could not compute digest response")
+}
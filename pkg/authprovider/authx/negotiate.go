@@ -0,0 +1,91 @@
+package authx
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/projectdiscovery/retryablehttp-go"
+	kclient "github.com/ropnop/gokrb5/v8/client"
+	kconfig "github.com/ropnop/gokrb5/v8/config"
+	"github.com/ropnop/gokrb5/v8/spnego"
+)
+
+// NegotiateAuthStrategy sets the kerberos SPNEGO negotiate authorization
+// header with service tickets of the host requested from the KDC of the
+// realm of the user.
+type NegotiateAuthStrategy struct {
+	Data *Secret
+
+	once   sync.Once
+	client *kclient.Client
+	err    error
+	warned sync.Map
+}
+
+// NewNegotiateAuthStrategy creates a new negotiate auth strategy
+func NewNegotiateAuthStrategy(data *Secret) *NegotiateAuthStrategy {
+	return &NegotiateAuthStrategy{Data: data}
+}
+
+// Apply applies the negotiate auth strategy to the request
+func (s *NegotiateAuthStrategy) Apply(req *http.Request) {
+	client, err := s.kerberosClient()
+	if err != nil {
+		return
+	}
+	spn := s.Data.SPN
+	if spn == "" {
+		spn = "HTTP/" + req.URL.Hostname()
+	}
+	if err := spnego.SetSPNEGOHeader(client, req, spn); err != nil {
+		if _, warned := s.warned.LoadOrStore(spn, struct{}{}); !warned {
+			gologger.Warning().Msgf("Could not get kerberos ticket for %s: %s\n", spn, err)
+		}
+	}
+}
+
+// ApplyOnRR applies the negotiate auth strategy to the retryable request
+func (s *NegotiateAuthStrategy) ApplyOnRR(req *retryablehttp.Request) {
+	s.Apply(req.Request)
+}
+
+// Variables returns the username and password
+func (s *NegotiateAuthStrategy) Variables() map[string]interface{} {
+	return map[string]interface{}{"username": s.Data.Username, "password": s.Data.Password}
+}
+
+// kerberosClient returns the kerberos client of the user logged in once
+func (s *NegotiateAuthStrategy) kerberosClient() (*kclient.Client, error) {
+	s.once.Do(func() {
+		username, realm, _ := strings.Cut(s.Data.Username, "@")
+		realm = strings.ToUpper(realm)
+
+		config, err := kconfig.NewFromString(krb5Config(realm, s.Data.KDC))
+		if err != nil {
+			s.err = errors.Wrap(err, "could not create kerberos config")
+			return
+		}
+		client := kclient.NewWithPassword(username, realm, s.Data.Password, config, kclient.DisablePAFXFAST(true))
+		if err := client.Login(); err != nil {
+			s.err = errors.Wrapf(err, "could not login to realm %s", realm)
+			gologger.Warning().Msgf("Could not get kerberos ticket for %s: %s\n", s.Data.Username, s.err)
+			return
+		}
+		s.client = client
+	})
+	return s.client, s.err
+}
+
+// krb5Config returns the kerberos config of the realm looking up
+// the KDC of the realm with DNS if not provided.
+func krb5Config(realm, kdc string) string {
+	if kdc == "" {
+		return fmt.Sprintf("[libdefaults]\ndns_lookup_kdc = true\ndefault_realm = %s\n", realm)
+	}
+	return fmt.Sprintf("[libdefaults]\ndefault_realm = %s\n[realms]\n%s = {\n\tkdc = %s\n}\n", realm, realm, kdc)
+}
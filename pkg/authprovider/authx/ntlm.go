@@ -0,0 +1,314 @@
+package authx
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	ntlmssp "github.com/Azure/go-ntlmssp"
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+const (
+	// maxNTLMBodySize is the maximum size of request bodies buffered to be
+	// sent again during handshakes.
+	maxNTLMBodySize = 10 * 1024 * 1024
+	// ntlmIdleConnTimeout is the time after which idle dedicated
+	// connections are closed once the requests to a host are done.
+	ntlmIdleConnTimeout = 90 * time.Second
+)
+
+// NTLMAuthStrategy authenticates connections to hosts with the NTLMv2
+// handshake. As NTLM authenticates connections instead of requests, requests
+// to a host are sent over a dedicated keep-alive connection which is
+// authenticated again whenever the server challenges it.
+type NTLMAuthStrategy struct {
+	Data *Secret
+}
+
+// ntlmConn is the dedicated connection of a host
+type ntlmConn struct {
+	// handshake is held exclusively during handshakes so that no
+	// other request is sent over the connection being authenticated
+	handshake sync.RWMutex
+	// authenticated is set once the connection was authenticated
+	// or the host did not challenge it.
+	authenticated atomic.Bool
+	transport     http.RoundTripper
+}
+
+// ntlmConnKey identifies the dedicated connection of a user to a host
+type ntlmConnKey struct {
+	ntlmTransportKey
+	host string
+}
+
+// ntlmTransportKey identifies the dedicated transport of a user
+type ntlmTransportKey struct {
+	base               *http.Transport
+	username, password string
+}
+
+// ntlmPool contains the dedicated connections shared by the strategies of
+// the same user so that templates authenticating the same hosts reuse their
+// connections instead of opening new ones. Each user has a single dedicated
+// transport keeping one connection per host.
+var ntlmPool = struct {
+	sync.Mutex
+	conns      map[ntlmConnKey]*ntlmConn
+	transports map[ntlmTransportKey]*http.Transport
+}{
+	conns:      make(map[ntlmConnKey]*ntlmConn),
+	transports: make(map[ntlmTransportKey]*http.Transport),
+}
+
+// NewNTLMAuthStrategy creates a new ntlm auth strategy
+func NewNTLMAuthStrategy(data *Secret) *NTLMAuthStrategy {
+	return &NTLMAuthStrategy{Data: data}
+}
+
+// Apply applies the ntlm auth strategy to the request
+func (s *NTLMAuthStrategy) Apply(req *http.Request) {
+	*req = *withRoundTripStrategy(req, s)
+}
+
+// ApplyOnRR applies the ntlm auth strategy to the retryable request
+func (s *NTLMAuthStrategy) ApplyOnRR(req *retryablehttp.Request) {
+	req.Request = withRoundTripStrategy(req.Request, s)
+}
+
+// Variables returns the username and password
+func (s *NTLMAuthStrategy) Variables() map[string]interface{} {
+	return map[string]interface{}{"username": s.Data.Username, "password": s.Data.Password}
+}
+
+// RoundTrip sends the request over the dedicated connection of the host
+// running the handshake if the server challenges the connection.
+func (s *NTLMAuthStrategy) RoundTrip(base http.RoundTripper, req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := s.conn(base, req.URL)
+	if err != nil {
+		return nil, err
+	}
+	if resp, ok, err := s.authenticate(conn, req, body); ok || err != nil {
+		return resp, err
+	}
+
+	// the connection is likely authenticated by previous requests
+	conn.handshake.RLock()
+	resp, err := conn.transport.RoundTrip(cloneRequest(req, body, ""))
+	conn.handshake.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	scheme := ntlmScheme(resp)
+	if resp.StatusCode != http.StatusUnauthorized || scheme == "" {
+		return resp, nil
+	}
+	drainBody(resp)
+
+	conn.handshake.Lock()
+	defer conn.handshake.Unlock()
+	return s.handshake(conn, req, body, scheme)
+}
+
+// authenticate authenticates the connection before the first request to the
+// host. The server is challenged with the request without its body so that
+// the body is only sent over the authenticated connection. The response
+// is returned if it answers the request, like after a handshake.
+func (s *NTLMAuthStrategy) authenticate(conn *ntlmConn, req *http.Request, body []byte) (*http.Response, bool, error) {
+	if conn.authenticated.Load() {
+		return nil, false, nil
+	}
+	conn.handshake.Lock()
+	defer conn.handshake.Unlock()
+	if conn.authenticated.Load() {
+		return nil, false, nil
+	}
+
+	resp, err := conn.transport.RoundTrip(cloneRequest(req, []byte{}, ""))
+	if err != nil {
+		return nil, false, err
+	}
+	scheme := ntlmScheme(resp)
+	if resp.StatusCode != http.StatusUnauthorized || scheme == "" {
+		// the host did not challenge the connection
+		conn.authenticated.Store(true)
+		if len(body) == 0 {
+			return resp, true, nil
+		}
+		drainBody(resp)
+		return nil, false, nil
+	}
+	drainBody(resp)
+
+	resp, err = s.handshake(conn, req, body, scheme)
+	return resp, true, err
+}
+
+// handshake sends the negotiate and authenticate messages of the
+// handshake with the auth scheme offered by the server.
+func (s *NTLMAuthStrategy) handshake(conn *ntlmConn, req *http.Request, body []byte, scheme string) (*http.Response, error) {
+	user, domain, domainNeeded := ntlmssp.GetDomain(s.Data.Username)
+	negotiate, err := ntlmssp.NewNegotiateMessage(domain, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create ntlm negotiate message")
+	}
+	resp, err := conn.transport.RoundTrip(cloneRequest(req, body, scheme+" "+base64.StdEncoding.EncodeToString(negotiate)))
+	if err != nil {
+		return nil, err
+	}
+	challenge := ntlmChallenge(resp, scheme)
+	if resp.StatusCode != http.StatusUnauthorized || len(challenge) == 0 {
+		// the server did not continue the handshake
+		return resp, nil
+	}
+	drainBody(resp)
+
+	authenticate, err := ntlmssp.ProcessChallenge(challenge, user, s.Data.Password, domainNeeded)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not process ntlm challenge")
+	}
+	resp, err = conn.transport.RoundTrip(cloneRequest(req, body, scheme+" "+base64.StdEncoding.EncodeToString(authenticate)))
+	if err == nil && resp.StatusCode != http.StatusUnauthorized {
+		conn.authenticated.Store(true)
+	}
+	return resp, err
+}
+
+// conn returns the dedicated connection of the user to the host of the url
+func (s *NTLMAuthStrategy) conn(base http.RoundTripper, u *url.URL) (*ntlmConn, error) {
+	transport, ok := base.(*http.Transport)
+	if !ok {
+		return nil, errors.Errorf("ntlm auth can't be used with %T, only http/1.1 is supported", base)
+	}
+	key := ntlmConnKey{
+		ntlmTransportKey: ntlmTransportKey{base: transport, username: s.Data.Username, password: s.Data.Password},
+		host:             u.Scheme + "://" + strings.ToLower(u.Host),
+	}
+
+	ntlmPool.Lock()
+	defer ntlmPool.Unlock()
+	conn, ok := ntlmPool.conns[key]
+	if !ok {
+		dedicated, ok := ntlmPool.transports[key.ntlmTransportKey]
+		if !ok {
+			dedicated = dedicatedTransport(transport)
+			ntlmPool.transports[key.ntlmTransportKey] = dedicated
+		}
+		conn = &ntlmConn{transport: dedicated}
+		ntlmPool.conns[key] = conn
+	}
+	return conn, nil
+}
+
+// dedicatedTransport returns a transport with a single keep-alive http/1.1
+// connection per host inheriting the dialer, tls and proxy of the base.
+//
+// Other transports (http/3, alt-svc, h2c) can't keep the handshake on a
+// single http/1.1 connection so ntlm can't be used with them.
+func dedicatedTransport(base *http.Transport) *http.Transport {
+	dedicated := base.Clone()
+	dedicated.DisableKeepAlives = false
+	dedicated.MaxConnsPerHost = 1
+	dedicated.MaxIdleConnsPerHost = 1
+	dedicated.IdleConnTimeout = ntlmIdleConnTimeout
+	dedicated.ForceAttemptHTTP2 = false
+	dedicated.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	return dedicated
+}
+
+// closeNTLMIdleConnections closes the idle dedicated connections of the base
+func closeNTLMIdleConnections(base http.RoundTripper) {
+	ntlmPool.Lock()
+	defer ntlmPool.Unlock()
+	for key, transport := range ntlmPool.transports {
+		if key.base == base {
+			transport.CloseIdleConnections()
+		}
+	}
+}
+
+// ntlmScheme returns the scheme of the ntlm challenge of the response
+// preferring NTLM over Negotiate which also accepts ntlm messages.
+func ntlmScheme(resp *http.Response) string {
+	var scheme string
+	for _, value := range resp.Header.Values("WWW-Authenticate") {
+		for _, challenge := range strings.Split(value, ",") {
+			name, _, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+			switch {
+			case strings.EqualFold(name, "NTLM"):
+				return "NTLM"
+			case strings.EqualFold(name, "Negotiate"):
+				scheme = "Negotiate"
+			}
+		}
+	}
+	return scheme
+}
+
+// ntlmChallenge returns the decoded challenge message of the response
+func ntlmChallenge(resp *http.Response, scheme string) []byte {
+	for _, value := range resp.Header.Values("WWW-Authenticate") {
+		if !hasHeaderPrefix(value, scheme+" ") {
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value[len(scheme)+1:]))
+		if err == nil && len(data) > 0 {
+			return data
+		}
+	}
+	return nil
+}
+
+// readRequestBody reads the body of the request to send it again
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxNTLMBodySize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read request body")
+	}
+	if len(body) > maxNTLMBodySize {
+		return nil, errors.Errorf("request body is larger than %d bytes which can be sent with ntlm auth", maxNTLMBodySize)
+	}
+	return body, nil
+}
+
+// cloneRequest returns a copy of the request with the body
+// and the authorization header if not empty.
+func cloneRequest(req *http.Request, body []byte, authorization string) *http.Request {
+	cloned := req.Clone(req.Context())
+	if len(body) > 0 {
+		cloned.Body = io.NopCloser(bytes.NewReader(body))
+		cloned.ContentLength = int64(len(body))
+	} else if body != nil {
+		cloned.Body = http.NoBody
+		cloned.ContentLength = 0
+	}
+	if authorization != "" {
+		cloned.Header.Set("Authorization", authorization)
+	}
+	return cloned
+}
+
+// drainBody drains and closes the body of the response
+// so that the connection can be reused.
+func drainBody(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxNTLMBodySize))
+	resp.Body.Close()
+}
@@ -0,0 +1,267 @@
+package authx
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// ntlmServer is a stand-in server authenticating connections with ntlm
+type ntlmServer struct {
+	mu            sync.Mutex
+	challenged    map[string]bool
+	authenticated map[string]bool
+	handshakes    int
+	users         []string
+	// anonymous are the body sizes of requests of unauthenticated connections
+	anonymous []int64
+}
+
+func newNTLMServer() *ntlmServer {
+	return &ntlmServer{challenged: make(map[string]bool), authenticated: make(map[string]bool)}
+}
+
+func (n *ntlmServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	conn := r.RemoteAddr
+	authorization := r.Header.Get("Authorization")
+	switch {
+	case authorization == "" && n.authenticated[conn]:
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(append([]byte("ok "), body...))
+	case strings.HasPrefix(authorization, "NTLM "):
+		data, _ := base64.StdEncoding.DecodeString(authorization[5:])
+		if len(data) < 12 || !bytes.HasPrefix(data, []byte("NTLMSSP\x00")) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch binary.LittleEndian.Uint32(data[8:12]) {
+		case 1:
+			n.challenged[conn] = true
+			w.Header().Set("WWW-Authenticate", "NTLM "+base64.StdEncoding.EncodeToString(ntlmChallengeMessage()))
+			w.WriteHeader(http.StatusUnauthorized)
+		case 3:
+			// the authenticate message must be sent over the challenged connection
+			if !n.challenged[conn] {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			delete(n.challenged, conn)
+			n.authenticated[conn] = true
+			n.handshakes++
+			n.users = append(n.users, ntlmMessageField(data, 36))
+			body, _ := io.ReadAll(r.Body)
+			_, _ = w.Write(append([]byte("ok "), body...))
+		}
+	default:
+		n.anonymous = append(n.anonymous, r.ContentLength)
+		w.Header().Add("WWW-Authenticate", "Negotiate")
+		w.Header().Add("WWW-Authenticate", "NTLM")
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+}
+
+// ntlmChallengeMessage returns a challenge message of the CORP domain
+func ntlmChallengeMessage() []byte {
+	target := []byte{'C', 0, 'O', 0, 'R', 0, 'P', 0}
+	info := append([]byte{2, 0, byte(len(target)), 0}, target...)
+	info = append(info, 0, 0, 0, 0)
+
+	message := &bytes.Buffer{}
+	message.WriteString("NTLMSSP\x00")
+	_ = binary.Write(message, binary.LittleEndian, uint32(2))
+	_ = binary.Write(message, binary.LittleEndian, []uint16{uint16(len(target)), uint16(len(target))})
+	_ = binary.Write(message, binary.LittleEndian, uint32(48))
+	// unicode, ntlm, extended session security and target info flags
+	_ = binary.Write(message, binary.LittleEndian, uint32(0x00000001|0x00000200|0x00080000|0x00800000))
+	message.Write([]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	message.Write(make([]byte, 8))
+	_ = binary.Write(message, binary.LittleEndian, []uint16{uint16(len(info)), uint16(len(info))})
+	_ = binary.Write(message, binary.LittleEndian, uint32(48+len(target)))
+	message.Write(target)
+	message.Write(info)
+	return message.Bytes()
+}
+
+// ntlmMessageField returns the unicode field of the message at the offset
+func ntlmMessageField(data []byte, offset int) string {
+	length := int(binary.LittleEndian.Uint16(data[offset:]))
+	start := int(binary.LittleEndian.Uint32(data[offset+4:]))
+	field := &strings.Builder{}
+	for i := start; i < start+length && i < len(data); i += 2 {
+		field.WriteByte(data[i])
+	}
+	return field.String()
+}
+
+func TestNTLMAuthStrategy(t *testing.T) {
+	server := newNTLMServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	strategy := NewNTLMAuthStrategy(&Secret{Type: NTLMAuth, Username: `CORP\admin`, Password: "secret"})
+	client := &http.Client{Transport: NewTransport(&http.Transport{})}
+
+	send := func(method, body string) (int, string) {
+		req, err := http.NewRequest(method, ts.URL, strings.NewReader(body))
+		require.Nil(t, err)
+		strategy.Apply(req)
+		resp, err := client.Do(req)
+		require.Nil(t, err)
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		require.Nil(t, err)
+		return resp.StatusCode, string(data)
+	}
+
+	status, body := send(http.MethodPost, "data")
+	require.Equal(t, http.StatusOK, status, "could not authenticate connection")
+	require.Equal(t, "ok data", body, "could not send body after handshake")
+	require.Equal(t, []string{"admin"}, server.users, "could not send username without domain")
+	require.Equal(t, []int64{0}, server.anonymous, "could send body before handshake")
+
+	status, _ = send(http.MethodGet, "")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, 1, server.handshakes, "could not reuse authenticated connection")
+
+	var wg sync.WaitGroup
+	statuses := make(chan int, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+			strategy.Apply(req)
+			resp, err := client.Do(req)
+			if err != nil {
+				statuses <- 0
+				return
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			statuses <- resp.StatusCode
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+	for status := range statuses {
+		require.Equal(t, http.StatusOK, status, "could not send concurrent requests")
+	}
+}
+
+func TestNTLMAuthStrategyPool(t *testing.T) {
+	server := newNTLMServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	client := &http.Client{Transport: NewTransport(&http.Transport{})}
+
+	send := func(strategy *NTLMAuthStrategy) {
+		req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+		require.Nil(t, err)
+		strategy.Apply(req)
+		resp, err := client.Do(req)
+		require.Nil(t, err)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	// strategies of templates with the same user share the connection
+	send(NewNTLMAuthStrategy(&Secret{Type: NTLMAuth, Username: "admin", Password: "secret"}))
+	send(NewNTLMAuthStrategy(&Secret{Type: NTLMAuth, Username: "admin", Password: "secret"}))
+	require.Equal(t, 1, server.handshakes, "could not reuse connection of other strategy")
+
+	send(NewNTLMAuthStrategy(&Secret{Type: NTLMAuth, Username: "other", Password: "secret"}))
+	require.Equal(t, []string{"admin", "other"}, server.users, "could reuse connection of other user")
+}
+
+func TestNTLMAuthStrategyLargeBody(t *testing.T) {
+	ts := httptest.NewServer(newNTLMServer())
+	defer ts.Close()
+
+	strategy := NewNTLMAuthStrategy(&Secret{Type: NTLMAuth, Username: "admin", Password: "secret"})
+	req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(strings.Repeat("a", maxNTLMBodySize+1)))
+	require.Nil(t, err)
+	strategy.Apply(req)
+
+	_, err = NewTransport(&http.Transport{}).RoundTrip(req)
+	require.NotNil(t, err, "could send truncated body")
+}
+
+func TestNTLMAuthStrategyOtherHost(t *testing.T) {
+	server := newNTLMServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	other := newNTLMServer()
+	otherTs := httptest.NewServer(other)
+	defer otherTs.Close()
+
+	strategy := NewNTLMAuthStrategy(&Secret{Type: NTLMAuth, Username: "admin", Password: "secret"})
+	req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	require.Nil(t, err)
+	strategy.Apply(req)
+
+	// requests to other hosts like redirects are not authenticated
+	redirected, err := http.NewRequestWithContext(req.Context(), http.MethodGet, otherTs.URL, nil)
+	require.Nil(t, err)
+	resp, err := NewTransport(&http.Transport{}).RoundTrip(redirected)
+	require.Nil(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	require.Zero(t, other.handshakes, "could authenticate other host")
+}
+
+func TestNTLMAuthStrategyUnsupportedTransport(t *testing.T) {
+	ts := httptest.NewServer(newNTLMServer())
+	defer ts.Close()
+
+	strategy := NewNTLMAuthStrategy(&Secret{Type: NTLMAuth, Username: "admin", Password: "secret"})
+	req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	require.Nil(t, err)
+	strategy.Apply(req)
+
+	// transports other than http/1.1 like h2c or http/3 can't be authenticated
+	base := roundTripperFunc(http.DefaultTransport.RoundTrip)
+	_, err = NewTransport(base).RoundTrip(req)
+	require.NotNil(t, err, "could send ntlm request with unsupported transport")
+}
+
+func TestNegotiateValidate(t *testing.T) {
+	auth, err := GetAuthDataFromYAML([]byte(`
+static:
+  - type: negotiate
+    domains: [intranet.corp.local]
+    username: admin@CORP.LOCAL
+    password: secret
+    kdc: dc.corp.local:88
+  - type: ntlm
+    domains: [sharepoint.corp.local]
+    username: CORP\admin
+    password: secret
+`))
+	require.Nil(t, err, "could not parse negotiate and ntlm secrets")
+	require.IsType(t, &NegotiateAuthStrategy{}, auth.Secrets[0].GetStrategy())
+	require.IsType(t, &NTLMAuthStrategy{}, auth.Secrets[1].GetStrategy())
+
+	_, err = GetAuthDataFromYAML([]byte("static:\n  - type: negotiate\n    domains: [example.com]\n    username: admin\n    password: secret\n"))
+	require.NotNil(t, err, "could parse negotiate secret without realm")
+
+	require.Equal(t, "[libdefaults]\ndefault_realm = CORP.LOCAL\n[realms]\nCORP.LOCAL = {\n\tkdc = dc.corp.local:88\n}\n", krb5Config("CORP.LOCAL", "dc.corp.local:88"))
+}
+
+// roundTripperFunc is a round tripper which is not a *http.Transport
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,289 @@
+package authx
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+const (
+	// ClientCredentialsGrant is the oauth2 client credentials grant
+	ClientCredentialsGrant = "client_credentials"
+	// PasswordGrant is the oauth2 resource owner password grant
+	PasswordGrant = "password"
+	// RefreshTokenGrant is the oauth2 refresh token grant
+	RefreshTokenGrant = "refresh_token"
+)
+
+// tokenExpiryDelta is the time before the expiry of tokens they are renewed at
+const tokenExpiryDelta = 30 * time.Second
+
+// OAuth2 contains the configuration of an oauth2 token endpoint
+type OAuth2 struct {
+	// TokenURL is the url of the token endpoint
+	TokenURL string `yaml:"token-url"`
+	// GrantType is the grant used to obtain tokens
+	GrantType string `yaml:"grant-type"`
+	// ClientID is the id of the client
+	ClientID string `yaml:"client-id"`
+	// ClientSecret is the secret of the client
+	ClientSecret string `yaml:"client-secret"`
+	// ClientAuth is how client credentials are sent. The credentials
+	// are sent as basic auth by default or as form parameters with "body".
+	ClientAuth string `yaml:"client-auth"`
+	// Scopes are the scopes requested for tokens
+	Scopes []string `yaml:"scopes"`
+	// Username is the username of the password grant
+	Username string `yaml:"username"`
+	// Password is the password of the password grant
+	Password string `yaml:"password"`
+	// RefreshToken is the refresh token of the refresh token grant
+	RefreshToken string `yaml:"refresh-token"`
+	// Params are additional parameters of token requests like audience
+	Params []KV `yaml:"params"`
+}
+
+// Validate validates the oauth2 configuration
+func (o *OAuth2) Validate() error {
+	if o.TokenURL == "" {
+		return errors.New("token-url is required for oauth2 auth")
+	}
+	if _, err := url.Parse(o.TokenURL); err != nil {
+		return errors.Wrap(err, "invalid token-url")
+	}
+	if o.GrantType == "" {
+		o.GrantType = ClientCredentialsGrant
+	}
+	switch o.GrantType {
+	case ClientCredentialsGrant:
+		if o.ClientID == "" {
+			return errors.New("client-id is required for client_credentials grant")
+		}
+	case PasswordGrant:
+		if o.Username == "" || o.Password == "" {
+			return errors.New("username and password are required for password grant")
+		}
+	case RefreshTokenGrant:
+		if o.RefreshToken == "" {
+			return errors.New("refresh-token is required for refresh_token grant")
+		}
+	default:
+		return errors.Errorf("unsupported grant-type %q", o.GrantType)
+	}
+	if o.ClientAuth != "" && o.ClientAuth != "header" && o.ClientAuth != "body" {
+		return errors.Errorf("unsupported client-auth %q", o.ClientAuth)
+	}
+	return nil
+}
+
+// Values returns the secret values of the configuration
+func (o *OAuth2) Values() []string {
+	var values []string
+	for _, value := range []string{o.ClientSecret, o.Password, o.RefreshToken} {
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// tokenResponse is the response of a token endpoint
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	Error        string `json:"error"`
+	Description  string `json:"error_description"`
+}
+
+// OAuth2AuthStrategy attaches access tokens obtained from a token
+// endpoint as bearer tokens. Tokens are cached until they expire.
+type OAuth2AuthStrategy struct {
+	Data *Secret
+
+	mu           sync.Mutex
+	client       *http.Client
+	accessToken  string
+	refreshToken string
+	issuedAt     time.Time
+	expiry       time.Time
+	issued       []string
+}
+
+// NewOAuth2AuthStrategy creates a new oauth2 auth strategy
+func NewOAuth2AuthStrategy(data *Secret) *OAuth2AuthStrategy {
+	return &OAuth2AuthStrategy{Data: data, client: http.DefaultClient, refreshToken: data.OAuth2.RefreshToken}
+}
+
+// SetHTTPClient sets the client used for token requests
+func (s *OAuth2AuthStrategy) SetHTTPClient(client *http.Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.client = client
+}
+
+// Apply applies the oauth2 auth strategy to the request
+func (s *OAuth2AuthStrategy) Apply(req *http.Request) {
+	token, err := s.Token()
+	if err != nil {
+		gologger.Warning().Msgf("Could not get oauth2 token for %s: %s\n", req.URL.Host, err)
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+}
+
+// ApplyOnRR applies the oauth2 auth strategy to the retryable request
+func (s *OAuth2AuthStrategy) ApplyOnRR(req *retryablehttp.Request) {
+	s.Apply(req.Request)
+}
+
+// Variables returns the access token
+func (s *OAuth2AuthStrategy) Variables() map[string]interface{} {
+	token, err := s.Token()
+	if err != nil {
+		return nil
+	}
+	return map[string]interface{}{"token": token}
+}
+
+// Challenge discards the cached token when it is rejected by the server.
+// Tokens rejected right after being issued are not renewed as the
+// rejection is not caused by the expiry of the token.
+func (s *OAuth2AuthStrategy) Challenge(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusUnauthorized || resp.Request == nil {
+		return false
+	}
+	rejected := strings.TrimPrefix(resp.Request.Header.Get("Authorization"), "Bearer ")
+	if rejected == "" {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.accessToken != rejected {
+		// the token was already renewed
+		return true
+	}
+	if time.Since(s.issuedAt) < minRefreshInterval {
+		return false
+	}
+	s.accessToken = ""
+	return true
+}
+
+// RuntimeValues returns the access and refresh tokens issued during the scan
+func (s *OAuth2AuthStrategy) RuntimeValues() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.issued...)
+}
+
+// Token returns the cached access token requesting a new
+// one from the token endpoint if it is missing or expired.
+func (s *OAuth2AuthStrategy) Token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.accessToken != "" && (s.expiry.IsZero() || time.Now().Add(tokenExpiryDelta).Before(s.expiry)) {
+		return s.accessToken, nil
+	}
+	config := s.Data.OAuth2
+
+	var resp *tokenResponse
+	var err error
+	// renew with the refresh token of previous tokens if available
+	if s.refreshToken != "" && config.GrantType != RefreshTokenGrant {
+		resp, err = s.requestToken(RefreshTokenGrant)
+	}
+	if resp == nil {
+		resp, err = s.requestToken(config.GrantType)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	s.accessToken = resp.AccessToken
+	s.issuedAt = time.Now()
+	s.expiry = time.Time{}
+	if resp.ExpiresIn > 0 {
+		s.expiry = time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+	}
+	s.issued = append(s.issued, resp.AccessToken)
+	if resp.RefreshToken != "" {
+		s.refreshToken = resp.RefreshToken
+		s.issued = append(s.issued, resp.RefreshToken)
+	}
+	return s.accessToken, nil
+}
+
+// requestToken requests a token from the token endpoint with the grant
+func (s *OAuth2AuthStrategy) requestToken(grantType string) (*tokenResponse, error) {
+	config := s.Data.OAuth2
+
+	form := url.Values{}
+	form.Set("grant_type", grantType)
+	switch grantType {
+	case PasswordGrant:
+		form.Set("username", config.Username)
+		form.Set("password", config.Password)
+	case RefreshTokenGrant:
+		form.Set("refresh_token", s.refreshToken)
+	}
+	if len(config.Scopes) > 0 {
+		form.Set("scope", strings.Join(config.Scopes, " "))
+	}
+	for _, param := range config.Params {
+		form.Set(param.Key, param.Value)
+	}
+	if config.ClientAuth == "body" {
+		form.Set("client_id", config.ClientID)
+		if config.ClientSecret != "" {
+			form.Set("client_secret", config.ClientSecret)
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodPost, config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create token request")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if config.ClientAuth != "body" && config.ClientID != "" {
+		req.SetBasicAuth(url.QueryEscape(config.ClientID), url.QueryEscape(config.ClientSecret))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not send token request")
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read token response")
+	}
+	token := &tokenResponse{}
+	if err := json.Unmarshal(body, token); err != nil {
+		return nil, errors.Wrapf(err, "could not decode token response with status %d", resp.StatusCode)
+	}
+	if token.Error != "" {
+		return nil, errors.Errorf("token endpoint returned error %s: %s", token.Error, token.Description)
+	}
+	if resp.StatusCode != http.StatusOK || token.AccessToken == "" {
+		return nil, errors.Errorf("token endpoint returned no access token with status %d", resp.StatusCode)
+	}
+	if token.TokenType != "" && !strings.EqualFold(token.TokenType, "bearer") {
+		return nil, errors.Errorf("unsupported token type %q", token.TokenType)
+	}
+	return token, nil
+}
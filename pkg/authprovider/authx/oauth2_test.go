@@ -0,0 +1,179 @@
+package authx
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// tokenServer is a stand-in oauth2 token endpoint
+type tokenServer struct {
+	mu        sync.Mutex
+	requests  []map[string]string
+	expiresIn int64
+}
+
+func (t *tokenServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	request := map[string]string{}
+	for key := range r.PostForm {
+		request[key] = r.PostForm.Get(key)
+	}
+	if user, pass, ok := r.BasicAuth(); ok {
+		request["basic"] = user + ":" + pass
+	}
+
+	t.mu.Lock()
+	t.requests = append(t.requests, request)
+	count := len(t.requests)
+	t.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if request["grant_type"] == PasswordGrant && request["password"] != "secret" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant", "error_description": "bad credentials"})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token":  fmt.Sprintf("access-%d", count),
+		"refresh_token": fmt.Sprintf("refresh-%d", count),
+		"token_type":    "Bearer",
+		"expires_in":    t.expiresIn,
+	})
+}
+
+func TestOAuth2Validate(t *testing.T) {
+	auth, err := GetAuthDataFromYAML([]byte(`
+static:
+  - type: oauth2
+    domains: [api.example.com]
+    oauth2:
+      token-url: https://auth.example.com/token
+      client-id: scanner
+      client-secret: s3cr3t
+      scopes: [read, write]
+`))
+	require.Nil(t, err, "could not parse oauth2 secret")
+	require.Equal(t, ClientCredentialsGrant, auth.Secrets[0].OAuth2.GrantType, "could not set default grant")
+	require.IsType(t, &OAuth2AuthStrategy{}, auth.Secrets[0].GetStrategy())
+	require.Equal(t, []string{"s3cr3t"}, auth.Secrets[0].Values())
+
+	_, err = GetAuthDataFromYAML([]byte("static:\n  - type: oauth2\n    domains: [example.com]\n"))
+	require.NotNil(t, err, "could parse oauth2 secret without configuration")
+
+	_, err = GetAuthDataFromYAML([]byte("static:\n  - type: oauth2\n    domains: [example.com]\n    oauth2:\n      token-url: https://example.com/token\n      grant-type: password\n      username: admin\n"))
+	require.NotNil(t, err, "could parse password grant without password")
+}
+
+func TestOAuth2ClientCredentials(t *testing.T) {
+	server := &tokenServer{expiresIn: 3600}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	secret := &Secret{Type: OAuth2Auth, OAuth2: &OAuth2{TokenURL: ts.URL, ClientID: "scanner", ClientSecret: "s3cr3t", Scopes: []string{"read", "write"}}}
+	require.Nil(t, secret.OAuth2.Validate())
+	strategy := NewOAuth2AuthStrategy(secret)
+	strategy.SetHTTPClient(ts.Client())
+
+	for i := 0; i < 3; i++ {
+		req, err := http.NewRequest(http.MethodGet, "https://api.example.com/", nil)
+		require.Nil(t, err)
+		strategy.Apply(req)
+		require.Equal(t, "Bearer access-1", req.Header.Get("Authorization"), "could not apply access token")
+	}
+	require.Len(t, server.requests, 1, "could not cache access token")
+	require.Equal(t, map[string]string{"grant_type": "client_credentials", "scope": "read write", "basic": "scanner:s3cr3t"}, server.requests[0])
+	require.ElementsMatch(t, []string{"access-1", "refresh-1"}, strategy.RuntimeValues())
+	require.Equal(t, map[string]interface{}{"token": "access-1"}, strategy.Variables())
+}
+
+func TestOAuth2Expiry(t *testing.T) {
+	// tokens expiring within the expiry delta are renewed on every use
+	server := &tokenServer{expiresIn: 10}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	secret := &Secret{Type: OAuth2Auth, OAuth2: &OAuth2{TokenURL: ts.URL, GrantType: PasswordGrant, Username: "admin", Password: "secret", ClientID: "scanner", ClientAuth: "body"}}
+	require.Nil(t, secret.OAuth2.Validate())
+	strategy := NewOAuth2AuthStrategy(secret)
+	strategy.SetHTTPClient(ts.Client())
+
+	token, err := strategy.Token()
+	require.Nil(t, err)
+	require.Equal(t, "access-1", token)
+	require.Equal(t, map[string]string{"grant_type": "password", "username": "admin", "password": "secret", "client_id": "scanner"}, server.requests[0])
+
+	token, err = strategy.Token()
+	require.Nil(t, err)
+	require.Equal(t, "access-2", token, "could not renew expired token")
+	require.Equal(t, map[string]string{"grant_type": "refresh_token", "refresh_token": "refresh-1", "client_id": "scanner"}, server.requests[1], "could not renew token with refresh token")
+}
+
+func TestOAuth2RefreshTokenGrant(t *testing.T) {
+	server := &tokenServer{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	secret := &Secret{Type: OAuth2Auth, OAuth2: &OAuth2{TokenURL: ts.URL, GrantType: RefreshTokenGrant, RefreshToken: "initial"}}
+	require.Nil(t, secret.OAuth2.Validate())
+	strategy := NewOAuth2AuthStrategy(secret)
+	strategy.SetHTTPClient(ts.Client())
+
+	token, err := strategy.Token()
+	require.Nil(t, err)
+	require.Equal(t, "access-1", token)
+	require.Equal(t, map[string]string{"grant_type": "refresh_token", "refresh_token": "initial"}, server.requests[0])
+}
+
+func TestOAuth2Errors(t *testing.T) {
+	server := &tokenServer{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	secret := &Secret{Type: OAuth2Auth, OAuth2: &OAuth2{TokenURL: ts.URL, GrantType: PasswordGrant, Username: "admin", Password: "wrong"}}
+	require.Nil(t, secret.OAuth2.Validate())
+	strategy := NewOAuth2AuthStrategy(secret)
+	strategy.SetHTTPClient(ts.Client())
+
+	_, err := strategy.Token()
+	require.ErrorContains(t, err, "invalid_grant", "could not return token endpoint error")
+
+	req, err := http.NewRequest(http.MethodGet, "https://api.example.com/", nil)
+	require.Nil(t, err)
+	strategy.Apply(req)
+	require.Empty(t, req.Header.Get("Authorization"), "could apply missing token")
+}
+
+func TestOAuth2Challenge(t *testing.T) {
+	server := &tokenServer{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	secret := &Secret{Type: OAuth2Auth, OAuth2: &OAuth2{TokenURL: ts.URL, ClientID: "scanner"}}
+	require.Nil(t, secret.OAuth2.Validate())
+	strategy := NewOAuth2AuthStrategy(secret)
+	strategy.SetHTTPClient(ts.Client())
+
+	req, err := http.NewRequest(http.MethodGet, "https://api.example.com/", nil)
+	require.Nil(t, err)
+	strategy.Apply(req)
+	resp := &http.Response{StatusCode: http.StatusUnauthorized, Header: http.Header{}, Request: req}
+	require.False(t, strategy.Challenge(resp), "could renew token rejected right after being issued")
+
+	strategy.issuedAt = time.Now().Add(-minRefreshInterval)
+	require.True(t, strategy.Challenge(resp), "could not discard rejected token")
+	strategy.Apply(req)
+	require.Equal(t, "Bearer access-2", req.Header.Get("Authorization"), "could not apply renewed token")
+
+	resp.StatusCode = http.StatusOK
+	require.False(t, strategy.Challenge(resp))
+}
@@ -0,0 +1,268 @@
+package authx
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// AuthStrategy is an authentication strategy applied to requests
+type AuthStrategy interface {
+	// Apply applies the strategy to the request
+	Apply(*http.Request)
+	// ApplyOnRR applies the strategy to the retryable request
+	ApplyOnRR(*retryablehttp.Request)
+	// Variables returns the credentials exposed as template variables
+	// to protocols not sending http requests.
+	Variables() map[string]interface{}
+}
+
+// ChallengeStrategy is implemented by strategies requiring a challenge
+// from the server before credentials can be sent.
+type ChallengeStrategy interface {
+	// Challenge consumes the challenge of an unauthorized response
+	// and returns true if the request should be retried.
+	Challenge(*http.Response) bool
+}
+
+// RuntimeStrategy is implemented by strategies obtaining secrets
+// during the scan like access tokens.
+type RuntimeStrategy interface {
+	// RuntimeValues returns the secrets obtained during the scan
+	RuntimeValues() []string
+	// SetHTTPClient sets the client used to obtain the secrets
+	SetHTTPClient(*http.Client)
+}
+
+// RoundTripStrategy is implemented by strategies authenticating connections
+// with a handshake of multiple requests like NTLM. Requests the strategy is
+// applied to are sent by the strategy through the Transport of the client.
+type RoundTripStrategy interface {
+	// RoundTrip sends the request with the base round tripper
+	// authenticating the connection if required.
+	RoundTrip(base http.RoundTripper, req *http.Request) (*http.Response, error)
+}
+
+// BasicAuthStrategy sets the basic authorization header
+type BasicAuthStrategy struct {
+	Data *Secret
+}
+
+// NewBasicAuthStrategy creates a new basic auth strategy
+func NewBasicAuthStrategy(data *Secret) *BasicAuthStrategy {
+	return &BasicAuthStrategy{Data: data}
+}
+
+// Apply applies the basic auth strategy to the request
+func (s *BasicAuthStrategy) Apply(req *http.Request) {
+	req.SetBasicAuth(s.Data.Username, s.Data.Password)
+}
+
+// ApplyOnRR applies the basic auth strategy to the retryable request
+func (s *BasicAuthStrategy) ApplyOnRR(req *retryablehttp.Request) {
+	req.SetBasicAuth(s.Data.Username, s.Data.Password)
+}
+
+// Variables returns the username and password
+func (s *BasicAuthStrategy) Variables() map[string]interface{} {
+	return map[string]interface{}{"username": s.Data.Username, "password": s.Data.Password}
+}
+
+// BearerTokenAuthStrategy sets the bearer authorization header
+type BearerTokenAuthStrategy struct {
+	Data *Secret
+}
+
+// NewBearerTokenAuthStrategy creates a new bearer token auth strategy
+func NewBearerTokenAuthStrategy(data *Secret) *BearerTokenAuthStrategy {
+	return &BearerTokenAuthStrategy{Data: data}
+}
+
+// Apply applies the bearer token auth strategy to the request
+func (s *BearerTokenAuthStrategy) Apply(req *http.Request) {
+	req.Header.Set("Authorization", "Bearer "+s.Data.Token)
+}
+
+// ApplyOnRR applies the bearer token auth strategy to the retryable request
+func (s *BearerTokenAuthStrategy) ApplyOnRR(req *retryablehttp.Request) {
+	s.Apply(req.Request)
+}
+
+// Variables returns the token
+func (s *BearerTokenAuthStrategy) Variables() map[string]interface{} {
+	return map[string]interface{}{"token": s.Data.Token}
+}
+
+// HeadersAuthStrategy sets the headers of the secret
+type HeadersAuthStrategy struct {
+	Data *Secret
+}
+
+// NewHeadersAuthStrategy creates a new headers auth strategy
+func NewHeadersAuthStrategy(data *Secret) *HeadersAuthStrategy {
+	return &HeadersAuthStrategy{Data: data}
+}
+
+// Apply applies the headers auth strategy to the request
+func (s *HeadersAuthStrategy) Apply(req *http.Request) {
+	for _, header := range s.Data.Headers {
+		req.Header.Set(header.Key, header.Value)
+	}
+}
+
+// ApplyOnRR applies the headers auth strategy to the retryable request
+func (s *HeadersAuthStrategy) ApplyOnRR(req *retryablehttp.Request) {
+	s.Apply(req.Request)
+}
+
+// Variables returns no variables as header names are not valid identifiers
+func (s *HeadersAuthStrategy) Variables() map[string]interface{} {
+	return nil
+}
+
+// CookiesAuthStrategy sets the cookies of the secret replacing
+// existing cookies with the same name.
+type CookiesAuthStrategy struct {
+	Data *Secret
+}
+
+// NewCookiesAuthStrategy creates a new cookies auth strategy
+func NewCookiesAuthStrategy(data *Secret) *CookiesAuthStrategy {
+	return &CookiesAuthStrategy{Data: data}
+}
+
+// Apply applies the cookies auth strategy to the request. The Cookie
+// header is edited as text so that other cookies, like fuzzing payloads,
+// are sent byte for byte.
+func (s *CookiesAuthStrategy) Apply(req *http.Request) {
+	pairs := make([]KV, 0, len(s.Data.Cookies))
+	for _, cookie := range s.Data.Cookies {
+		pairs = append(pairs, KV{Key: cookie.Key, Value: cookie.Key + "=" + cookie.Value})
+	}
+	values := req.Header.Values("Cookie")
+	if len(values) == 0 {
+		values = []string{""}
+	}
+	values = setPairs(values, "; ", pairs, func(pair string) string {
+		name, _, _ := strings.Cut(pair, "=")
+		return strings.TrimSpace(name)
+	})
+	req.Header["Cookie"] = values
+}
+
+// ApplyOnRR applies the cookies auth strategy to the retryable request
+func (s *CookiesAuthStrategy) ApplyOnRR(req *retryablehttp.Request) {
+	s.Apply(req.Request)
+}
+
+// Variables returns no variables for cookies
+func (s *CookiesAuthStrategy) Variables() map[string]interface{} {
+	return nil
+}
+
+// QueryAuthStrategy sets the query parameters of the secret
+type QueryAuthStrategy struct {
+	Data *Secret
+}
+
+// NewQueryAuthStrategy creates a new query auth strategy
+func NewQueryAuthStrategy(data *Secret) *QueryAuthStrategy {
+	return &QueryAuthStrategy{Data: data}
+}
+
+// Apply applies the query auth strategy to the request. The raw query is
+// edited as text so that other parameters are sent as they were written.
+func (s *QueryAuthStrategy) Apply(req *http.Request) {
+	pairs := make([]KV, 0, len(s.Data.Params))
+	for _, param := range s.Data.Params {
+		pairs = append(pairs, KV{Key: param.Key, Value: url.QueryEscape(param.Key) + "=" + url.QueryEscape(param.Value)})
+	}
+	values := setPairs([]string{req.URL.RawQuery}, "&", pairs, func(pair string) string {
+		name, _, _ := strings.Cut(pair, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			return unescaped
+		}
+		return name
+	})
+	req.URL.RawQuery = values[0]
+}
+
+// ApplyOnRR applies the query auth strategy to the retryable request. The
+// raw query is edited as text and the parsed parameters of the request are
+// updated without encoding the query again.
+func (s *QueryAuthStrategy) ApplyOnRR(req *retryablehttp.Request) {
+	s.Apply(req.Request)
+	if req.URL == nil {
+		return
+	}
+	req.URL.RawQuery = req.Request.URL.RawQuery
+	if req.URL.Params != nil {
+		for _, param := range s.Data.Params {
+			req.URL.Params.Update(param.Key, []string{param.Value})
+		}
+	}
+}
+
+// Variables returns no variables for query parameters
+func (s *QueryAuthStrategy) Variables() map[string]interface{} {
+	return nil
+}
+
+// hasHeaderPrefix returns true if the header value starts with the
+// prefix ignoring case.
+func hasHeaderPrefix(value, prefix string) bool {
+	return len(value) >= len(prefix) && strings.EqualFold(value[:len(prefix)], prefix)
+}
+
+// setPairs replaces the pairs of the values named like one of the given
+// pairs, whose Key is the name and Value the written pair. Pairs not found
+// are appended to the last value. Pairs of the values are separated by sep
+// and other pairs are kept as they are.
+func setPairs(values []string, sep string, pairs []KV, name func(pair string) string) []string {
+	set := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		set[pair.Key] = pair.Value
+	}
+	written := make(map[string]struct{}, len(pairs))
+	separator := strings.TrimSpace(sep)
+
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		var parts []string
+		if value != "" {
+			parts = strings.Split(value, separator)
+		}
+		kept := parts[:0]
+		for _, part := range parts {
+			key := name(part)
+			replacement, ok := set[key]
+			if !ok {
+				kept = append(kept, part)
+				continue
+			}
+			if _, ok := written[key]; ok {
+				continue
+			}
+			written[key] = struct{}{}
+			// keep the whitespace following the separator
+			kept = append(kept, part[:len(part)-len(strings.TrimLeft(part, " "))]+replacement)
+		}
+		result = append(result, strings.Join(kept, separator))
+	}
+
+	last := result[len(result)-1]
+	for _, pair := range pairs {
+		if _, ok := written[pair.Key]; ok {
+			continue
+		}
+		written[pair.Key] = struct{}{}
+		if last != "" {
+			last += sep
+		}
+		last += pair.Value
+	}
+	result[len(result)-1] = last
+	return result
+}
@@ -0,0 +1,52 @@
+package authx
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// roundTripKey is the context key of the round trip strategy of a request
+type roundTripKey struct{}
+
+// roundTrip is the round trip strategy applied to requests of a host
+type roundTrip struct {
+	strategy RoundTripStrategy
+	host     string
+}
+
+// withRoundTripStrategy returns the request with the strategy attached to
+// its context. The strategy is only used for requests to the same host
+// so that redirects to other hosts are not authenticated.
+func withRoundTripStrategy(req *http.Request, strategy RoundTripStrategy) *http.Request {
+	ctx := context.WithValue(req.Context(), roundTripKey{}, &roundTrip{strategy: strategy, host: req.URL.Host})
+	return req.WithContext(ctx)
+}
+
+// Transport is a round tripper sending requests with the round trip
+// strategy attached to them or with the base round tripper otherwise.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// NewTransport creates a new transport wrapping the base round tripper
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{Base: base}
+}
+
+// RoundTrip sends the request
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if value, ok := req.Context().Value(roundTripKey{}).(*roundTrip); ok && strings.EqualFold(value.host, req.URL.Host) {
+		return value.strategy.RoundTrip(t.Base, req)
+	}
+	return t.Base.RoundTrip(req)
+}
+
+// CloseIdleConnections closes the idle connections of the base round tripper
+// and of the dedicated ntlm transports created from it.
+func (t *Transport) CloseIdleConnections() {
+	closeNTLMIdleConnections(t.Base)
+	if closer, ok := t.Base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
@@ -0,0 +1,148 @@
+package authprovider
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	fileutil "github.com/khulnasoft-lab/utils/file"
+	urlutil "github.com/khulnasoft-lab/utils/url"
+	"github.com/khulnasoft-lab/vulmap/pkg/authprovider/authx"
+)
+
+// redactedValue is the value secrets are replaced with
+const redactedValue = "[REDACTED]"
+
+// FileAuthProvider is an auth provider for a secrets file
+type FileAuthProvider struct {
+	Path       string
+	store      *authx.Authx
+	strategies []authx.AuthStrategy
+	replacer   *strings.Replacer
+}
+
+// NewFileAuthProvider creates a new auth provider from a secrets file.
+// The client is used by secrets requesting tokens if it is not nil.
+func NewFileAuthProvider(path string, client *http.Client) (AuthProvider, error) {
+	store, err := authx.GetAuthDataFromFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not load secrets file %s", path)
+	}
+	if len(store.Secrets) == 0 && len(store.Dynamic) == 0 {
+		return nil, ErrNoSecrets
+	}
+	// login templates are relative to the secrets file if not found
+	for _, dynamic := range store.Dynamic {
+		if filepath.IsAbs(dynamic.TemplatePath) || fileutil.FileExists(dynamic.TemplatePath) {
+			continue
+		}
+		if relative := filepath.Join(filepath.Dir(path), dynamic.TemplatePath); fileutil.FileExists(relative) {
+			dynamic.TemplatePath = relative
+		}
+	}
+	provider := newFileAuthProvider(path, store)
+	if client != nil {
+		for _, strategy := range provider.strategies {
+			if runtime, ok := strategy.(authx.RuntimeStrategy); ok {
+				runtime.SetHTTPClient(client)
+			}
+		}
+	}
+	return provider, nil
+}
+
+func newFileAuthProvider(path string, store *authx.Authx) *FileAuthProvider {
+	provider := &FileAuthProvider{Path: path, store: store}
+	var values []string
+	for i := range store.Secrets {
+		secret := &store.Secrets[i]
+		provider.strategies = append(provider.strategies, secret.GetStrategy())
+		values = append(values, redactedValues(secret)...)
+	}
+	// longer values are replaced first in case secrets overlap
+	sort.SliceStable(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+	replacements := make([]string, 0, len(values)*2)
+	for _, value := range values {
+		replacements = append(replacements, value, redactedValue)
+	}
+	provider.replacer = strings.NewReplacer(replacements...)
+	return provider
+}
+
+// redactedValues returns the secret values along with their encoded forms
+func redactedValues(secret *authx.Secret) []string {
+	var values []string
+	if secret.Type == authx.BasicAuth {
+		values = append(values, base64.StdEncoding.EncodeToString([]byte(secret.Username+":"+secret.Password)))
+	}
+	return withEscapedValues(values, secret.Values())
+}
+
+// withEscapedValues appends the values along with their url escaped forms
+func withEscapedValues(values, secrets []string) []string {
+	for _, value := range secrets {
+		values = append(values, value)
+		if escaped := url.QueryEscape(value); escaped != value {
+			values = append(values, escaped)
+		}
+	}
+	return values
+}
+
+// LookupAddr looks up the auth strategies of a host or host:port address
+func (f *FileAuthProvider) LookupAddr(addr string) []authx.AuthStrategy {
+	var strategies []authx.AuthStrategy
+	for i := range f.store.Secrets {
+		if f.store.Secrets[i].MatchesHost(addr) {
+			strategies = append(strategies, f.strategies[i])
+		}
+	}
+	for _, dynamic := range f.store.Dynamic {
+		if dynamic.MatchesHost(addr) {
+			strategies = append(strategies, authx.NewDynamicAuthStrategy(dynamic, addr))
+		}
+	}
+	return strategies
+}
+
+// LookupURL looks up the auth strategies of the url
+func (f *FileAuthProvider) LookupURL(u *url.URL) []authx.AuthStrategy {
+	return f.LookupAddr(u.Host)
+}
+
+// LookupURLX looks up the auth strategies of the urlutil url
+func (f *FileAuthProvider) LookupURLX(u *urlutil.URL) []authx.AuthStrategy {
+	return f.LookupAddr(u.Host)
+}
+
+// Redact replaces the secret values in data
+func (f *FileAuthProvider) Redact(data string) string {
+	data = f.replacer.Replace(data)
+	// values of dynamic secrets are only known after the login
+	for _, dynamic := range f.store.Dynamic {
+		for _, value := range withEscapedValues(nil, dynamic.Values()) {
+			data = strings.ReplaceAll(data, value, redactedValue)
+		}
+	}
+	// tokens are only known after they are issued
+	for _, strategy := range f.strategies {
+		if runtime, ok := strategy.(authx.RuntimeStrategy); ok {
+			for _, value := range runtime.RuntimeValues() {
+				data = strings.ReplaceAll(data, value, redactedValue)
+			}
+		}
+	}
+	return data
+}
+
+// DynamicSecrets returns the secrets requiring a login template
+func (f *FileAuthProvider) DynamicSecrets() []*authx.Dynamic {
+	return f.store.Dynamic
+}
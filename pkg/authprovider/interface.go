@@ -0,0 +1,56 @@
+// Package authprovider provides the secrets of authenticated
+// scans for the targets matching the domains of the secrets.
+package authprovider
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+
+	urlutil "github.com/khulnasoft-lab/utils/url"
+	"github.com/khulnasoft-lab/vulmap/pkg/authprovider/authx"
+)
+
+// ErrNoSecrets is returned when no secrets are provided to the auth provider
+var ErrNoSecrets = errors.New("no secrets provided")
+
+// AuthProvider is an interface for auth providers
+// It returns the auth strategies of the target
+type AuthProvider interface {
+	// LookupAddr looks up the auth strategies of a host or host:port address
+	LookupAddr(string) []authx.AuthStrategy
+	// LookupURL looks up the auth strategies of the url
+	LookupURL(*url.URL) []authx.AuthStrategy
+	// LookupURLX looks up the auth strategies of the urlutil url
+	LookupURLX(*urlutil.URL) []authx.AuthStrategy
+	// Redact replaces the secret values in data
+	Redact(string) string
+	// DynamicSecrets returns the secrets requiring a login template
+	DynamicSecrets() []*authx.Dynamic
+}
+
+// AuthProviderOptions contains the options of the auth provider
+type AuthProviderOptions struct {
+	// SecretsFiles are the paths of the secrets files
+	SecretsFiles []string
+	// HTTPClient is the client used to request tokens of secrets
+	// like oauth2 access tokens. The default client is used if nil.
+	HTTPClient *http.Client
+}
+
+// NewAuthProvider creates a new auth provider from the options
+func NewAuthProvider(options *AuthProviderOptions) (AuthProvider, error) {
+	if len(options.SecretsFiles) == 0 {
+		return nil, ErrNoSecrets
+	}
+	var providers []AuthProvider
+	for _, file := range options.SecretsFiles {
+		provider, err := NewFileAuthProvider(file, options.HTTPClient)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+	}
+	return NewMultiAuthProvider(providers...), nil
+}
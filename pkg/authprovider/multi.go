@@ -0,0 +1,54 @@
+package authprovider
+
+import (
+	"net/url"
+
+	urlutil "github.com/khulnasoft-lab/utils/url"
+	"github.com/khulnasoft-lab/vulmap/pkg/authprovider/authx"
+)
+
+// MultiAuthProvider combines the secrets of multiple auth providers
+type MultiAuthProvider struct {
+	Providers []AuthProvider
+}
+
+// NewMultiAuthProvider creates a new multi auth provider
+func NewMultiAuthProvider(providers ...AuthProvider) AuthProvider {
+	return &MultiAuthProvider{Providers: providers}
+}
+
+// LookupAddr looks up the auth strategies of a host or host:port address
+func (m *MultiAuthProvider) LookupAddr(addr string) []authx.AuthStrategy {
+	var strategies []authx.AuthStrategy
+	for _, provider := range m.Providers {
+		strategies = append(strategies, provider.LookupAddr(addr)...)
+	}
+	return strategies
+}
+
+// LookupURL looks up the auth strategies of the url
+func (m *MultiAuthProvider) LookupURL(u *url.URL) []authx.AuthStrategy {
+	return m.LookupAddr(u.Host)
+}
+
+// LookupURLX looks up the auth strategies of the urlutil url
+func (m *MultiAuthProvider) LookupURLX(u *urlutil.URL) []authx.AuthStrategy {
+	return m.LookupAddr(u.Host)
+}
+
+// Redact replaces the secret values in data
+func (m *MultiAuthProvider) Redact(data string) string {
+	for _, provider := range m.Providers {
+		data = provider.Redact(data)
+	}
+	return data
+}
+
+// DynamicSecrets returns the secrets requiring a login template
+func (m *MultiAuthProvider) DynamicSecrets() []*authx.Dynamic {
+	var secrets []*authx.Dynamic
+	for _, provider := range m.Providers {
+		secrets = append(secrets, provider.DynamicSecrets()...)
+	}
+	return secrets
+}
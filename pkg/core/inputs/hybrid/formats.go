@@ -0,0 +1,75 @@
+package hybrid
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/input/formats"
+	"github.com/khulnasoft-lab/vulmap/pkg/input/formats/burp"
+	"github.com/khulnasoft-lab/vulmap/pkg/input/formats/har"
+	"github.com/khulnasoft-lab/vulmap/pkg/input/formats/openapi"
+	"github.com/khulnasoft-lab/vulmap/pkg/input/formats/postman"
+	"github.com/khulnasoft-lab/vulmap/pkg/input/formats/zap"
+	"github.com/khulnasoft-lab/vulmap/pkg/input/types"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	vulmapTypes "github.com/khulnasoft-lab/vulmap/pkg/types"
+)
+
+// DefaultInputMode is the default input mode which reads
+// a list of targets (one per line) from the input file.
+const DefaultInputMode = "list"
+
+// supportedInputFormats is the list of request based input formats
+var supportedInputFormats = []formats.Format{
+	openapi.New(),
+	burp.New(),
+	zap.New(),
+	har.New(),
+	postman.New(),
+}
+
+// SupportedInputModes returns the comma separated list of supported input modes
+func SupportedInputModes() string {
+	modes := []string{DefaultInputMode}
+	for _, format := range supportedInputFormats {
+		modes = append(modes, format.Name())
+	}
+	return strings.Join(modes, ", ")
+}
+
+// IsSupportedInputMode returns true if the input mode is supported
+func IsSupportedInputMode(mode string) bool {
+	return mode == "" || mode == DefaultInputMode || getInputFormat(mode) != nil
+}
+
+// getInputFormat returns the request based input format for a mode
+func getInputFormat(mode string) formats.Format {
+	for _, format := range supportedInputFormats {
+		if strings.EqualFold(format.Name(), mode) {
+			return format
+		}
+	}
+	return nil
+}
+
+// initializeInputFormat parses the input file with a request based
+// input format and stores the discovered requests as inputs.
+func (i *Input) initializeInputFormat(options *vulmapTypes.Options) error {
+	format := getInputFormat(options.InputFileMode)
+	if format == nil {
+		return errors.Errorf("unsupported input mode: %s", options.InputFileMode)
+	}
+	format.SetOptions(formats.InputFormatOptions{
+		PostmanEnvironmentFile: options.PostmanEnvironmentFile,
+		Variables:              options.Vars.AsMap(),
+	})
+	err := format.Parse(options.TargetsFilePath, func(rr *types.RequestResponse) bool {
+		i.setItem(&contextargs.MetaInput{Input: rr.URL, ReqResp: rr})
+		return true
+	})
+	if err != nil {
+		return errors.Wrapf(err, "could not parse %s input file", format.Name())
+	}
+	return nil
+}
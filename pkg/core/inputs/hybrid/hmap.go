@@ -123,8 +123,13 @@ func (i *Input) initializeInputSources(opts *Options) error {
 		i.scanInputFromReader(readerutil.TimeoutReader{Reader: os.Stdin, Timeout: time.Duration(options.InputReadTimeout)})
 	}
 
-	// Handle target file
-	if options.TargetsFilePath != "" {
+	// Handle target file, parsed with a request based
+	// input format when the input mode is not a list.
+	if options.TargetsFilePath != "" && options.InputFileMode != "" && options.InputFileMode != DefaultInputMode {
+		if err := i.initializeInputFormat(options); err != nil {
+			return err
+		}
+	} else if options.TargetsFilePath != "" {
 		input, inputErr := os.Open(options.TargetsFilePath)
 		if inputErr != nil {
 			// Handle cloud based input here.
@@ -0,0 +1,130 @@
+// Package burp implements an input format which parses the XML
+// export of Burp Suite "Save items" into complete http requests.
+package burp
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/khulnasoft-lab/vulmap/pkg/input/formats"
+	"github.com/khulnasoft-lab/vulmap/pkg/input/types"
+)
+
+// BurpFormat is a Burp XML File parser
+type BurpFormat struct{}
+
+// New creates a new Burp XML File parser
+func New() *BurpFormat {
+	return &BurpFormat{}
+}
+
+var _ formats.Format = &BurpFormat{}
+
+// Name returns the name of the format
+func (b *BurpFormat) Name() string {
+	return "burp"
+}
+
+// SetOptions sets the options for the input format
+func (b *BurpFormat) SetOptions(options formats.InputFormatOptions) {}
+
+// item is a single request-response item of burp xml export
+type item struct {
+	URL      string   `xml:"url"`
+	Host     string   `xml:"host"`
+	Port     string   `xml:"port"`
+	Protocol string   `xml:"protocol"`
+	Request  itemData `xml:"request"`
+	Response itemData `xml:"response"`
+}
+
+// itemData is the optionally base64 encoded request or response data
+type itemData struct {
+	Base64 string `xml:"base64,attr"`
+	Value  string `xml:",chardata"`
+}
+
+// decode returns the decoded data of item
+func (d itemData) decode() (string, error) {
+	if d.Base64 != "true" {
+		return d.Value, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(d.Value))
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
+
+// baseURL returns the scheme://host:port of the item
+func (i *item) baseURL() string {
+	if i.Protocol == "" || i.Host == "" {
+		return ""
+	}
+	baseURL := i.Protocol + "://" + i.Host
+	if i.Port != "" && !(i.Protocol == "http" && i.Port == "80") && !(i.Protocol == "https" && i.Port == "443") {
+		baseURL += ":" + i.Port
+	}
+	return baseURL
+}
+
+// Parse parses the input and calls the provided callback
+// function for each RawRequest it discovers.
+func (b *BurpFormat) Parse(input string, resultsCb formats.ParseReqRespCallback) error {
+	file, err := os.Open(input)
+	if err != nil {
+		return errors.Wrap(err, "could not open burp xml file")
+	}
+	defer file.Close()
+
+	decoder := xml.NewDecoder(file)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "could not decode burp xml file")
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "item" {
+			continue
+		}
+		var burpItem item
+		if err := decoder.DecodeElement(&burpItem, &start); err != nil {
+			return errors.Wrap(err, "could not decode burp xml item")
+		}
+		rr, err := burpItem.requestResponse()
+		if err != nil {
+			gologger.Warning().Msgf("burp: could not parse item %s: %s\n", burpItem.URL, err)
+			continue
+		}
+		if !resultsCb(rr) {
+			return nil
+		}
+	}
+}
+
+// requestResponse converts the burp item to a request-response pair
+func (i *item) requestResponse() (*types.RequestResponse, error) {
+	rawRequest, err := i.Request.decode()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode request")
+	}
+	rr, err := types.ParseRawRequest(rawRequest, i.baseURL())
+	if err != nil {
+		return nil, err
+	}
+	if rawResponse, err := i.Response.decode(); err == nil && rawResponse != "" {
+		if resp, err := types.ParseRawResponse(rawResponse); err == nil {
+			rr.Response = resp
+		}
+	}
+	return rr, nil
+}
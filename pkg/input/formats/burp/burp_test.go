@@ -0,0 +1,29 @@
+package burp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/input/types"
+)
+
+func TestBurpParse(t *testing.T) {
+	format := New()
+
+	var gotRequests []*types.RequestResponse
+	err := format.Parse("testdata/burp.xml", func(request *types.RequestResponse) bool {
+		gotRequests = append(gotRequests, request)
+		return true
+	})
+	require.NoError(t, err, "could not parse burp xml")
+	require.Len(t, gotRequests, 1, "could not get correct number of requests")
+
+	got := gotRequests[0]
+	require.Equal(t, "http://localhost:8080/login?next=%2Fhome", got.URL)
+	require.Equal(t, "POST", got.Request.Method)
+	require.Equal(t, "session=abc", got.Request.Headers.Get("Cookie"))
+	require.Equal(t, "username=admin&password=abc", got.Request.Body)
+	require.Equal(t, 302, got.Response.StatusCode)
+	require.Equal(t, "/home", got.Response.Headers.Get("Location"))
+}
@@ -0,0 +1,35 @@
+// Package formats contains the interface implemented by request based
+// input formats (openapi, burp, har, postman etc) which parse input files
+// into complete http requests to be used as base requests for fuzzing.
+package formats
+
+import (
+	"github.com/khulnasoft-lab/vulmap/pkg/input/types"
+)
+
+// ParseReqRespCallback is a callback function for discovered raw requests.
+// Returning false from the callback stops the parsing.
+type ParseReqRespCallback func(rr *types.RequestResponse) bool
+
+// InputFormatOptions contains options for the input formats
+type InputFormatOptions struct {
+	// PostmanEnvironmentFile is the postman environment file used
+	// to resolve the variables of a postman collection.
+	PostmanEnvironmentFile string
+	// Variables are the global variables provided by the user.
+	//
+	// The openapi format resolves relative or missing servers against
+	// the target variable and uses them for server url variables.
+	Variables map[string]interface{}
+}
+
+// Format is an interface implemented by all input formats
+type Format interface {
+	// Name returns the name of the format
+	Name() string
+	// SetOptions sets the options for the input format
+	SetOptions(options InputFormatOptions)
+	// Parse parses the input file and calls the provided callback
+	// function for each RequestResponse it discovers.
+	Parse(input string, resultsCb ParseReqRespCallback) error
+}
@@ -0,0 +1,228 @@
+// Package har implements an input format which parses HTTP Archive (HAR 1.2)
+// files exported from browsers into complete http requests.
+package har
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/input/formats"
+	"github.com/khulnasoft-lab/vulmap/pkg/input/types"
+)
+
+// HarFormat is a HTTP Archive (HAR) File parser
+type HarFormat struct{}
+
+// New creates a new HAR file parser
+func New() *HarFormat {
+	return &HarFormat{}
+}
+
+var _ formats.Format = &HarFormat{}
+
+// Name returns the name of the format
+func (h *HarFormat) Name() string {
+	return "har"
+}
+
+// SetOptions sets the options for the input format
+func (h *HarFormat) SetOptions(options formats.InputFormatOptions) {}
+
+// archive is the root of a HAR file
+type archive struct {
+	Log struct {
+		Entries []*entry `json:"entries"`
+	} `json:"log"`
+}
+
+// entry is a single request-response entry of a HAR file
+type entry struct {
+	Request  *request  `json:"request"`
+	Response *response `json:"response"`
+}
+
+type request struct {
+	Method   string      `json:"method"`
+	URL      string      `json:"url"`
+	Headers  []nameValue `json:"headers"`
+	PostData *struct {
+		MimeType string      `json:"mimeType"`
+		Text     string      `json:"text"`
+		Params   []nameValue `json:"params"`
+	} `json:"postData"`
+}
+
+type response struct {
+	Status  int         `json:"status"`
+	Headers []nameValue `json:"headers"`
+	Content struct {
+		Text     string `json:"text"`
+		Encoding string `json:"encoding"`
+	} `json:"content"`
+}
+
+type nameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// skippedHeaders are the headers which are not added to the requests.
+// HTTP/2 pseudo headers (:authority, :path etc) are skipped as well.
+var skippedHeaders = map[string]struct{}{
+	"content-length":    {},
+	"connection":        {},
+	"transfer-encoding": {},
+}
+
+// Parse parses the input and calls the provided callback
+// function for each RawRequest it discovers.
+func (h *HarFormat) Parse(input string, resultsCb formats.ParseReqRespCallback) error {
+	file, err := os.Open(input)
+	if err != nil {
+		return errors.Wrap(err, "could not open har file")
+	}
+	defer file.Close()
+
+	var har archive
+	if err := json.NewDecoder(file).Decode(&har); err != nil {
+		return errors.Wrap(err, "could not decode har file")
+	}
+
+	seen := make(map[string]struct{})
+	for _, item := range har.Log.Entries {
+		if item == nil || item.Request == nil || item.Request.URL == "" {
+			continue
+		}
+		rr := item.requestResponse()
+
+		// dedupe requests having the same method, url and parameter names
+		shape := requestShape(rr)
+		if _, ok := seen[shape]; ok {
+			continue
+		}
+		seen[shape] = struct{}{}
+
+		if !resultsCb(rr) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// requestResponse converts the HAR entry to a request-response pair
+func (e *entry) requestResponse() *types.RequestResponse {
+	headers := http.Header{}
+	for _, header := range e.Request.Headers {
+		if strings.HasPrefix(header.Name, ":") {
+			continue
+		}
+		if _, ok := skippedHeaders[strings.ToLower(header.Name)]; ok {
+			continue
+		}
+		headers.Add(header.Name, header.Value)
+	}
+
+	var body string
+	if postData := e.Request.PostData; postData != nil {
+		body = postData.Text
+		if body == "" && len(postData.Params) > 0 {
+			values := url.Values{}
+			for _, param := range postData.Params {
+				values.Add(param.Name, param.Value)
+			}
+			body = values.Encode()
+		}
+		if postData.MimeType != "" && headers.Get("Content-Type") == "" {
+			headers.Set("Content-Type", postData.MimeType)
+		}
+	}
+
+	rr := &types.RequestResponse{
+		URL: e.Request.URL,
+		Request: &types.HttpRequest{
+			Method:  e.Request.Method,
+			Headers: headers,
+			Body:    body,
+		},
+	}
+	if e.Response != nil && e.Response.Status > 0 {
+		respHeaders := http.Header{}
+		for _, header := range e.Response.Headers {
+			respHeaders.Add(header.Name, header.Value)
+		}
+		respBody := e.Response.Content.Text
+		if e.Response.Content.Encoding == "base64" {
+			if decoded, err := base64.StdEncoding.DecodeString(respBody); err == nil {
+				respBody = string(decoded)
+			}
+		}
+		rr.Response = &types.HttpResponse{
+			StatusCode: e.Response.Status,
+			Headers:    respHeaders,
+			Body:       respBody,
+		}
+	}
+	return rr
+}
+
+// requestShape returns the shape of the request made of method, url without
+// query and the sorted names of query and body parameters of the request.
+func requestShape(rr *types.RequestResponse) string {
+	var builder strings.Builder
+	builder.WriteString(rr.Request.Method)
+	builder.WriteString(" ")
+
+	parsed, err := url.Parse(rr.URL)
+	if err != nil {
+		builder.WriteString(rr.URL)
+		return builder.String()
+	}
+	builder.WriteString(parsed.Scheme + "://" + parsed.Host + parsed.Path)
+	builder.WriteString("?")
+	builder.WriteString(strings.Join(sortedNames(parsed.Query()), ","))
+
+	if rr.Request.Body != "" {
+		builder.WriteString("#")
+		builder.WriteString(strings.Join(bodyParameterNames(rr.Request.Headers.Get("Content-Type"), rr.Request.Body), ","))
+	}
+	return builder.String()
+}
+
+// bodyParameterNames returns the names of top-level body parameters
+// for form and json bodies and the body itself for other bodies.
+func bodyParameterNames(contentType, body string) []string {
+	switch {
+	case strings.Contains(contentType, "json"):
+		var object map[string]interface{}
+		if err := json.Unmarshal([]byte(body), &object); err == nil {
+			names := make([]string, 0, len(object))
+			for name := range object {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return names
+		}
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		if values, err := url.ParseQuery(body); err == nil {
+			return sortedNames(values)
+		}
+	}
+	return []string{body}
+}
+
+// sortedNames returns the sorted names of parameters
+func sortedNames(values url.Values) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
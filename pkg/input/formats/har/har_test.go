@@ -0,0 +1,30 @@
+package har
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/input/types"
+)
+
+func TestHarParse(t *testing.T) {
+	format := New()
+
+	var gotRequests []*types.RequestResponse
+	err := format.Parse("testdata/session.har", func(request *types.RequestResponse) bool {
+		gotRequests = append(gotRequests, request)
+		return true
+	})
+	require.NoError(t, err, "could not parse har file")
+	require.Len(t, gotRequests, 2, "could not dedupe requests with same shape")
+
+	require.Equal(t, "http://localhost:8080/products?id=1&sort=asc", gotRequests[0].URL)
+	require.Equal(t, "session=abc", gotRequests[0].Request.Headers.Get("Cookie"))
+	require.Empty(t, gotRequests[0].Request.Headers.Get(":authority"))
+	require.Equal(t, "<html></html>", gotRequests[0].Response.Body)
+
+	require.Equal(t, "POST", gotRequests[1].Request.Method)
+	require.Equal(t, `{"id":1,"quantity":2}`, gotRequests[1].Request.Body)
+	require.Empty(t, gotRequests[1].Request.Headers.Get("Content-Length"))
+}
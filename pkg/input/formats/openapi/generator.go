@@ -0,0 +1,509 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/input/formats"
+	"github.com/khulnasoft-lab/vulmap/pkg/input/types"
+)
+
+// maxSchemaDepth is the maximum depth up to which nested
+// schemas are expanded to avoid recursive definitions.
+const maxSchemaDepth = 8
+
+// preferredContentTypes is the order in which request body
+// content types are picked when multiple are supported.
+var preferredContentTypes = []string{
+	"application/json",
+	"application/xml",
+	"application/x-www-form-urlencoded",
+	"multipart/form-data",
+	"text/plain",
+}
+
+// generateRequestsFromSchema generates http requests for all operations of
+// the schema filling parameters and body with example or placeholder values.
+func generateRequestsFromSchema(doc *document, variables map[string]interface{}, callback formats.ParseReqRespCallback) error {
+	baseURL, err := doc.baseURL(variables)
+	if err != nil {
+		return err
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		item := doc.Paths[path]
+		if item == nil {
+			continue
+		}
+		for _, op := range item.operations() {
+			rr, err := doc.generateRequest(baseURL, path, op.method, item.Parameters, op.op)
+			if err != nil {
+				return errors.Wrapf(err, "could not generate request for %s %s", op.method, path)
+			}
+			if !callback(rr) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// baseURL returns the base url of the api described by the document.
+//
+// Relative server urls, and the default "/" server of documents without
+// servers, are resolved against the target variable provided by the user.
+func (doc *document) baseURL(variables map[string]interface{}) (string, error) {
+	var target string
+	if value, ok := variables["target"]; ok {
+		target = fmt.Sprint(value)
+	}
+
+	if doc.Swagger != "" {
+		if doc.Host == "" {
+			return resolveServerURL(target, doc.BasePath)
+		}
+		scheme := "https"
+		if len(doc.Schemes) > 0 {
+			scheme = doc.Schemes[0]
+		}
+		return fmt.Sprintf("%s://%s%s", scheme, doc.Host, strings.TrimSuffix(doc.BasePath, "/")), nil
+	}
+	if len(doc.Servers) == 0 {
+		return resolveServerURL(target, "/")
+	}
+	var relative string
+	for _, server := range doc.Servers {
+		if server == nil {
+			continue
+		}
+		serverURL := server.URL
+		for name, variable := range server.Variables {
+			value := variable.Default
+			if provided, ok := variables[name]; ok {
+				value = fmt.Sprint(provided)
+			}
+			serverURL = strings.ReplaceAll(serverURL, "{"+name+"}", value)
+		}
+		parsed, err := url.Parse(serverURL)
+		if err != nil {
+			continue
+		}
+		if parsed.Scheme != "" && parsed.Host != "" {
+			return strings.TrimSuffix(serverURL, "/"), nil
+		}
+		if relative == "" && parsed.Scheme == "" && parsed.Host == "" {
+			relative = serverURL
+		}
+	}
+	if relative == "" {
+		return "", errors.New("no valid server url found in openapi schema")
+	}
+	return resolveServerURL(target, relative)
+}
+
+// resolveServerURL resolves a relative server url against the target
+func resolveServerURL(target, serverURL string) (string, error) {
+	if target == "" {
+		return "", errors.Errorf("relative server url %q requires the target variable (-var target=<url>)", serverURL)
+	}
+	base, err := url.Parse(target)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return "", errors.Errorf("invalid target variable %q, an absolute url is required", target)
+	}
+	reference, err := url.Parse(serverURL)
+	if err != nil {
+		return "", errors.Wrap(err, "could not parse server url")
+	}
+	return strings.TrimSuffix(base.ResolveReference(reference).String(), "/"), nil
+}
+
+// generateRequest generates a single request for an operation
+func (doc *document) generateRequest(baseURL, path, method string, common []*parameter, op *operation) (*types.RequestResponse, error) {
+	headers := http.Header{}
+	query := []string{}
+	var cookies []string
+	var body string
+	formParams := map[string]interface{}{}
+
+	consumes := append(append([]string{}, op.Consumes...), doc.Consumes...)
+	for _, param := range doc.mergeParameters(common, op.Parameters) {
+		if param.In == "body" {
+			data, contentType, err := encodeBody(firstOr(consumes, "application/json"), doc.exampleForSchema(param.Schema, 0))
+			if err != nil {
+				return nil, err
+			}
+			headers.Set("Content-Type", contentType)
+			body = data
+			continue
+		}
+		value := stringifyValue(doc.exampleForParameter(param))
+		switch param.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+param.Name+"}", url.PathEscape(value))
+		case "query":
+			query = append(query, url.QueryEscape(param.Name)+"="+url.QueryEscape(value))
+		case "header":
+			headers.Set(param.Name, value)
+		case "cookie":
+			cookies = append(cookies, param.Name+"="+value)
+		case "formData":
+			formParams[param.Name] = value
+		}
+	}
+	if len(cookies) > 0 {
+		headers.Set("Cookie", strings.Join(cookies, "; "))
+	}
+	if len(formParams) > 0 {
+		contentType := "application/x-www-form-urlencoded"
+		for _, item := range consumes {
+			if strings.HasPrefix(item, "multipart/form-data") {
+				contentType = item
+			}
+		}
+		data, contentType, err := encodeBody(contentType, formParams)
+		if err != nil {
+			return nil, err
+		}
+		body = data
+		headers.Set("Content-Type", contentType)
+	}
+
+	if reqBody := doc.resolveRequestBody(op.RequestBody); reqBody != nil && len(reqBody.Content) > 0 {
+		contentType := pickContentType(reqBody.Content)
+		media := reqBody.Content[contentType]
+		var example interface{}
+		if media != nil {
+			if media.Example != nil {
+				example = media.Example
+			} else {
+				example = doc.exampleForSchema(media.Schema, 0)
+			}
+		}
+		data, contentType, err := encodeBody(contentType, example)
+		if err != nil {
+			return nil, err
+		}
+		body = data
+		headers.Set("Content-Type", contentType)
+	}
+
+	requestURL := baseURL + path
+	if len(query) > 0 {
+		requestURL += "?" + strings.Join(query, "&")
+	}
+	rr := &types.RequestResponse{
+		URL: requestURL,
+		Request: &types.HttpRequest{
+			Method:  method,
+			Headers: headers,
+			Body:    body,
+		},
+	}
+	return rr, nil
+}
+
+// mergeParameters merges path level parameters with operation level parameters.
+// Operation parameters override path parameters with same name and location.
+func (doc *document) mergeParameters(common, params []*parameter) []*parameter {
+	var merged []*parameter
+	index := make(map[string]int)
+	for _, param := range append(append([]*parameter{}, common...), params...) {
+		param = doc.resolveParameter(param)
+		if param == nil {
+			continue
+		}
+		key := param.In + ":" + param.Name
+		if i, ok := index[key]; ok {
+			merged[i] = param
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, param)
+	}
+	return merged
+}
+
+// resolveParameter resolves a parameter reference
+func (doc *document) resolveParameter(param *parameter) *parameter {
+	for depth := 0; param != nil && param.Ref != "" && depth < maxSchemaDepth; depth++ {
+		name := refName(param.Ref)
+		switch {
+		case strings.HasPrefix(param.Ref, "#/components/parameters/"):
+			param = doc.Components.Parameters[name]
+		case strings.HasPrefix(param.Ref, "#/parameters/"):
+			param = doc.Parameters[name]
+		default:
+			return nil
+		}
+	}
+	return param
+}
+
+// resolveRequestBody resolves a request body reference
+func (doc *document) resolveRequestBody(body *requestBody) *requestBody {
+	for depth := 0; body != nil && body.Ref != "" && depth < maxSchemaDepth; depth++ {
+		if !strings.HasPrefix(body.Ref, "#/components/requestBodies/") {
+			return nil
+		}
+		body = doc.Components.RequestBodies[refName(body.Ref)]
+	}
+	return body
+}
+
+// resolveSchema resolves a schema reference
+func (doc *document) resolveSchema(s *schema) *schema {
+	for depth := 0; s != nil && s.Ref != "" && depth < maxSchemaDepth; depth++ {
+		name := refName(s.Ref)
+		switch {
+		case strings.HasPrefix(s.Ref, "#/components/schemas/"):
+			s = doc.Components.Schemas[name]
+		case strings.HasPrefix(s.Ref, "#/definitions/"):
+			s = doc.Definitions[name]
+		default:
+			return nil
+		}
+	}
+	return s
+}
+
+// exampleForParameter returns an example value for a parameter
+func (doc *document) exampleForParameter(param *parameter) interface{} {
+	if param.Example != nil {
+		return param.Example
+	}
+	if param.Schema != nil {
+		return doc.exampleForSchema(param.Schema, 0)
+	}
+	return doc.exampleForSchema(&schema{
+		Type:    param.Type,
+		Format:  param.Format,
+		Items:   param.Items,
+		Default: param.Default,
+		Enum:    param.Enum,
+	}, 0)
+}
+
+// exampleForSchema returns an example value for a schema using the example,
+// default or enum values if provided or a placeholder based on the type.
+func (doc *document) exampleForSchema(s *schema, depth int) interface{} {
+	s = doc.resolveSchema(s)
+	if s == nil || depth > maxSchemaDepth {
+		return nil
+	}
+	switch {
+	case s.Example != nil:
+		return s.Example
+	case s.Default != nil:
+		return s.Default
+	case len(s.Enum) > 0:
+		return s.Enum[0]
+	}
+
+	if len(s.AllOf) > 0 {
+		merged := make(map[string]interface{})
+		for _, item := range s.AllOf {
+			if value, ok := doc.exampleForSchema(item, depth+1).(map[string]interface{}); ok {
+				for k, v := range value {
+					merged[k] = v
+				}
+			}
+		}
+		return merged
+	}
+	if len(s.OneOf) > 0 {
+		return doc.exampleForSchema(s.OneOf[0], depth+1)
+	}
+	if len(s.AnyOf) > 0 {
+		return doc.exampleForSchema(s.AnyOf[0], depth+1)
+	}
+
+	schemaType := s.Type.String()
+	if schemaType == "" && len(s.Properties) > 0 {
+		schemaType = "object"
+	}
+	switch schemaType {
+	case "object":
+		object := make(map[string]interface{})
+		for name, property := range s.Properties {
+			object[name] = doc.exampleForSchema(property, depth+1)
+		}
+		return object
+	case "array":
+		item := doc.exampleForSchema(s.Items, depth+1)
+		if item == nil {
+			return []interface{}{}
+		}
+		return []interface{}{item}
+	case "integer":
+		return 1
+	case "number":
+		return 1.5
+	case "boolean":
+		return true
+	default:
+		return exampleForStringFormat(s.Format)
+	}
+}
+
+// exampleForStringFormat returns a placeholder value for a string format
+func exampleForStringFormat(format string) string {
+	switch format {
+	case "date":
+		return "2023-01-01"
+	case "date-time":
+		return "2023-01-01T00:00:00Z"
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+	case "uri", "url":
+		return "https://example.com"
+	case "hostname":
+		return "example.com"
+	case "ipv4":
+		return "127.0.0.1"
+	case "ipv6":
+		return "::1"
+	case "password":
+		return "password"
+	case "byte":
+		return "ZXhhbXBsZQ=="
+	default:
+		return "string"
+	}
+}
+
+// encodeBody encodes an example value based on the content type and
+// returns the encoded body along with the final content type header.
+func encodeBody(contentType string, value interface{}) (string, string, error) {
+	switch {
+	case strings.Contains(contentType, "json"):
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", "", errors.Wrap(err, "could not encode json body")
+		}
+		return string(data), contentType, nil
+	case strings.Contains(contentType, "xml"):
+		var buffer bytes.Buffer
+		encodeXML(&buffer, "root", value)
+		return buffer.String(), contentType, nil
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		values := url.Values{}
+		object, _ := value.(map[string]interface{})
+		for _, key := range sortedKeys(object) {
+			values.Set(key, stringifyValue(object[key]))
+		}
+		return values.Encode(), contentType, nil
+	case strings.HasPrefix(contentType, "multipart/form-data"):
+		var buffer bytes.Buffer
+		writer := multipart.NewWriter(&buffer)
+		object, _ := value.(map[string]interface{})
+		for _, key := range sortedKeys(object) {
+			if err := writer.WriteField(key, stringifyValue(object[key])); err != nil {
+				return "", "", errors.Wrap(err, "could not encode multipart body")
+			}
+		}
+		if err := writer.Close(); err != nil {
+			return "", "", errors.Wrap(err, "could not encode multipart body")
+		}
+		return buffer.String(), writer.FormDataContentType(), nil
+	default:
+		return stringifyValue(value), contentType, nil
+	}
+}
+
+// encodeXML encodes a value as xml with the provided element name
+func encodeXML(buffer *bytes.Buffer, name string, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		fmt.Fprintf(buffer, "<%s>", name)
+		for _, key := range sortedKeys(v) {
+			encodeXML(buffer, key, v[key])
+		}
+		fmt.Fprintf(buffer, "</%s>", name)
+	case []interface{}:
+		for _, item := range v {
+			encodeXML(buffer, name, item)
+		}
+	default:
+		fmt.Fprintf(buffer, "<%s>", name)
+		_ = xml.EscapeText(buffer, []byte(stringifyValue(v)))
+		fmt.Fprintf(buffer, "</%s>", name)
+	}
+}
+
+// pickContentType picks the content type to use for a request body
+func pickContentType(content map[string]*mediaType) string {
+	for _, preferred := range preferredContentTypes {
+		for contentType := range content {
+			if strings.HasPrefix(contentType, preferred) {
+				return contentType
+			}
+		}
+	}
+	keys := make([]string, 0, len(content))
+	for key := range content {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys[0]
+}
+
+// stringifyValue converts an example value to string
+func stringifyValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, stringifyValue(item))
+		}
+		return strings.Join(items, ",")
+	case map[string]interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// sortedKeys returns the sorted keys of a map
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// refName returns the name of the component from a local reference
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// firstOr returns the first item of the slice or the fallback value
+func firstOr(items []string, fallback string) string {
+	if len(items) > 0 {
+		return items[0]
+	}
+	return fallback
+}
@@ -0,0 +1,210 @@
+// Package openapi implements an input format which parses OpenAPI 3.x and
+// Swagger 2.0 specifications into complete http requests for each operation.
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/input/formats"
+)
+
+// OpenAPIFormat is a OpenAPI 3.x and Swagger 2.0 Schema File parser
+type OpenAPIFormat struct {
+	options formats.InputFormatOptions
+}
+
+// New creates a new OpenAPI format parser
+func New() *OpenAPIFormat {
+	return &OpenAPIFormat{}
+}
+
+var _ formats.Format = &OpenAPIFormat{}
+
+// Name returns the name of the format
+func (j *OpenAPIFormat) Name() string {
+	return "openapi"
+}
+
+// SetOptions sets the options for the input format
+func (j *OpenAPIFormat) SetOptions(options formats.InputFormatOptions) {
+	j.options = options
+}
+
+// Parse parses the input and calls the provided callback
+// function for each RawRequest it discovers.
+func (j *OpenAPIFormat) Parse(input string, resultsCb formats.ParseReqRespCallback) error {
+	data, err := os.ReadFile(input)
+	if err != nil {
+		return errors.Wrap(err, "could not read openapi schema")
+	}
+	// json specifications are converted to yaml before decoding
+	// as json documents indented with tabs are not valid yaml.
+	if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte("{")) {
+		var generic interface{}
+		if err := json.Unmarshal(trimmed, &generic); err != nil {
+			return errors.Wrap(err, "could not decode openapi schema")
+		}
+		if data, err = yaml.Marshal(generic); err != nil {
+			return errors.Wrap(err, "could not decode openapi schema")
+		}
+	}
+	doc := &document{}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return errors.Wrap(err, "could not decode openapi schema")
+	}
+	if doc.OpenAPI == "" && doc.Swagger == "" {
+		return errors.New("input is not a valid openapi or swagger schema")
+	}
+	return generateRequestsFromSchema(doc, j.options.Variables, resultsCb)
+}
+
+// document is a minimal representation of an OpenAPI 3.x or
+// Swagger 2.0 document containing fields required to build requests.
+type document struct {
+	// OpenAPI is the version of an OpenAPI 3.x document
+	OpenAPI string `yaml:"openapi"`
+	// Swagger is the version of a Swagger 2.0 document
+	Swagger string `yaml:"swagger"`
+
+	// Servers is the list of servers of an OpenAPI 3.x document
+	Servers []*server `yaml:"servers"`
+	// Host, BasePath and Schemes describe the server of a Swagger 2.0 document
+	Host     string   `yaml:"host"`
+	BasePath string   `yaml:"basePath"`
+	Schemes  []string `yaml:"schemes"`
+	Consumes []string `yaml:"consumes"`
+
+	Paths map[string]*pathItem `yaml:"paths"`
+
+	Components struct {
+		Schemas       map[string]*schema      `yaml:"schemas"`
+		Parameters    map[string]*parameter   `yaml:"parameters"`
+		RequestBodies map[string]*requestBody `yaml:"requestBodies"`
+	} `yaml:"components"`
+	Definitions map[string]*schema    `yaml:"definitions"`
+	Parameters  map[string]*parameter `yaml:"parameters"`
+}
+
+type server struct {
+	URL       string `yaml:"url"`
+	Variables map[string]struct {
+		Default string `yaml:"default"`
+	} `yaml:"variables"`
+}
+
+type pathItem struct {
+	Parameters []*parameter `yaml:"parameters"`
+	Get        *operation   `yaml:"get"`
+	Put        *operation   `yaml:"put"`
+	Post       *operation   `yaml:"post"`
+	Delete     *operation   `yaml:"delete"`
+	Options    *operation   `yaml:"options"`
+	Head       *operation   `yaml:"head"`
+	Patch      *operation   `yaml:"patch"`
+	Trace      *operation   `yaml:"trace"`
+}
+
+// methodOperation is an operation along with its http method
+type methodOperation struct {
+	method string
+	op     *operation
+}
+
+// operations returns the operations of path item in a stable order
+func (p *pathItem) operations() []methodOperation {
+	all := []methodOperation{
+		{"GET", p.Get}, {"POST", p.Post}, {"PUT", p.Put}, {"PATCH", p.Patch},
+		{"DELETE", p.Delete}, {"HEAD", p.Head}, {"OPTIONS", p.Options}, {"TRACE", p.Trace},
+	}
+	var ops []methodOperation
+	for _, item := range all {
+		if item.op != nil {
+			ops = append(ops, item)
+		}
+	}
+	return ops
+}
+
+type operation struct {
+	OperationID string       `yaml:"operationId"`
+	Parameters  []*parameter `yaml:"parameters"`
+	RequestBody *requestBody `yaml:"requestBody"`
+	Consumes    []string     `yaml:"consumes"`
+}
+
+type parameter struct {
+	Ref      string      `yaml:"$ref"`
+	Name     string      `yaml:"name"`
+	In       string      `yaml:"in"`
+	Required bool        `yaml:"required"`
+	Schema   *schema     `yaml:"schema"`
+	Example  interface{} `yaml:"example"`
+
+	// swagger 2.0 non-body parameters describe their type inline
+	Type    schemaType    `yaml:"type"`
+	Format  string        `yaml:"format"`
+	Items   *schema       `yaml:"items"`
+	Default interface{}   `yaml:"default"`
+	Enum    []interface{} `yaml:"enum"`
+}
+
+type requestBody struct {
+	Ref     string                `yaml:"$ref"`
+	Content map[string]*mediaType `yaml:"content"`
+}
+
+type mediaType struct {
+	Schema  *schema     `yaml:"schema"`
+	Example interface{} `yaml:"example"`
+}
+
+type schema struct {
+	Ref        string             `yaml:"$ref"`
+	Type       schemaType         `yaml:"type"`
+	Format     string             `yaml:"format"`
+	Properties map[string]*schema `yaml:"properties"`
+	Items      *schema            `yaml:"items"`
+	Example    interface{}        `yaml:"example"`
+	Default    interface{}        `yaml:"default"`
+	Enum       []interface{}      `yaml:"enum"`
+	AllOf      []*schema          `yaml:"allOf"`
+	OneOf      []*schema          `yaml:"oneOf"`
+	AnyOf      []*schema          `yaml:"anyOf"`
+}
+
+// schemaType is the type of schema which can either be a
+// single string or a list of strings (OpenAPI 3.1).
+type schemaType []string
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+func (s *schemaType) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.SequenceNode {
+		var types []string
+		if err := value.Decode(&types); err != nil {
+			return err
+		}
+		*s = types
+		return nil
+	}
+	var single string
+	if err := value.Decode(&single); err != nil {
+		return err
+	}
+	*s = schemaType{single}
+	return nil
+}
+
+// String returns the first non-null type of the schema
+func (s schemaType) String() string {
+	for _, item := range s {
+		if item != "null" {
+			return item
+		}
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/input/formats"
+	"github.com/khulnasoft-lab/vulmap/pkg/input/types"
+)
+
+func TestOpenAPIParser(t *testing.T) {
+	format := New()
+
+	var gotRequests []*types.RequestResponse
+	err := format.Parse("testdata/openapi.yaml", func(request *types.RequestResponse) bool {
+		gotRequests = append(gotRequests, request)
+		return true
+	})
+	require.NoError(t, err, "could not parse openapi schema")
+	require.Len(t, gotRequests, 3, "could not get correct number of requests")
+
+	require.Equal(t, "GET", gotRequests[0].Request.Method)
+	require.Equal(t, "http://localhost:8080/api/v1/pets?limit=10", gotRequests[0].URL)
+	require.Equal(t, "3fa85f64-5717-4562-b3fc-2c963f66afa6", gotRequests[0].Request.Headers.Get("X-Request-Id"))
+	require.Equal(t, "session=abc", gotRequests[0].Request.Headers.Get("Cookie"))
+
+	require.Equal(t, "POST", gotRequests[1].Request.Method)
+	require.Equal(t, "application/json", gotRequests[1].Request.Headers.Get("Content-Type"))
+	require.JSONEq(t, `{"name":"doggie","tags":["string"]}`, gotRequests[1].Request.Body)
+
+	require.Equal(t, "http://localhost:8080/api/v1/pets/1", gotRequests[2].URL)
+}
+
+func TestSwaggerParser(t *testing.T) {
+	format := New()
+
+	var gotRequests []*types.RequestResponse
+	err := format.Parse("testdata/swagger.json", func(request *types.RequestResponse) bool {
+		gotRequests = append(gotRequests, request)
+		return true
+	})
+	require.NoError(t, err, "could not parse swagger schema")
+	require.Len(t, gotRequests, 1, "could not get correct number of requests")
+	require.Equal(t, "http://localhost:8080/v2/users/login", gotRequests[0].URL)
+	require.Equal(t, "application/x-www-form-urlencoded", gotRequests[0].Request.Headers.Get("Content-Type"))
+	require.Equal(t, "password=password&username=string", gotRequests[0].Request.Body)
+}
+
+func TestOpenAPIRelativeServer(t *testing.T) {
+	format := New()
+
+	err := format.Parse("testdata/relative.yaml", func(request *types.RequestResponse) bool { return true })
+	require.Error(t, err, "could parse relative server without target")
+
+	format.SetOptions(formats.InputFormatOptions{Variables: map[string]interface{}{"target": "http://localhost:8080/", "version": "v2"}})
+	var gotRequests []*types.RequestResponse
+	err = format.Parse("testdata/relative.yaml", func(request *types.RequestResponse) bool {
+		gotRequests = append(gotRequests, request)
+		return true
+	})
+	require.NoError(t, err, "could not parse openapi schema with relative server")
+	require.Len(t, gotRequests, 1, "could not get correct number of requests")
+	require.Equal(t, "http://localhost:8080/api/v2/status", gotRequests[0].URL)
+
+	doc := &document{OpenAPI: "3.0.0"}
+	baseURL, err := doc.baseURL(map[string]interface{}{"target": "https://example.com"})
+	require.NoError(t, err, "could not resolve missing servers")
+	require.Equal(t, "https://example.com", baseURL)
+}
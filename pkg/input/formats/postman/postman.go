@@ -0,0 +1,273 @@
+// Package postman implements an input format which parses Postman v2.1
+// collections into complete http requests for each request of collection.
+package postman
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/khulnasoft-lab/vulmap/pkg/input/formats"
+	"github.com/khulnasoft-lab/vulmap/pkg/input/types"
+)
+
+// variableRegex matches {{variable}} placeholders of postman collections
+var variableRegex = regexp.MustCompile(`{{\s*([^{}\s]+)\s*}}`)
+
+// PostmanFormat is a Postman v2.1 Collection File parser
+type PostmanFormat struct {
+	options formats.InputFormatOptions
+}
+
+// New creates a new Postman collection parser
+func New() *PostmanFormat {
+	return &PostmanFormat{}
+}
+
+var _ formats.Format = &PostmanFormat{}
+
+// Name returns the name of the format
+func (p *PostmanFormat) Name() string {
+	return "postman"
+}
+
+// SetOptions sets the options for the input format
+func (p *PostmanFormat) SetOptions(options formats.InputFormatOptions) {
+	p.options = options
+}
+
+// Parse parses the input and calls the provided callback
+// function for each RawRequest it discovers.
+func (p *PostmanFormat) Parse(input string, resultsCb formats.ParseReqRespCallback) error {
+	var collection collection
+	if err := readJSONFile(input, &collection); err != nil {
+		return errors.Wrap(err, "could not read postman collection")
+	}
+
+	// environment values take precedence over collection variables
+	variables := make(map[string]string)
+	for _, variable := range collection.Variable {
+		if !variable.Disabled {
+			variables[variable.Key] = variable.stringValue()
+		}
+	}
+	if p.options.PostmanEnvironmentFile != "" {
+		var env environment
+		if err := readJSONFile(p.options.PostmanEnvironmentFile, &env); err != nil {
+			return errors.Wrap(err, "could not read postman environment")
+		}
+		for _, value := range env.Values {
+			if value.Enabled == nil || *value.Enabled {
+				variables[value.Key] = value.stringValue()
+			}
+		}
+	}
+
+	parser := &collectionParser{variables: variables, callback: resultsCb}
+	parser.walk(collection.Item, collection.Auth)
+	return nil
+}
+
+// collectionParser flattens the folders of a collection into requests
+type collectionParser struct {
+	variables map[string]string
+	callback  formats.ParseReqRespCallback
+	stopped   bool
+}
+
+// walk walks the items of a folder inheriting the auth of the parent
+func (c *collectionParser) walk(items []*item, parentAuth *auth) {
+	for _, item := range items {
+		if c.stopped || item == nil {
+			return
+		}
+		itemAuth := parentAuth
+		if item.Auth != nil {
+			itemAuth = item.Auth
+		}
+		if item.Request == nil {
+			c.walk(item.Item, itemAuth)
+			continue
+		}
+		if item.Request.Auth != nil {
+			itemAuth = item.Request.Auth
+		}
+		rr, err := c.buildRequest(item.Request, itemAuth)
+		if err != nil {
+			gologger.Warning().Msgf("postman: could not build request %s: %s\n", item.Name, err)
+			continue
+		}
+		if !c.callback(rr) {
+			c.stopped = true
+			return
+		}
+	}
+}
+
+// buildRequest builds a request-response pair from a collection request
+func (c *collectionParser) buildRequest(req *request, itemAuth *auth) (*types.RequestResponse, error) {
+	rawURL := c.resolve(req.URL.String())
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse request url")
+	}
+
+	headers := http.Header{}
+	for _, header := range req.Header {
+		if !header.Disabled {
+			headers.Add(c.resolve(header.Key), c.resolve(header.stringValue()))
+		}
+	}
+
+	body, contentType, err := c.buildBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" && headers.Get("Content-Type") == "" {
+		headers.Set("Content-Type", contentType)
+	}
+
+	c.applyAuth(itemAuth, headers, parsed)
+
+	method := req.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	rr := &types.RequestResponse{
+		URL: parsed.String(),
+		Request: &types.HttpRequest{
+			Method:  strings.ToUpper(method),
+			Headers: headers,
+			Body:    body,
+		},
+	}
+	return rr, nil
+}
+
+// buildBody builds the body and content type of the request
+func (c *collectionParser) buildBody(body *requestBody) (string, string, error) {
+	if body == nil || body.Disabled {
+		return "", "", nil
+	}
+	switch body.Mode {
+	case "raw":
+		var contentType string
+		switch body.Options.Raw.Language {
+		case "json":
+			contentType = "application/json"
+		case "xml":
+			contentType = "application/xml"
+		case "html":
+			contentType = "text/html"
+		case "text":
+			contentType = "text/plain"
+		}
+		return c.resolve(body.Raw), contentType, nil
+	case "urlencoded":
+		values := url.Values{}
+		for _, param := range body.URLEncoded {
+			if !param.Disabled {
+				values.Add(c.resolve(param.Key), c.resolve(param.stringValue()))
+			}
+		}
+		return values.Encode(), "application/x-www-form-urlencoded", nil
+	case "formdata":
+		var builder multipartBuilder
+		for _, param := range body.FormData {
+			if param.Disabled {
+				continue
+			}
+			value := param.stringValue()
+			if src, ok := param.Src.(string); ok && param.Type == "file" {
+				value = src
+			}
+			if err := builder.writeField(c.resolve(param.Key), c.resolve(value), param.Type == "file"); err != nil {
+				return "", "", errors.Wrap(err, "could not build multipart body")
+			}
+		}
+		return builder.finish()
+	case "graphql":
+		data, err := json.Marshal(map[string]interface{}{
+			"query":     c.resolve(body.GraphQL.Query),
+			"variables": json.RawMessage(defaultIfEmpty(c.resolve(body.GraphQL.Variables), "{}")),
+		})
+		if err != nil {
+			return "", "", errors.Wrap(err, "could not build graphql body")
+		}
+		return string(data), "application/json", nil
+	}
+	return "", "", nil
+}
+
+// applyAuth applies the auth block to the request headers or query
+func (c *collectionParser) applyAuth(itemAuth *auth, headers http.Header, parsed *url.URL) {
+	if itemAuth == nil {
+		return
+	}
+	switch itemAuth.Type {
+	case "bearer":
+		if token := c.resolve(itemAuth.value("bearer", "token")); token != "" {
+			headers.Set("Authorization", "Bearer "+token)
+		}
+	case "basic":
+		username := c.resolve(itemAuth.value("basic", "username"))
+		password := c.resolve(itemAuth.value("basic", "password"))
+		headers.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(username+":"+password)))
+	case "apikey":
+		key := c.resolve(itemAuth.value("apikey", "key"))
+		value := c.resolve(itemAuth.value("apikey", "value"))
+		if key == "" {
+			return
+		}
+		if itemAuth.value("apikey", "in") == "query" {
+			query := parsed.Query()
+			query.Set(key, value)
+			parsed.RawQuery = query.Encode()
+		} else {
+			headers.Set(key, value)
+		}
+	}
+}
+
+// resolve replaces {{variables}} in data with their values.
+// Unknown variables are kept as is in the data.
+func (c *collectionParser) resolve(data string) string {
+	if !strings.Contains(data, "{{") {
+		return data
+	}
+	return variableRegex.ReplaceAllStringFunc(data, func(match string) string {
+		name := variableRegex.FindStringSubmatch(match)[1]
+		if value, ok := c.variables[name]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// readJSONFile reads and decodes a json file into data
+func readJSONFile(path string, data interface{}) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return json.NewDecoder(file).Decode(data)
+}
+
+// defaultIfEmpty returns the fallback value if value is empty
+func defaultIfEmpty(value, fallback string) string {
+	if strings.TrimSpace(value) == "" {
+		return fallback
+	}
+	return value
+}
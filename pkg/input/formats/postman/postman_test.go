@@ -0,0 +1,36 @@
+package postman
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/input/formats"
+	"github.com/khulnasoft-lab/vulmap/pkg/input/types"
+)
+
+func TestPostmanParse(t *testing.T) {
+	format := New()
+	format.SetOptions(formats.InputFormatOptions{PostmanEnvironmentFile: "testdata/environment.json"})
+
+	var gotRequests []*types.RequestResponse
+	err := format.Parse("testdata/collection.json", func(request *types.RequestResponse) bool {
+		gotRequests = append(gotRequests, request)
+		return true
+	})
+	require.NoError(t, err, "could not parse postman collection")
+	require.Len(t, gotRequests, 3, "could not get correct number of requests")
+
+	require.Equal(t, "http://localhost:8080/products?page=1", gotRequests[0].URL)
+	require.Equal(t, "Bearer staging-token", gotRequests[0].Request.Headers.Get("Authorization"))
+	require.Equal(t, "application/json", gotRequests[0].Request.Headers.Get("Accept"))
+
+	require.Equal(t, "http://localhost:8080/products?api_key=secret", gotRequests[1].URL)
+	require.Empty(t, gotRequests[1].Request.Headers.Get("Authorization"))
+	require.Equal(t, `{"name": "phone"}`, gotRequests[1].Request.Body)
+	require.Equal(t, "application/json", gotRequests[1].Request.Headers.Get("Content-Type"))
+
+	require.Equal(t, "POST", gotRequests[2].Request.Method)
+	require.Equal(t, "Basic YWRtaW46YWRtaW4=", gotRequests[2].Request.Headers.Get("Authorization"))
+	require.Equal(t, "remember=true", gotRequests[2].Request.Body)
+}
@@ -0,0 +1,166 @@
+package postman
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"strings"
+)
+
+// collection is a Postman v2.1 collection
+type collection struct {
+	Item     []*item     `json:"item"`
+	Auth     *auth       `json:"auth"`
+	Variable []*keyValue `json:"variable"`
+}
+
+// environment is a Postman environment
+type environment struct {
+	Values []*struct {
+		keyValue
+		Enabled *bool `json:"enabled"`
+	} `json:"values"`
+}
+
+// item is either a folder containing items or a request
+type item struct {
+	Name    string   `json:"name"`
+	Item    []*item  `json:"item"`
+	Auth    *auth    `json:"auth"`
+	Request *request `json:"request"`
+}
+
+type request struct {
+	Method string       `json:"method"`
+	Header []*keyValue  `json:"header"`
+	URL    requestURL   `json:"url"`
+	Body   *requestBody `json:"body"`
+	Auth   *auth        `json:"auth"`
+}
+
+// requestURL is the url of a request which can either
+// be a string or an object containing the raw url.
+type requestURL struct {
+	Raw string `json:"raw"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface
+func (u *requestURL) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		return json.Unmarshal(data, &u.Raw)
+	}
+	type alias requestURL
+	return json.Unmarshal(data, (*alias)(u))
+}
+
+// String returns the raw url of request
+func (u requestURL) String() string {
+	return u.Raw
+}
+
+type requestBody struct {
+	Mode       string      `json:"mode"`
+	Disabled   bool        `json:"disabled"`
+	Raw        string      `json:"raw"`
+	URLEncoded []*keyValue `json:"urlencoded"`
+	FormData   []*keyValue `json:"formdata"`
+	GraphQL    struct {
+		Query     string `json:"query"`
+		Variables string `json:"variables"`
+	} `json:"graphql"`
+	Options struct {
+		Raw struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	} `json:"options"`
+}
+
+// auth is an auth block of collection, folder or request
+type auth struct {
+	Type   string      `json:"type"`
+	Bearer []*keyValue `json:"bearer"`
+	Basic  []*keyValue `json:"basic"`
+	APIKey []*keyValue `json:"apikey"`
+}
+
+// value returns the value of a key of an auth type
+func (a *auth) value(authType, key string) string {
+	var values []*keyValue
+	switch authType {
+	case "bearer":
+		values = a.Bearer
+	case "basic":
+		values = a.Basic
+	case "apikey":
+		values = a.APIKey
+	}
+	for _, item := range values {
+		if item.Key == key {
+			return item.stringValue()
+		}
+	}
+	return ""
+}
+
+// keyValue is a generic key value pair of postman collections
+type keyValue struct {
+	Key      string      `json:"key"`
+	Value    interface{} `json:"value"`
+	Type     string      `json:"type"`
+	Src      interface{} `json:"src"`
+	Disabled bool        `json:"disabled"`
+}
+
+// stringValue returns the value as string
+func (k *keyValue) stringValue() string {
+	switch v := k.Value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// multipartBuilder builds a multipart/form-data body
+type multipartBuilder struct {
+	buffer bytes.Buffer
+	writer *multipart.Writer
+}
+
+// writeField writes a field to the multipart body. File fields
+// are written with the field value as filename and placeholder content.
+func (m *multipartBuilder) writeField(key, value string, isFile bool) error {
+	if m.writer == nil {
+		m.writer = multipart.NewWriter(&m.buffer)
+	}
+	if !isFile {
+		return m.writer.WriteField(key, value)
+	}
+	filename := value
+	if index := strings.LastIndexAny(filename, `/\`); index != -1 {
+		filename = filename[index+1:]
+	}
+	if filename == "" {
+		filename = "file.txt"
+	}
+	part, err := m.writer.CreateFormFile(key, filename)
+	if err != nil {
+		return err
+	}
+	_, err = part.Write([]byte("test"))
+	return err
+}
+
+// finish returns the built body and content type
+func (m *multipartBuilder) finish() (string, string, error) {
+	if m.writer == nil {
+		return "", "", nil
+	}
+	if err := m.writer.Close(); err != nil {
+		return "", "", err
+	}
+	return m.buffer.String(), m.writer.FormDataContentType(), nil
+}
@@ -0,0 +1,85 @@
+// Package zap implements an input format which parses the messages
+// exported by OWASP ZAP ("Export Messages to File") into http requests.
+package zap
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/khulnasoft-lab/vulmap/pkg/input/formats"
+	"github.com/khulnasoft-lab/vulmap/pkg/input/types"
+)
+
+var (
+	// messageSeparator separates the messages of a zap export
+	messageSeparator = regexp.MustCompile(`(?m)^==== \d+ ==========\r?\n`)
+	// responseStatusLine matches the status line of the response in a message
+	responseStatusLine = regexp.MustCompile(`(?m)^HTTP/\d(?:\.\d)? \d{3}`)
+)
+
+// ZapFormat is a OWASP ZAP message export parser
+type ZapFormat struct{}
+
+// New creates a new OWASP ZAP message export parser
+func New() *ZapFormat {
+	return &ZapFormat{}
+}
+
+var _ formats.Format = &ZapFormat{}
+
+// Name returns the name of the format
+func (z *ZapFormat) Name() string {
+	return "zap"
+}
+
+// SetOptions sets the options for the input format
+func (z *ZapFormat) SetOptions(options formats.InputFormatOptions) {}
+
+// Parse parses the input and calls the provided callback
+// function for each RawRequest it discovers.
+func (z *ZapFormat) Parse(input string, resultsCb formats.ParseReqRespCallback) error {
+	data, err := os.ReadFile(input)
+	if err != nil {
+		return errors.Wrap(err, "could not read zap messages file")
+	}
+	for _, message := range messageSeparator.Split(string(data), -1) {
+		if strings.TrimSpace(message) == "" {
+			continue
+		}
+		rr, err := parseMessage(message)
+		if err != nil {
+			gologger.Warning().Msgf("zap: could not parse message: %s\n", err)
+			continue
+		}
+		if !resultsCb(rr) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// parseMessage parses a single exported message which contains
+// the request followed by the response of the message.
+func parseMessage(message string) (*types.RequestResponse, error) {
+	rawRequest, rawResponse := message, ""
+	if loc := responseStatusLine.FindStringIndex(message); loc != nil {
+		rawRequest, rawResponse = message[:loc[0]], message[loc[0]:]
+	}
+	// zap separates the request body and the response with a new line
+	rawRequest = strings.TrimSuffix(strings.TrimSuffix(rawRequest, "\n"), "\r")
+
+	rr, err := types.ParseRawRequest(rawRequest, "")
+	if err != nil {
+		return nil, err
+	}
+	if rawResponse != "" {
+		if resp, err := types.ParseRawResponse(rawResponse); err == nil {
+			rr.Response = resp
+		}
+	}
+	return rr, nil
+}
@@ -0,0 +1,31 @@
+package zap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/input/types"
+)
+
+func TestZapParse(t *testing.T) {
+	format := New()
+
+	var gotRequests []*types.RequestResponse
+	err := format.Parse("testdata/zap.txt", func(request *types.RequestResponse) bool {
+		gotRequests = append(gotRequests, request)
+		return true
+	})
+	require.NoError(t, err, "could not parse zap messages")
+	require.Len(t, gotRequests, 2, "could not get correct number of requests")
+
+	require.Equal(t, "http://localhost:8080/search?q=test", gotRequests[0].URL)
+	require.Equal(t, "GET", gotRequests[0].Request.Method)
+	require.Equal(t, "Mozilla/5.0", gotRequests[0].Request.Headers.Get("User-Agent"))
+	require.Equal(t, 200, gotRequests[0].Response.StatusCode)
+
+	require.Equal(t, "http://localhost:8080/api/users", gotRequests[1].URL)
+	require.Equal(t, "POST", gotRequests[1].Request.Method)
+	require.Equal(t, `{"name":"admin"}`, gotRequests[1].Request.Body)
+	require.Equal(t, 201, gotRequests[1].Response.StatusCode)
+}
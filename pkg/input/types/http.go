@@ -0,0 +1,124 @@
+// Package types contains the types shared by request based input
+// formats like openapi, burp, har and postman.
+package types
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// RequestResponse is a single request (and optionally its response)
+// obtained from a request based input source.
+type RequestResponse struct {
+	// URL is the complete URL of the request
+	URL string `json:"url"`
+	// Request is the http request of the pair
+	Request *HttpRequest `json:"request"`
+	// Response is the http response of the pair if one was captured
+	Response *HttpResponse `json:"response,omitempty"`
+}
+
+// HttpRequest is a http request from an input source
+type HttpRequest struct {
+	// Method is the http method of the request
+	Method string `json:"method"`
+	// Headers contains the headers of the request
+	Headers http.Header `json:"headers,omitempty"`
+	// Body is the raw body of the request
+	Body string `json:"body,omitempty"`
+}
+
+// HttpResponse is a http response from an input source
+type HttpResponse struct {
+	// StatusCode is the status code of the response
+	StatusCode int `json:"statusCode,omitempty"`
+	// Headers contains the headers of the response
+	Headers http.Header `json:"headers,omitempty"`
+	// Body is the raw body of the response
+	Body string `json:"body,omitempty"`
+}
+
+// hopHeaders are headers computed by the http client when
+// the request is sent and are hence skipped while building it.
+var hopHeaders = []string{"Content-Length", "Connection", "Transfer-Encoding"}
+
+// BuildRequest builds a retryablehttp request from the request-response pair
+func (rr *RequestResponse) BuildRequest() (*retryablehttp.Request, error) {
+	method := http.MethodGet
+	var body interface{}
+	var headers http.Header
+	if rr.Request != nil {
+		if rr.Request.Method != "" {
+			method = strings.ToUpper(rr.Request.Method)
+		}
+		if rr.Request.Body != "" {
+			body = rr.Request.Body
+		}
+		headers = rr.Request.Headers
+	}
+	req, err := retryablehttp.NewRequest(method, rr.URL, body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	for _, header := range hopHeaders {
+		req.Header.Del(header)
+	}
+	// update host of request and not URL
+	// URL.Host is used to dial the connection
+	if host := req.Header.Get("Host"); host != "" {
+		req.Request.Host = host
+	}
+	return req, nil
+}
+
+// ID returns a unique id for the request-response pair
+// based on the method, url, headers and body of request.
+func (rr *RequestResponse) ID() string {
+	var builder strings.Builder
+	builder.WriteString(rr.URL)
+	if rr.Request != nil {
+		builder.WriteString(rr.Request.Method)
+		keys := make([]string, 0, len(rr.Request.Headers))
+		for key := range rr.Request.Headers {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			builder.WriteString(key)
+			builder.WriteString(strings.Join(rr.Request.Headers[key], ","))
+		}
+		builder.WriteString(rr.Request.Body)
+	}
+	hash := sha1.Sum([]byte(builder.String()))
+	return hex.EncodeToString(hash[:])
+}
+
+// Clone returns a copy of the request-response pair
+func (rr *RequestResponse) Clone() *RequestResponse {
+	cloned := &RequestResponse{URL: rr.URL}
+	if rr.Request != nil {
+		cloned.Request = &HttpRequest{
+			Method:  rr.Request.Method,
+			Headers: rr.Request.Headers.Clone(),
+			Body:    rr.Request.Body,
+		}
+	}
+	if rr.Response != nil {
+		cloned.Response = &HttpResponse{
+			StatusCode: rr.Response.StatusCode,
+			Headers:    rr.Response.Headers.Clone(),
+			Body:       rr.Response.Body,
+		}
+	}
+	return cloned
+}
@@ -0,0 +1,95 @@
+package types
+
+import (
+	"bufio"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ParseRawRequest parses a raw http request into a RequestResponse. baseURL
+// (scheme://host:port) is used to build the request URL for requests without
+// an absolute url in request line, otherwise the Host header is used.
+func ParseRawRequest(raw, baseURL string) (*RequestResponse, error) {
+	head, body := splitRawMessage(raw)
+	head = normalizeRequestLine(head)
+
+	req, err := http.ReadRequest(bufio.NewReader(strings.NewReader(head + "\r\n\r\n")))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read raw request")
+	}
+	var rawURL string
+	switch {
+	case req.URL.IsAbs():
+		rawURL = req.URL.String()
+	case baseURL != "":
+		rawURL = strings.TrimSuffix(baseURL, "/") + req.RequestURI
+	case req.Host != "":
+		rawURL = "http://" + req.Host + req.RequestURI
+	default:
+		return nil, errors.New("could not find host in raw request")
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse request url")
+	}
+
+	headers := req.Header.Clone()
+	// http.ReadRequest moves the Host header to the request, add it back
+	// only when it differs from the url host (virtual host routing etc)
+	if req.Host != "" && req.Host != parsed.Host {
+		headers.Set("Host", req.Host)
+	}
+	rr := &RequestResponse{
+		URL: rawURL,
+		Request: &HttpRequest{
+			Method:  req.Method,
+			Headers: headers,
+			Body:    body,
+		},
+	}
+	return rr, nil
+}
+
+// ParseRawResponse parses a raw http response
+func ParseRawResponse(raw string) (*HttpResponse, error) {
+	head, body := splitRawMessage(raw)
+
+	resp, err := http.ReadResponse(bufio.NewReader(strings.NewReader(head+"\r\n\r\n")), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read raw response")
+	}
+	_ = resp.Body.Close()
+	return &HttpResponse{
+		StatusCode: resp.StatusCode,
+		Headers:    resp.Header,
+		Body:       body,
+	}, nil
+}
+
+// splitRawMessage splits a raw http message into its head and body
+func splitRawMessage(raw string) (string, string) {
+	if index := strings.Index(raw, "\r\n\r\n"); index != -1 {
+		return raw[:index], raw[index+4:]
+	}
+	if index := strings.Index(raw, "\n\n"); index != -1 {
+		return raw[:index], raw[index+2:]
+	}
+	return strings.TrimRight(raw, "\r\n"), ""
+}
+
+// normalizeRequestLine replaces the protocol of request line with HTTP/1.1
+// as requests captured over HTTP/2 can't be parsed by the http package.
+func normalizeRequestLine(head string) string {
+	line, rest, _ := strings.Cut(head, "\n")
+	line = strings.TrimSuffix(line, "\r")
+	if parts := strings.Split(line, " "); len(parts) == 3 && strings.HasPrefix(parts[2], "HTTP/2") {
+		line = parts[0] + " " + parts[1] + " HTTP/1.1"
+	}
+	if rest == "" {
+		return line
+	}
+	return line + "\r\n" + rest
+}
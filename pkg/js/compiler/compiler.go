@@ -28,6 +28,7 @@ import (
 	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/librsync"
 	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libsmb"
 	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libsmtp"
+	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libsnmp"
 	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libssh"
 	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libstructs"
 	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libtelnet"
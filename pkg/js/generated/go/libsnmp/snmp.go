@@ -0,0 +1,36 @@
+package snmp
+
+import (
+	lib_snmp "github.com/khulnasoft-lab/vulmap/pkg/js/libs/snmp"
+
+	"github.com/dop251/goja"
+	"github.com/khulnasoft-lab/vulmap/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("vulmap/snmp")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"SNMPClient":   func() lib_snmp.SNMPClient { return lib_snmp.SNMPClient{} },
+			"SNMPResponse": func() lib_snmp.SNMPResponse { return lib_snmp.SNMPResponse{} },
+			"SNMPVariable": func() lib_snmp.SNMPVariable { return lib_snmp.SNMPVariable{} },
+
+			// Types (pointer type)
+			"NewSNMPClient":   func() *lib_snmp.SNMPClient { return &lib_snmp.SNMPClient{} },
+			"NewSNMPResponse": func() *lib_snmp.SNMPResponse { return &lib_snmp.SNMPResponse{} },
+			"NewSNMPVariable": func() *lib_snmp.SNMPVariable { return &lib_snmp.SNMPVariable{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
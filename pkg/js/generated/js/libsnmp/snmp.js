@@ -0,0 +1,110 @@
+/** @module snmp */
+
+/**
+ * @class
+ * @classdesc SNMPClient is a client for the SNMP protocol supporting v1, v2c and v3. Versions 1 and 2c authenticate with the Community string, version 3 uses the USM credentials (Username, AuthProtocol, AuthPassword, PrivProtocol, PrivPassword, ContextName). Timeout is in seconds.
+ */
+class SNMPClient {
+    /**
+    * @method
+    * @description Get retrieves the values of the given OIDs.
+    * @param {string} host - The host of the SNMP agent.
+    * @param {int} port - The port of the SNMP agent (default 161).
+    * @param {string[]} oids - The OIDs to retrieve.
+    * @returns {SNMPResponse} - The variables returned by the agent.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/snmp');
+    * let c = m.SNMPClient();
+    * c.Community = 'public';
+    * let response = c.Get('localhost', 161, ['1.3.6.1.2.1.1.1.0']);
+    */
+    Get(host, port, oids) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description GetNext retrieves the values of the variables following the given OIDs.
+    * @param {string} host - The host of the SNMP agent.
+    * @param {int} port - The port of the SNMP agent (default 161).
+    * @param {string[]} oids - The OIDs preceding the variables to retrieve.
+    * @returns {SNMPResponse} - The variables returned by the agent.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/snmp');
+    * let c = m.SNMPClient();
+    * c.Community = 'public';
+    * let response = c.GetNext('localhost', 161, ['1.3.6.1.2.1.1']);
+    */
+    GetNext(host, port, oids) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Walk retrieves all the variables of the subtree under the root OID. GETBULK requests are used for v2c and v3, and GETNEXT requests for v1.
+    * @param {string} host - The host of the SNMP agent.
+    * @param {int} port - The port of the SNMP agent (default 161).
+    * @param {string} rootOID - The root OID of the subtree to walk.
+    * @returns {SNMPResponse} - The variables of the subtree.
+    * @throws {error} - The error encountered during the walk.
+    * @example
+    * let m = require('vulmap/snmp');
+    * let c = m.SNMPClient();
+    * c.Version = '3';
+    * c.Username = 'admin';
+    * c.AuthProtocol = 'SHA';
+    * c.AuthPassword = 'password';
+    * let response = c.Walk('localhost', 161, '1.3.6.1.2.1.1');
+    */
+    Walk(host, port, rootOID) {
+        // implemented in go
+    };
+};
+
+/**
+ * @class
+ * @classdesc SNMPResponse contains the variables returned by the agent. It is converted to "OID = Type: Value" lines when returned from the script.
+ */
+class SNMPResponse {
+    /**
+    * @method
+    * @description Value returns the value of the variable with the given OID or an empty string if the response does not contain it.
+    * @param {string} oid - The OID of the variable.
+    * @returns {string} - The value of the variable.
+    * @example
+    * let m = require('vulmap/snmp');
+    * let c = m.SNMPClient();
+    * let response = c.Get('localhost', 161, ['1.3.6.1.2.1.1.1.0']);
+    * let sysDescr = response.Value('1.3.6.1.2.1.1.1.0');
+    */
+    Value(oid) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description String returns the variables as "OID = Type: Value" lines.
+    * @returns {string} - The variables of the response.
+    * @example
+    * let m = require('vulmap/snmp');
+    * let c = m.SNMPClient();
+    * let response = c.Walk('localhost', 161, '1.3.6.1.2.1.1');
+    * log(response.String());
+    */
+    String() {
+        // implemented in go
+    };
+};
+
+/**
+ * @typedef {object} SNMPVariable
+ * @description SNMPVariable is a variable binding returned by the agent with its OID, Type and Value. Non printable octet strings are hex encoded.
+ */
+const SNMPVariable = {};
+
+module.exports = {
+    SNMPClient: SNMPClient,
+    SNMPResponse: SNMPResponse,
+};
\ No newline at end of file
@@ -0,0 +1,332 @@
+package snmp
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolstate"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultPort    = 161
+	defaultTimeout = 5
+)
+
+// SNMPClient is a client for the SNMP protocol supporting v1, v2c and v3.
+//
+// Versions 1 and 2c authenticate with the Community string, version 3
+// uses the USM credentials. The security level of v3 requests is chosen
+// from the provided passwords.
+type SNMPClient struct {
+	// Version is the SNMP version to use: 1, 2c (default) or 3.
+	Version string
+	// Community is the community string used by v1 and v2c.
+	Community string
+
+	// Username is the v3 USM user name.
+	Username string
+	// AuthProtocol is the v3 authentication protocol: MD5, SHA (default),
+	// SHA224, SHA256, SHA384 or SHA512.
+	AuthProtocol string
+	// AuthPassword is the v3 authentication password.
+	AuthPassword string
+	// PrivProtocol is the v3 privacy protocol: DES, AES (default), AES192,
+	// AES256, AES192C or AES256C.
+	PrivProtocol string
+	// PrivPassword is the v3 privacy password.
+	PrivPassword string
+	// ContextName is the optional v3 context name.
+	ContextName string
+
+	// Timeout is the timeout of a request in seconds (default 5).
+	Timeout int
+	// Retries is the number of times a request is retried.
+	Retries int
+}
+
+// SNMPVariable is a variable binding returned by the agent.
+type SNMPVariable struct {
+	// OID is the object identifier of the variable.
+	OID string
+	// Type is the ASN.1 type of the value, e.g. OctetString or Counter32.
+	Type string
+	// Value is the string representation of the value. Non printable
+	// octet strings are hex encoded.
+	Value string
+}
+
+// SNMPResponse contains the variables returned by the agent.
+type SNMPResponse struct {
+	Variables []SNMPVariable
+}
+
+// Value returns the value of the variable with the given OID
+// or an empty string if the response does not contain it.
+func (r *SNMPResponse) Value(oid string) string {
+	oid = strings.TrimPrefix(oid, ".")
+	for _, variable := range r.Variables {
+		if strings.TrimPrefix(variable.OID, ".") == oid {
+			return variable.Value
+		}
+	}
+	return ""
+}
+
+// String returns the variables as "OID = Type: Value" lines.
+func (r *SNMPResponse) String() string {
+	builder := &strings.Builder{}
+	for _, variable := range r.Variables {
+		builder.WriteString(fmt.Sprintf("%s = %s: %s\n", variable.OID, variable.Type, variable.Value))
+	}
+	return builder.String()
+}
+
+// Get retrieves the values of the given OIDs.
+func (c *SNMPClient) Get(host string, port int, oids []string) (*SNMPResponse, error) {
+	client, err := c.connect(host, port)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Conn.Close()
+
+	packet, err := client.Get(oids)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get oids")
+	}
+	return packetToResponse(packet)
+}
+
+// GetNext retrieves the values of the variables following the given OIDs.
+func (c *SNMPClient) GetNext(host string, port int, oids []string) (*SNMPResponse, error) {
+	client, err := c.connect(host, port)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Conn.Close()
+
+	packet, err := client.GetNext(oids)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get next oids")
+	}
+	return packetToResponse(packet)
+}
+
+// Walk retrieves all the variables of the subtree under the root OID.
+//
+// GETBULK requests are used for v2c and v3, and GETNEXT requests for v1.
+func (c *SNMPClient) Walk(host string, port int, rootOID string) (*SNMPResponse, error) {
+	client, err := c.connect(host, port)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Conn.Close()
+
+	response := &SNMPResponse{}
+	walkFn := func(pdu gosnmp.SnmpPDU) error {
+		response.Variables = append(response.Variables, toVariable(pdu))
+		return nil
+	}
+	if client.Version == gosnmp.Version1 {
+		err = client.Walk(rootOID, walkFn)
+	} else {
+		err = client.BulkWalk(rootOID, walkFn)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not walk %s", rootOID)
+	}
+	return response, nil
+}
+
+// connect validates the configuration of the client and opens the
+// socket to the agent.
+func (c *SNMPClient) connect(host string, port int) (*gosnmp.GoSNMP, error) {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	if port == 0 {
+		port = defaultPort
+	}
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	// gosnmp opens the socket to the agent itself, the agent is resolved
+	// by the vulmap dialer and the socket is checked by its control hook.
+	target, err := resolveAgent(host)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &gosnmp.GoSNMP{
+		Target:    target,
+		Port:      uint16(port),
+		Community: c.Community,
+		Timeout:   time.Duration(timeout) * time.Second,
+		Retries:   c.Retries,
+		MaxOids:   gosnmp.MaxOids,
+		Control:   allowAgent,
+	}
+
+	switch c.Version {
+	case "1":
+		client.Version = gosnmp.Version1
+	case "", "2", "2c":
+		client.Version = gosnmp.Version2c
+	case "3":
+		if err := c.configureV3(client); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf("invalid snmp version %s", c.Version)
+	}
+
+	if err := client.Connect(); err != nil {
+		return nil, errors.Wrap(err, "could not connect to snmp agent")
+	}
+	return client, nil
+}
+
+// resolveAgent returns the address of the agent resolved by the vulmap dialer
+func resolveAgent(host string) (string, error) {
+	data, err := protocolstate.Dialer.GetDNSData(host)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not resolve snmp agent %s", host)
+	}
+	switch {
+	case len(data.A) > 0:
+		return data.A[0], nil
+	case len(data.AAAA) > 0:
+		return data.AAAA[0], nil
+	}
+	return "", errors.Errorf("could not resolve snmp agent %s", host)
+}
+
+// allowAgent is the control hook of the sockets opened by gosnmp
+// denying agent addresses which are not allowed by the network policy.
+func allowAgent(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if !protocolstate.IsHostAllowed(host) {
+		return protocolstate.ErrHostDenied.Msgf(host)
+	}
+	return nil
+}
+
+// configureV3 sets up the USM security parameters of the client.
+func (c *SNMPClient) configureV3(client *gosnmp.GoSNMP) error {
+	if c.Username == "" {
+		return errors.New("username is required for snmp v3")
+	}
+	params := &gosnmp.UsmSecurityParameters{
+		UserName:               c.Username,
+		AuthenticationProtocol: gosnmp.NoAuth,
+		PrivacyProtocol:        gosnmp.NoPriv,
+	}
+	client.Version = gosnmp.Version3
+	client.SecurityModel = gosnmp.UserSecurityModel
+	client.ContextName = c.ContextName
+	client.MsgFlags = gosnmp.NoAuthNoPriv
+
+	if c.AuthPassword != "" {
+		protocol, ok := authProtocols[strings.ToUpper(c.AuthProtocol)]
+		if !ok {
+			return errors.Errorf("invalid snmp v3 auth protocol %s", c.AuthProtocol)
+		}
+		params.AuthenticationProtocol = protocol
+		params.AuthenticationPassphrase = c.AuthPassword
+		client.MsgFlags = gosnmp.AuthNoPriv
+
+		if c.PrivPassword != "" {
+			protocol, ok := privProtocols[strings.ToUpper(c.PrivProtocol)]
+			if !ok {
+				return errors.Errorf("invalid snmp v3 priv protocol %s", c.PrivProtocol)
+			}
+			params.PrivacyProtocol = protocol
+			params.PrivacyPassphrase = c.PrivPassword
+			client.MsgFlags = gosnmp.AuthPriv
+		}
+	} else if c.PrivPassword != "" {
+		return errors.New("auth password is required to use snmp v3 privacy")
+	}
+	client.SecurityParameters = params
+	return nil
+}
+
+var authProtocols = map[string]gosnmp.SnmpV3AuthProtocol{
+	"":       gosnmp.SHA,
+	"MD5":    gosnmp.MD5,
+	"SHA":    gosnmp.SHA,
+	"SHA224": gosnmp.SHA224,
+	"SHA256": gosnmp.SHA256,
+	"SHA384": gosnmp.SHA384,
+	"SHA512": gosnmp.SHA512,
+}
+
+var privProtocols = map[string]gosnmp.SnmpV3PrivProtocol{
+	"":        gosnmp.AES,
+	"DES":     gosnmp.DES,
+	"AES":     gosnmp.AES,
+	"AES192":  gosnmp.AES192,
+	"AES256":  gosnmp.AES256,
+	"AES192C": gosnmp.AES192C,
+	"AES256C": gosnmp.AES256C,
+}
+
+// packetToResponse converts the response packet of the agent
+func packetToResponse(packet *gosnmp.SnmpPacket) (*SNMPResponse, error) {
+	if packet.Error != gosnmp.NoError {
+		return nil, errors.Errorf("snmp agent returned error %s at index %d", packet.Error, packet.ErrorIndex)
+	}
+	response := &SNMPResponse{Variables: make([]SNMPVariable, 0, len(packet.Variables))}
+	for _, pdu := range packet.Variables {
+		response.Variables = append(response.Variables, toVariable(pdu))
+	}
+	return response, nil
+}
+
+// toVariable converts a variable binding to its string representation
+func toVariable(pdu gosnmp.SnmpPDU) SNMPVariable {
+	variable := SNMPVariable{OID: pdu.Name, Type: pdu.Type.String()}
+
+	switch pdu.Type {
+	case gosnmp.Integer, gosnmp.Counter32, gosnmp.Gauge32, gosnmp.TimeTicks, gosnmp.Counter64, gosnmp.Uinteger32:
+		variable.Value = gosnmp.ToBigInt(pdu.Value).String()
+	case gosnmp.Null, gosnmp.NoSuchObject, gosnmp.NoSuchInstance, gosnmp.EndOfMibView:
+	default:
+		if value, ok := pdu.Value.([]byte); ok {
+			if isPrintable(value) {
+				variable.Value = string(value)
+			} else {
+				variable.Value = hex.EncodeToString(value)
+			}
+		} else if pdu.Value != nil {
+			variable.Value = fmt.Sprint(pdu.Value)
+		}
+	}
+	return variable
+}
+
+// isPrintable checks if the data is printable utf-8 text
+func isPrintable(data []byte) bool {
+	if !utf8.Valid(data) {
+		return false
+	}
+	for _, r := range string(data) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
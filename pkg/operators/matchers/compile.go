@@ -69,6 +69,16 @@ func (matcher *Matcher) CompileMatchers() error {
 		matcher.dslCompiled = append(matcher.dslCompiled, compiledExpression)
 	}
 
+	// Set up the diff options with default thresholds
+	if matcher.GetType() == DiffMatcher {
+		if matcher.Diff == nil {
+			matcher.Diff = &DiffOptions{}
+		}
+		if err := matcher.Diff.compile(); err != nil {
+			return err
+		}
+	}
+
 	// Set up the condition type, if any.
 	if matcher.Condition != "" {
 		matcher.condition, ok = ConditionTypes[matcher.Condition]
@@ -0,0 +1,224 @@
+package matchers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	sliceutil "github.com/khulnasoft-lab/utils/slice"
+	"golang.org/x/net/html"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+)
+
+const (
+	// BaselinePrefix is the prefix of the baseline response fields in the
+	// matcher data (eg. baseline_status_code, baseline_body).
+	BaselinePrefix = "baseline_"
+
+	defaultLengthBucketRatio   = 0.1
+	defaultWordCountRatio      = 0.1
+	defaultStructureSimilarity = 0.9
+)
+
+// diffChecks is the list of supported comparisons of diff matcher
+var diffChecks = []string{"status", "length", "words", "structure"}
+
+// DiffOptions are the options of diff matcher which compares a response
+// against the baseline response of the unmodified request.
+type DiffOptions struct {
+	// description: |
+	//   Checks is the list of comparisons to perform. All checks are performed by default.
+	// values:
+	//   - "status"
+	//   - "length"
+	//   - "words"
+	//   - "structure"
+	Checks []string `yaml:"checks,omitempty" json:"checks,omitempty" jsonschema:"title=comparisons to perform,description=Comparisons to perform between baseline and response,enum=status,enum=length,enum=words,enum=structure"`
+	// description: |
+	//   LengthBucketRatio is the relative width of the buckets response lengths are grouped in.
+	//   Responses with lengths in different buckets are different. Default is 0.1 (10%).
+	LengthBucketRatio float64 `yaml:"length-bucket-ratio,omitempty" json:"length-bucket-ratio,omitempty" jsonschema:"title=length bucket ratio,description=Relative width of length buckets"`
+	// description: |
+	//   WordCountRatio is the maximum relative difference of word count of similar responses.
+	//   Default is 0.1 (10%).
+	WordCountRatio float64 `yaml:"word-count-ratio,omitempty" json:"word-count-ratio,omitempty" jsonschema:"title=word count ratio,description=Maximum relative difference of word count"`
+	// description: |
+	//   StructureSimilarity is the minimum structural (DOM or JSON keys) similarity of
+	//   similar responses between 0 and 1. Default is 0.9.
+	StructureSimilarity float64 `yaml:"structure-similarity,omitempty" json:"structure-similarity,omitempty" jsonschema:"title=structure similarity,description=Minimum structural similarity between 0 and 1"`
+}
+
+// compile validates the options and sets up the defaults
+func (options *DiffOptions) compile() error {
+	for _, check := range options.Checks {
+		if !sliceutil.Contains(diffChecks, check) {
+			return fmt.Errorf("unknown diff check specified: %s", check)
+		}
+	}
+	if len(options.Checks) == 0 {
+		options.Checks = diffChecks
+	}
+	if options.LengthBucketRatio <= 0 {
+		options.LengthBucketRatio = defaultLengthBucketRatio
+	}
+	if options.WordCountRatio <= 0 {
+		options.WordCountRatio = defaultWordCountRatio
+	}
+	if options.StructureSimilarity <= 0 {
+		options.StructureSimilarity = defaultStructureSimilarity
+	}
+	if options.StructureSimilarity > 1 {
+		return fmt.Errorf("invalid structure similarity specified: %v", options.StructureSimilarity)
+	}
+	return nil
+}
+
+// BaselineData returns the baseline response fields of the data
+// with the baseline prefix removed from the field names.
+func BaselineData(data map[string]interface{}) map[string]interface{} {
+	baseline := make(map[string]interface{})
+	for k, v := range data {
+		if strings.HasPrefix(k, BaselinePrefix) {
+			baseline[strings.TrimPrefix(k, BaselinePrefix)] = v
+		}
+	}
+	return baseline
+}
+
+// MatchDiff matches a response part against the same part of the baseline
+// response. Checks which differ beyond thresholds are returned as snippets.
+//
+// With OR condition a single differing check is a match while AND
+// condition requires all the checks to differ.
+func (matcher *Matcher) MatchDiff(corpus, baseline string, data map[string]interface{}) (bool, []string) {
+	var differences []string
+	for _, check := range matcher.Diff.Checks {
+		var difference string
+		switch check {
+		case "status":
+			status, baselineStatus := types.ToString(data["status_code"]), types.ToString(data[BaselinePrefix+"status_code"])
+			if status != baselineStatus {
+				difference = fmt.Sprintf("status: %s -> %s", baselineStatus, status)
+			}
+		case "length":
+			if lengthBucket(len(baseline), matcher.Diff.LengthBucketRatio) != lengthBucket(len(corpus), matcher.Diff.LengthBucketRatio) {
+				difference = fmt.Sprintf("length: %d -> %d", len(baseline), len(corpus))
+			}
+		case "words":
+			words, baselineWords := len(strings.Fields(corpus)), len(strings.Fields(baseline))
+			if relativeDifference(words, baselineWords) > matcher.Diff.WordCountRatio {
+				difference = fmt.Sprintf("words: %d -> %d", baselineWords, words)
+			}
+		case "structure":
+			if similarity := structureSimilarity(baseline, corpus); similarity < matcher.Diff.StructureSimilarity {
+				difference = fmt.Sprintf("structure: %.2f similarity", similarity)
+			}
+		}
+
+		if difference == "" {
+			// If we are in an AND request and a check did not differ,
+			// return false as the AND condition fails on any single mismatch.
+			if matcher.condition == ANDCondition {
+				return false, []string{}
+			}
+			continue
+		}
+		differences = append(differences, difference)
+	}
+	return len(differences) > 0, differences
+}
+
+// lengthBucket returns the logarithmic bucket of a length
+func lengthBucket(length int, ratio float64) int {
+	return int(math.Log(float64(length+1)) / math.Log(1+ratio))
+}
+
+// relativeDifference returns the difference of two counts relative to the larger
+func relativeDifference(a, b int) float64 {
+	larger := math.Max(float64(a), float64(b))
+	if larger == 0 {
+		return 0
+	}
+	return math.Abs(float64(a-b)) / larger
+}
+
+// structureSimilarity returns the jaccard similarity of the json key paths
+// or html/xml element paths of two documents. Documents without structure
+// are considered similar.
+func structureSimilarity(a, b string) float64 {
+	first, second := documentStructure(a), documentStructure(b)
+	if len(first) == 0 && len(second) == 0 {
+		return 1
+	}
+	var intersection int
+	for path := range first {
+		if _, ok := second[path]; ok {
+			intersection++
+		}
+	}
+	union := len(first) + len(second) - intersection
+	return float64(intersection) / float64(union)
+}
+
+// documentStructure returns the set of key or element paths of a document
+func documentStructure(data string) map[string]struct{} {
+	paths := make(map[string]struct{})
+	trimmed := strings.TrimSpace(data)
+	switch {
+	case strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "["):
+		var value interface{}
+		if err := json.Unmarshal([]byte(trimmed), &value); err == nil {
+			jsonPaths(value, "$", paths)
+		}
+	case strings.HasPrefix(trimmed, "<"):
+		elementPaths(trimmed, paths)
+	}
+	return paths
+}
+
+// jsonPaths collects the key paths of a json value. Array
+// indexes are ignored so arrays of different sizes are similar.
+func jsonPaths(value interface{}, path string, paths map[string]struct{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			childPath := path + "." + key
+			paths[childPath] = struct{}{}
+			jsonPaths(child, childPath, paths)
+		}
+	case []interface{}:
+		for _, item := range v {
+			jsonPaths(item, path+"[]", paths)
+		}
+	}
+}
+
+// elementPaths collects the element paths (eg. html>body>div) of a html or xml document
+func elementPaths(data string, paths map[string]struct{}) {
+	tokenizer := html.NewTokenizer(strings.NewReader(data))
+	var stack []string
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return
+		case html.StartTagToken:
+			name, _ := tokenizer.TagName()
+			stack = append(stack, string(name))
+			paths[strings.Join(stack, ">")] = struct{}{}
+		case html.SelfClosingTagToken:
+			name, _ := tokenizer.TagName()
+			paths[strings.Join(append(stack, string(name)), ">")] = struct{}{}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			// pop up to the matching element to handle unclosed elements
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i] == string(name) {
+					stack = stack[:i]
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package matchers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffMatcherCompile(t *testing.T) {
+	m := &Matcher{Type: MatcherTypeHolder{MatcherType: DiffMatcher}}
+	err := m.CompileMatchers()
+	require.Nil(t, err, "could not compile diff matcher")
+	require.Equal(t, "body", m.Part, "could not set default part")
+	require.Equal(t, diffChecks, m.Diff.Checks, "could not set default checks")
+	require.Equal(t, defaultStructureSimilarity, m.Diff.StructureSimilarity, "could not set default similarity")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: DiffMatcher}, Diff: &DiffOptions{Checks: []string{"unknown"}}}
+	err = m.CompileMatchers()
+	require.NotNil(t, err, "could compile diff matcher with unknown check")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: DiffMatcher}, Words: []string{"test"}}
+	err = m.CompileMatchers()
+	require.NotNil(t, err, "could compile diff matcher with words")
+}
+
+func TestMatchDiff(t *testing.T) {
+	baseline := `{"user":{"id":1,"name":"test"},"roles":["admin"]}`
+	data := map[string]interface{}{
+		"status_code":          200,
+		"baseline_status_code": 200,
+	}
+
+	t.Run("similar", func(t *testing.T) {
+		m := &Matcher{Type: MatcherTypeHolder{MatcherType: DiffMatcher}}
+		require.Nil(t, m.CompileMatchers())
+
+		isMatched, matched := m.MatchDiff(`{"user":{"id":2,"name":"tset"},"roles":["admin"]}`, baseline, data)
+		require.False(t, isMatched, "could match similar responses")
+		require.Empty(t, matched)
+	})
+
+	t.Run("structure", func(t *testing.T) {
+		m := &Matcher{Type: MatcherTypeHolder{MatcherType: DiffMatcher}, Diff: &DiffOptions{Checks: []string{"structure"}}}
+		require.Nil(t, m.CompileMatchers())
+
+		isMatched, matched := m.MatchDiff(`{"error":{"code":500,"message":"syntax error"}}`, baseline, data)
+		require.True(t, isMatched, "could not match different structure")
+		require.Equal(t, []string{"structure: 0.00 similarity"}, matched)
+	})
+
+	t.Run("status", func(t *testing.T) {
+		m := &Matcher{Type: MatcherTypeHolder{MatcherType: DiffMatcher}}
+		require.Nil(t, m.CompileMatchers())
+
+		isMatched, matched := m.MatchDiff(baseline, baseline, map[string]interface{}{"status_code": 500, "baseline_status_code": 200})
+		require.True(t, isMatched, "could not match different status")
+		require.Equal(t, []string{"status: 200 -> 500"}, matched)
+	})
+
+	t.Run("and-condition", func(t *testing.T) {
+		m := &Matcher{Type: MatcherTypeHolder{MatcherType: DiffMatcher}, Condition: "and", Diff: &DiffOptions{Checks: []string{"length", "words"}}}
+		require.Nil(t, m.CompileMatchers())
+
+		longer := baseline + strings.Repeat(" padding", 20)
+		isMatched, matched := m.MatchDiff(longer, baseline, data)
+		require.True(t, isMatched, "could not match different length and words")
+		require.Len(t, matched, 2)
+
+		isMatched, _ = m.MatchDiff(baseline+strings.Repeat("x", 100), baseline, data)
+		require.False(t, isMatched, "could match with only length different")
+	})
+}
+
+func TestStructureSimilarity(t *testing.T) {
+	first := `<html><body><div><p>a</p></div><form><input name="q"/></form></body></html>`
+	second := `<html><body><div><p>b</p><p>c</p></div><form><input name="q"/></form></body></html>`
+	require.Equal(t, float64(1), structureSimilarity(first, second), "could not get similarity of same structure")
+
+	third := `<html><body><h1>Error</h1></body></html>`
+	require.Less(t, structureSimilarity(first, third), 0.5, "could get high similarity of different structure")
+
+	require.Equal(t, float64(1), structureSimilarity("plain text", "other text"), "could not get similarity of unstructured data")
+}
@@ -104,6 +104,16 @@ type Matcher struct {
 	//       []string{"//a[@target="_blank"]"}
 	XPath []string `yaml:"xpath,omitempty" json:"xpath,omitempty" jsonschema:"title=xpath queries to match in response,description=xpath are the XPath queries that will be evaluated against the response part of vulmap matching rules"`
 	// description: |
+	//   Diff compares the response with the baseline response of the unmodified request.
+	//
+	//   The matcher is used with fuzzing templates to detect anomalies in responses to fuzzed
+	//   requests. Status code, length bucket, word count and structure (DOM or JSON keys) are compared.
+	// examples:
+	//   - name: Diff matcher with custom thresholds
+	//     value: >
+	//       &DiffOptions{Checks: []string{"status", "structure"}, StructureSimilarity: 0.8}
+	Diff *DiffOptions `yaml:"diff,omitempty" json:"diff,omitempty" jsonschema:"title=diff options,description=Options for comparison with baseline response"`
+	// description: |
 	//   Encoding specifies the encoding for the words field if any.
 	// values:
 	//   - "hex"
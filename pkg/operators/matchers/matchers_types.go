@@ -27,6 +27,8 @@ const (
 	DSLMatcher
 	// name:xpath
 	XPathMatcher
+	// name:diff
+	DiffMatcher
 	limit
 )
 
@@ -39,6 +41,7 @@ var MatcherTypes = map[MatcherType]string{
 	BinaryMatcher: "binary",
 	DSLMatcher:    "dsl",
 	XPathMatcher:  "xpath",
+	DiffMatcher:   "diff",
 }
 
 // GetType returns the type of the matcher
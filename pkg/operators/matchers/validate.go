@@ -41,6 +41,8 @@ func (matcher *Matcher) Validate() error {
 		expectedFields = append(commonExpectedFields, "Regex", "Part", "Encoding", "CaseInsensitive")
 	case XPathMatcher:
 		expectedFields = append(commonExpectedFields, "XPath", "Part")
+	case DiffMatcher:
+		expectedFields = append(commonExpectedFields, "Diff", "Part")
 	}
 
 	if err = checkFields(matcher, matcherMap, expectedFields...); err != nil {
@@ -60,6 +60,7 @@ type StandardWriter struct {
 	severityColors   func(severity.Severity) string
 	storeResponse    bool
 	storeResponseDir string
+	redactor         func(string) string
 }
 
 var decolorizerRegex = regexp.MustCompile(`\x1B\[[0-9;]*[a-zA-Z]`)
@@ -146,6 +147,13 @@ type ResultEvent struct {
 	// CURLCommand is an optional curl command to reproduce the request
 	// Only applicable if the report is for HTTP.
 	CURLCommand string `json:"curl-command,omitempty"`
+	// ProtocolVersion is the protocol version of the response (e.g. HTTP/3.0)
+	// Only applicable if the report is for HTTP.
+	ProtocolVersion string `json:"protocol-version,omitempty"`
+	// RedirectChain contains the hops of the followed redirects, from
+	// the first request to the final response.
+	// Only applicable if the report is for HTTP.
+	RedirectChain []RedirectHop `json:"redirect-chain,omitempty"`
 	// MatcherStatus is the status of the match
 	MatcherStatus bool `json:"matcher-status"`
 	// Lines is the line count for the specified match
@@ -154,6 +162,24 @@ type ResultEvent struct {
 	FileToIndexPosition map[string]int `json:"-"`
 }
 
+// RedirectHop is a request and response of a followed redirect chain
+type RedirectHop struct {
+	// URL is the url requested in the hop
+	URL string `json:"url"`
+	// Method is the method of the request
+	Method string `json:"method,omitempty"`
+	// StatusCode is the status code of the response
+	StatusCode int `json:"status-code"`
+	// Location is the location header of the response
+	Location string `json:"location,omitempty"`
+	// Request is the dumped request of the hop
+	Request string `json:"request,omitempty"`
+	// ResponseHeaders are the dumped status line and headers of the response
+	ResponseHeaders string `json:"response-headers,omitempty"`
+	// Duration is the time in seconds taken by the hop
+	Duration float64 `json:"duration"`
+}
+
 // NewStandardWriter creates a new output writer based on user configurations
 func NewStandardWriter(options *types.Options) (*StandardWriter, error) {
 	resumeBool := false
@@ -211,8 +237,45 @@ func NewStandardWriter(options *types.Options) (*StandardWriter, error) {
 	return writer, nil
 }
 
+// SetRedactor sets the function used to redact secrets from
+// results, trace logs and stored responses.
+func (w *StandardWriter) SetRedactor(redactor func(string) string) {
+	w.redactor = redactor
+}
+
+// redact redacts the secrets from the data if a redactor is set
+func (w *StandardWriter) redact(data string) string {
+	if w.redactor == nil || data == "" {
+		return data
+	}
+	return w.redactor(data)
+}
+
+// redactEvent redacts the secrets from the result event in place
+// so that exported reports of the event are redacted as well.
+func (w *StandardWriter) redactEvent(event *ResultEvent) {
+	if w.redactor == nil {
+		return
+	}
+	event.Matched = w.redact(event.Matched)
+	event.Request = w.redact(event.Request)
+	event.Response = w.redact(event.Response)
+	event.CURLCommand = w.redact(event.CURLCommand)
+	for i := range event.RedirectChain {
+		hop := &event.RedirectChain[i]
+		hop.URL = w.redact(hop.URL)
+		hop.Location = w.redact(hop.Location)
+		hop.Request = w.redact(hop.Request)
+		hop.ResponseHeaders = w.redact(hop.ResponseHeaders)
+	}
+	for i, result := range event.ExtractedResults {
+		event.ExtractedResults[i] = w.redact(result)
+	}
+}
+
 // Write writes the event to file and/or screen.
 func (w *StandardWriter) Write(event *ResultEvent) error {
+	w.redactEvent(event)
 	// Enrich the result event with extra metadata on the template-path and url.
 	if event.TemplatePath != "" {
 		event.Template, event.TemplateURL = utils.TemplatePathURL(types.ToString(event.TemplatePath), types.ToString(event.TemplateID))
@@ -265,11 +328,11 @@ func (w *StandardWriter) Request(templatePath, input, requestType string, reques
 	}
 	request := &JSONLogRequest{
 		Template: templatePath,
-		Input:    input,
+		Input:    w.redact(input),
 		Type:     requestType,
 	}
 	if unwrappedErr := utils.UnwrapError(requestErr); unwrappedErr != nil {
-		request.Error = unwrappedErr.Error()
+		request.Error = w.redact(unwrappedErr.Error())
 	} else {
 		request.Error = "none"
 	}
@@ -373,7 +436,7 @@ func (w *StandardWriter) WriteStoreDebugData(host, templateID, eventType string,
 			fmt.Print(err)
 			return
 		}
-		_, _ = f.WriteString(fmt.Sprintln(data))
+		_, _ = f.WriteString(fmt.Sprintln(w.redact(data)))
 		f.Close()
 	}
 
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/khulnasoft-lab/clistats"
@@ -32,6 +33,11 @@ type Progress interface {
 	// IncrementFailedRequestsBy increments the number of requests counter by count
 	// along with errors.
 	IncrementFailedRequestsBy(count int64)
+	// IncrementThrottled increments the counter of responses of hosts
+	// throttling requests by 1.
+	IncrementThrottled()
+	// AddBackoffHosts adds a delta to the number of hosts being slowed down.
+	AddBackoffHosts(delta int)
 }
 
 var _ Progress = &StatsTicker{}
@@ -43,6 +49,9 @@ type StatsTicker struct {
 	outputJSON   bool
 	stats        clistats.StatisticsClient
 	tickDuration time.Duration
+	// backoffHosts is the gauge of hosts being slowed down which is
+	// exposed as a dynamic field as clistats counters only increase
+	backoffHosts atomic.Int64
 }
 
 // NewStatsTicker creates and returns a new progress tracking object.
@@ -85,6 +94,10 @@ func (p *StatsTicker) Init(hostCount int64, rulesCount int, requestCount int64)
 	p.stats.AddCounter("errors", uint64(0))
 	p.stats.AddCounter("matched", uint64(0))
 	p.stats.AddCounter("total", uint64(requestCount))
+	p.stats.AddCounter("throttled", uint64(0))
+	p.stats.AddDynamic("backoff", func(clistats.StatisticsClient) interface{} {
+		return p.backoffHosts.Load()
+	})
 
 	if p.active {
 		var printCallbackFunc clistats.DynamicCallback
@@ -142,6 +155,16 @@ func (p *StatsTicker) IncrementFailedRequestsBy(count int64) {
 	p.stats.IncrementCounter("errors", int(count))
 }
 
+// IncrementThrottled increments the counter of responses of hosts throttling requests by 1.
+func (p *StatsTicker) IncrementThrottled() {
+	p.stats.IncrementCounter("throttled", 1)
+}
+
+// AddBackoffHosts adds a delta to the number of hosts being slowed down.
+func (p *StatsTicker) AddBackoffHosts(delta int) {
+	p.backoffHosts.Add(int64(delta))
+}
+
 func (p *StatsTicker) makePrintCallback() func(stats clistats.StatisticsClient) interface{} {
 	return func(stats clistats.StatisticsClient) interface{} {
 		builder := &strings.Builder{}
@@ -187,6 +210,16 @@ func (p *StatsTicker) makePrintCallback() func(stats clistats.StatisticsClient)
 			builder.WriteString(clistats.String(errors))
 		}
 
+		if throttled, ok := stats.GetCounter("throttled"); ok && throttled > 0 && !p.cloud {
+			builder.WriteString(" | Throttled: ")
+			builder.WriteString(clistats.String(throttled))
+			if backoff, ok := stats.GetDynamic("backoff"); ok {
+				builder.WriteString(" (Backoff Hosts: ")
+				builder.WriteString(clistats.String(backoff(stats)))
+				builder.WriteRune(')')
+			}
+		}
+
 		if okRequests && okTotal {
 			if p.cloud {
 				builder.WriteString(" | Task: ")
@@ -246,6 +279,11 @@ func metricsMap(stats clistats.StatisticsClient) map[string]interface{} {
 	results["rps"] = clistats.String(uint64(float64(requests) / duration.Seconds()))
 	errors, _ := stats.GetCounter("errors")
 	results["errors"] = clistats.String(errors)
+	throttled, _ := stats.GetCounter("throttled")
+	results["throttled"] = clistats.String(throttled)
+	if backoff, ok := stats.GetDynamic("backoff"); ok {
+		results["backoffHosts"] = clistats.String(backoff(stats))
+	}
 
 	// nolint:gomnd // this is not a magic number
 	percentData := (float64(requests) * float64(100)) / float64(total)
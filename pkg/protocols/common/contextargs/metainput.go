@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	jsoniter "github.com/json-iterator/go"
+	"github.com/khulnasoft-lab/vulmap/pkg/input/types"
 )
 
 // MetaInput represents a target with metadata (TODO: replace with https://github.com/khulnasoft-lab/metainput)
@@ -15,6 +16,9 @@ type MetaInput struct {
 	Input string `json:"input,omitempty"`
 	// CustomIP to use for connection
 	CustomIP string `json:"customIP,omitempty"`
+	// ReqResp is the complete request (and optionally response) for
+	// the input obtained from request based input formats
+	ReqResp *types.RequestResponse `json:"reqResp,omitempty"`
 	// hash of the input
 	hash string `json:"-"`
 }
@@ -58,10 +62,14 @@ func (metaInput *MetaInput) Unmarshal(data string) error {
 }
 
 func (metaInput *MetaInput) Clone() *MetaInput {
-	return &MetaInput{
+	input := &MetaInput{
 		Input:    metaInput.Input,
 		CustomIP: metaInput.CustomIP,
 	}
+	if metaInput.ReqResp != nil {
+		input.ReqResp = metaInput.ReqResp.Clone()
+	}
+	return input
 }
 
 func (metaInput *MetaInput) PrettyPrint() string {
@@ -77,7 +85,11 @@ func (metaInput *MetaInput) GetScanHash(templateId string) string {
 	// but that totally changes the scanID/hash so to avoid that we compute hash only once
 	// and reuse it for all subsequent calls
 	if metaInput.hash == "" {
-		metaInput.hash = getMd5Hash(templateId + ":" + metaInput.Input + ":" + metaInput.CustomIP)
+		var reqRespID string
+		if metaInput.ReqResp != nil {
+			reqRespID = metaInput.ReqResp.ID()
+		}
+		metaInput.hash = getMd5Hash(templateId + ":" + metaInput.Input + ":" + metaInput.CustomIP + ":" + reqRespID)
 	}
 	return metaInput.hash
 }
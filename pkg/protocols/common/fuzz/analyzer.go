@@ -0,0 +1,83 @@
+package fuzz
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/fuzz/analyzers"
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+)
+
+// executeAnalyzerRule executes a payload containing the placeholder of the
+// analyzer of the rule. Requests are generated for each variant of the
+// placeholder and every injection point is analyzed by sending its variants.
+// Only the injection points confirmed by the analyzer are returned with the
+// request of the last variant to the callback.
+func (rule *Rule) executeAnalyzerRule(input *ExecuteRuleInput, payload string) error {
+	if input.SendRequest == nil {
+		return errors.Errorf("analyzer %s requires a request sender", rule.analyzer.Name())
+	}
+	variants, err := rule.analyzer.Variants(rule.Analyzer.Parameters)
+	if err != nil {
+		return err
+	}
+
+	// injection point -> variant -> generated request
+	generated := make(map[string]map[string]GeneratedRequest)
+	var injectionPoints []string
+	for _, variant := range variants {
+		variant := variant
+		collectInput := *input
+		collectInput.Callback = func(gr GeneratedRequest) bool {
+			key := strings.Join(gr.InjectionPoints, ",")
+			if _, ok := generated[key]; !ok {
+				generated[key] = make(map[string]GeneratedRequest)
+				injectionPoints = append(injectionPoints, key)
+			}
+			generated[key][variant] = gr
+			return true
+		}
+		if err := rule.executePartRule(&collectInput, strings.ReplaceAll(payload, rule.analyzer.Placeholder(), variant)); err != nil {
+			return err
+		}
+		input.InteractURLs = collectInput.InteractURLs
+	}
+
+	for _, injectionPoint := range injectionPoints {
+		requests := generated[injectionPoint]
+		result, err := rule.analyzer.Analyze(&analyzers.Options{
+			Parameters: rule.Analyzer.Parameters,
+			SendVariant: func(variant string) (time.Duration, error) {
+				gr, ok := requests[variant]
+				if !ok {
+					return 0, errors.Errorf("no request generated for variant %s", variant)
+				}
+				resp, err := input.SendRequest(gr.Request.Clone(context.TODO()))
+				if err != nil {
+					return 0, err
+				}
+				return resp.Duration, nil
+			},
+		})
+		if err != nil {
+			gologger.Verbose().Msgf("Could not analyze %s with %s analyzer: %s\n", injectionPoint, rule.analyzer.Name(), err)
+			continue
+		}
+		if !result.Matched {
+			continue
+		}
+		gr, ok := requests[variants[len(variants)-1]]
+		if !ok {
+			continue
+		}
+		gr.AnalyzerResult = result
+		if !input.Callback(gr) {
+			return types.ErrNoMoreRequests
+		}
+	}
+	return nil
+}
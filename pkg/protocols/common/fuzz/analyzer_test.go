@@ -0,0 +1,63 @@
+package fuzz
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/fuzz/analyzers"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/interactsh"
+)
+
+func TestExecuteAnalyzerRule(t *testing.T) {
+	options := &protocols.ExecutorOptions{
+		Interactsh: &interactsh.Client{},
+	}
+	rule := &Rule{
+		Part:     "query",
+		Type:     "postfix",
+		Mode:     "single",
+		Analyzer: &analyzers.AnalyzerTemplate{Name: "time_delay"},
+	}
+	err := rule.Compile(nil, options)
+	require.NoError(t, err, "could not compile rule")
+
+	req, err := retryablehttp.NewRequest("GET", "http://localhost:8080/?id=1&name=test", nil)
+	require.NoError(t, err, "can't build request")
+
+	// only the id parameter is vulnerable to the injected sleep
+	sleepRegex := regexp.MustCompile(`id=1[^&]*SLEEP(?:%28|\()(\d+)`)
+	var sent int
+	var confirmed []GeneratedRequest
+	err = rule.executeAnalyzerRule(&ExecuteRuleInput{
+		Input:       contextargs.New(),
+		BaseRequest: req,
+		SendRequest: func(req *retryablehttp.Request) (*Response, error) {
+			sent++
+			duration := 100 * time.Millisecond
+			if match := sleepRegex.FindStringSubmatch(req.URL.String()); match != nil {
+				delay, _ := strconv.Atoi(match[1])
+				duration += time.Duration(delay) * time.Second
+			}
+			return &Response{StatusCode: 200, Duration: duration}, nil
+		},
+		Callback: func(gr GeneratedRequest) bool {
+			confirmed = append(confirmed, gr)
+			return true
+		},
+	}, " AND SLEEP([SLEEPTIME])")
+	require.NoError(t, err, "could not execute analyzer rule")
+	require.Len(t, confirmed, 1, "could not get confirmed injection points")
+	require.Equal(t, []string{"id"}, confirmed[0].InjectionPoints, "could not get confirmed injection point")
+	require.True(t, confirmed[0].AnalyzerResult.Matched, "could not get analyzer result")
+	require.Equal(t, "5", sleepRegex.FindStringSubmatch(confirmed[0].Request.URL.String())[1], "could not get request of last variant")
+	// 9 requests for the vulnerable parameter and 2 for the other one
+	// as analysis stops at the first response faster than the delay.
+	require.Equal(t, 11, sent, "could not send correct number of analyzer requests")
+}
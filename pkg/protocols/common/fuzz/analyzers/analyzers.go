@@ -0,0 +1,99 @@
+// Package analyzers implements analyzers for fuzzing rules which confirm
+// injection points by sending controlled variants of a fuzzed request.
+package analyzers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+)
+
+// AnalyzerTemplate is the analyzer configuration of a fuzzing rule
+type AnalyzerTemplate struct {
+	// description: |
+	//   Name is the name of the analyzer to use.
+	// values:
+	//   - "time_delay"
+	Name string `yaml:"name" json:"name" jsonschema:"title=name of analyzer,description=Name of the analyzer to use,enum=time_delay"`
+	// description: |
+	//   Parameters is the optional parameters of the analyzer.
+	// examples:
+	//   - name: Time delay analyzer parameters
+	//     value: >
+	//       map[string]interface{}{"delays": []int{0, 2, 5}, "requests_limit": 3}
+	Parameters map[string]interface{} `yaml:"parameters,omitempty" json:"parameters,omitempty" jsonschema:"title=parameters of analyzer,description=Parameters of the analyzer"`
+}
+
+// Analyzer confirms an injection point by sending the fuzzed request
+// with different values for the placeholder of the analyzer.
+type Analyzer interface {
+	// Name returns the name of the analyzer
+	Name() string
+	// Placeholder returns the placeholder replaced in payloads by the variants
+	Placeholder() string
+	// Variants returns the values with which the placeholder is replaced
+	Variants(parameters map[string]interface{}) ([]string, error)
+	// Analyze sends variants of an injection point and analyzes the responses
+	Analyze(options *Options) (*Result, error)
+}
+
+// Options contains the options for analysis of an injection point
+type Options struct {
+	// Parameters are the parameters of the analyzer from template
+	Parameters map[string]interface{}
+	// SendVariant sends the request for a variant returning the response time
+	SendVariant func(variant string) (time.Duration, error)
+}
+
+// Result is the result of the analysis of an injection point
+type Result struct {
+	// Name is the name of the analyzer
+	Name string
+	// Matched is true if the injection point is confirmed by the analyzer
+	Matched bool
+	// Details contains the details of the analysis
+	Details string
+}
+
+// analyzers is the list of available analyzers
+var analyzers = map[string]Analyzer{
+	"time_delay": &timeDelayAnalyzer{},
+}
+
+// Get returns the analyzer for a name
+func Get(name string) (Analyzer, error) {
+	analyzer, ok := analyzers[name]
+	if !ok {
+		return nil, errors.Errorf("invalid analyzer specified: %s", name)
+	}
+	return analyzer, nil
+}
+
+// intParameter returns an integer parameter or the default value
+func intParameter(parameters map[string]interface{}, name string, defaultValue int) (int, error) {
+	value, ok := parameters[name]
+	if !ok {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.Atoi(types.ToString(value))
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid value for %s parameter", name)
+	}
+	return parsed, nil
+}
+
+// floatParameter returns a float parameter or the default value
+func floatParameter(parameters map[string]interface{}, name string, defaultValue float64) (float64, error) {
+	value, ok := parameters[name]
+	if !ok {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.ParseFloat(types.ToString(value), 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid value for %s parameter", name)
+	}
+	return parsed, nil
+}
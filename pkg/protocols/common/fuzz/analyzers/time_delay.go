@@ -0,0 +1,149 @@
+package analyzers
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+)
+
+const (
+	// defaultRequestsLimit is the default number of requests sent per delay
+	defaultRequestsLimit = 3
+	// defaultCorrelationThreshold is the default minimum correlation between
+	// requested delays and response times to report an injection point.
+	defaultCorrelationThreshold = 0.9
+	// defaultSlopeErrorRange is the default allowed deviation of the slope from 1
+	// (response time is expected to increase by a second per requested second).
+	defaultSlopeErrorRange = 0.5
+)
+
+// defaultDelays are the default delays in seconds sent for each injection point
+var defaultDelays = []string{"0", "2", "5"}
+
+// timeDelayAnalyzer confirms time based blind injections by sending the
+// fuzzed request with different delays several times and fitting a linear
+// regression of the response time against the requested delay.
+//
+// Parameters:
+//   - delays: delays in seconds to send (default 0, 2, 5)
+//   - requests_limit: number of requests sent per delay (default 3)
+//   - correlation_threshold: minimum correlation coefficient (default 0.9)
+//   - slope_error_range: allowed deviation of the slope from 1 (default 0.5)
+type timeDelayAnalyzer struct{}
+
+// Name returns the name of the analyzer
+func (a *timeDelayAnalyzer) Name() string {
+	return "time_delay"
+}
+
+// Placeholder returns the placeholder replaced in payloads by the delays
+func (a *timeDelayAnalyzer) Placeholder() string {
+	return "[SLEEPTIME]"
+}
+
+// Variants returns the delays in seconds for the placeholder
+func (a *timeDelayAnalyzer) Variants(parameters map[string]interface{}) ([]string, error) {
+	value, ok := parameters["delays"]
+	if !ok {
+		return defaultDelays, nil
+	}
+	if delays, ok := value.(string); ok {
+		value = strings.ReplaceAll(delays, ",", " ")
+	}
+	delays := types.ToStringSlice(value)
+	if len(delays) < 2 {
+		return nil, errors.New("at least two delays are required for time_delay analyzer")
+	}
+	for _, delay := range delays {
+		if parsed, err := strconv.Atoi(delay); err != nil || parsed < 0 {
+			return nil, errors.Errorf("invalid delay specified for time_delay analyzer: %s", delay)
+		}
+	}
+	return delays, nil
+}
+
+// Analyze sends the delays interleaved for requests_limit rounds and
+// reports a match only if the response times strongly correlate with
+// the requested delays.
+func (a *timeDelayAnalyzer) Analyze(options *Options) (*Result, error) {
+	delays, err := a.Variants(options.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	requestsLimit, err := intParameter(options.Parameters, "requests_limit", defaultRequestsLimit)
+	if err != nil {
+		return nil, err
+	}
+	correlationThreshold, err := floatParameter(options.Parameters, "correlation_threshold", defaultCorrelationThreshold)
+	if err != nil {
+		return nil, err
+	}
+	slopeErrorRange, err := floatParameter(options.Parameters, "slope_error_range", defaultSlopeErrorRange)
+	if err != nil {
+		return nil, err
+	}
+
+	var requested, observed []float64
+	for i := 0; i < requestsLimit; i++ {
+		for _, delay := range delays {
+			duration, err := options.SendVariant(delay)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not send analyzer request")
+			}
+			delaySeconds, _ := strconv.ParseFloat(delay, 64)
+			// a response faster than the requested delay means
+			// the delay is not executed, so stop sending requests.
+			if duration.Seconds() < delaySeconds {
+				return &Result{Name: a.Name(), Details: fmt.Sprintf("response time %.3fs is less than requested delay %ss", duration.Seconds(), delay)}, nil
+			}
+			requested = append(requested, delaySeconds)
+			observed = append(observed, duration.Seconds())
+		}
+	}
+
+	slope, intercept, correlation := linearRegression(requested, observed)
+	result := &Result{
+		Name:    a.Name(),
+		Matched: correlation >= correlationThreshold && math.Abs(slope-1) <= slopeErrorRange,
+		Details: fmt.Sprintf("slope=%.3f intercept=%.3f correlation=%.3f samples=%d", slope, intercept, correlation, len(requested)),
+	}
+	return result, nil
+}
+
+// linearRegression fits y = intercept + slope*x using least squares and
+// returns the slope, intercept and pearson correlation coefficient.
+func linearRegression(x, y []float64) (slope, intercept, correlation float64) {
+	n := float64(len(x))
+	if n == 0 {
+		return 0, 0, 0
+	}
+	var sumX, sumY float64
+	for i := range x {
+		sumX += x[i]
+		sumY += y[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var covariance, varianceX, varianceY float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		covariance += dx * dy
+		varianceX += dx * dx
+		varianceY += dy * dy
+	}
+	if varianceX == 0 {
+		return 0, meanY, 0
+	}
+	slope = covariance / varianceX
+	intercept = meanY - slope*meanX
+	if varianceY == 0 {
+		return slope, intercept, 0
+	}
+	correlation = covariance / math.Sqrt(varianceX*varianceY)
+	return slope, intercept, correlation
+}
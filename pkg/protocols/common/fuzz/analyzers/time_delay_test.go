@@ -0,0 +1,72 @@
+package analyzers
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinearRegression(t *testing.T) {
+	slope, intercept, correlation := linearRegression([]float64{0, 2, 5}, []float64{0.5, 2.5, 5.5})
+	require.InDelta(t, 1, slope, 0.001, "could not get correct slope")
+	require.InDelta(t, 0.5, intercept, 0.001, "could not get correct intercept")
+	require.InDelta(t, 1, correlation, 0.001, "could not get correct correlation")
+}
+
+func TestTimeDelayAnalyzer(t *testing.T) {
+	analyzer, err := Get("time_delay")
+	require.NoError(t, err, "could not get analyzer")
+
+	t.Run("vulnerable", func(t *testing.T) {
+		var sent int
+		result, err := analyzer.Analyze(&Options{
+			SendVariant: func(variant string) (time.Duration, error) {
+				sent++
+				delay, _ := strconv.Atoi(variant)
+				jitter := time.Duration(sent%3) * 50 * time.Millisecond
+				return time.Duration(delay)*time.Second + 200*time.Millisecond + jitter, nil
+			},
+		})
+		require.NoError(t, err, "could not analyze")
+		require.True(t, result.Matched, "could not detect time delay: %s", result.Details)
+		require.Equal(t, 9, sent, "could not send correct number of requests")
+	})
+
+	t.Run("not-vulnerable", func(t *testing.T) {
+		var sent int
+		result, err := analyzer.Analyze(&Options{
+			SendVariant: func(variant string) (time.Duration, error) {
+				sent++
+				return 3 * time.Second, nil
+			},
+		})
+		require.NoError(t, err, "could not analyze")
+		require.False(t, result.Matched, "could detect time delay")
+		require.Equal(t, 3, sent, "could not stop early on fast response")
+	})
+
+	t.Run("jittery", func(t *testing.T) {
+		responses := []float64{6, 7.5, 9, 5.5, 9, 6.5, 8, 6, 5.2}
+		var sent int
+		result, err := analyzer.Analyze(&Options{
+			SendVariant: func(variant string) (time.Duration, error) {
+				response := responses[sent]
+				sent++
+				return time.Duration(response * float64(time.Second)), nil
+			},
+		})
+		require.NoError(t, err, "could not analyze")
+		require.False(t, result.Matched, "could detect time delay on jittery host")
+	})
+
+	t.Run("parameters", func(t *testing.T) {
+		variants, err := analyzer.Variants(map[string]interface{}{"delays": []interface{}{0, 4}})
+		require.NoError(t, err, "could not get variants")
+		require.Equal(t, []string{"0", "4"}, variants, "could not get correct variants")
+
+		_, err = analyzer.Variants(map[string]interface{}{"delays": "1"})
+		require.Error(t, err, "could get variants with single delay")
+	})
+}
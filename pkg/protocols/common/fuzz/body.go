@@ -0,0 +1,389 @@
+package fuzz
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// bodyLeaf is a single leaf value of a parsed request body
+// which can be fuzzed.
+type bodyLeaf struct {
+	// key is the name of the parameter containing the value
+	key string
+	// path is the full path of the value in the body (eg. user.emails[0])
+	path string
+	// value is the original value of the leaf
+	value string
+	// set updates the value of the leaf in the parsed body
+	set func(value string)
+	// restore optionally restores the original typed value of the leaf
+	restore func()
+}
+
+// reset changes the leaf back to its original value
+func (l *bodyLeaf) reset() {
+	if l.restore != nil {
+		l.restore()
+		return
+	}
+	l.set(l.value)
+}
+
+// bodyDocument is a parsed request body of a specific content type
+type bodyDocument interface {
+	// leaves returns the leaf values of the body in a stable order
+	leaves() []*bodyLeaf
+	// encode serializes the body with the current leaf values
+	encode() (string, error)
+}
+
+// bodyType is the type of a fuzzable request body
+type bodyType int
+
+const (
+	jsonBodyType bodyType = iota + 1
+	xmlBodyType
+	formBodyType
+	multipartBodyType
+)
+
+// getBodyType returns the body type for a content type. If the content
+// type is not known, the body is sniffed for json and xml documents.
+func getBodyType(contentType, body string) bodyType {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		return formBodyType
+	case mediaType == "multipart/form-data":
+		return multipartBodyType
+	case strings.HasSuffix(mediaType, "json"):
+		return jsonBodyType
+	case strings.HasSuffix(mediaType, "xml"):
+		return xmlBodyType
+	}
+	trimmed := strings.TrimSpace(body)
+	switch {
+	case strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "["):
+		return jsonBodyType
+	case strings.HasPrefix(trimmed, "<"):
+		return xmlBodyType
+	}
+	return 0
+}
+
+// parseBody parses a request body based on its content type
+func parseBody(contentType, body string) (bodyDocument, error) {
+	switch getBodyType(contentType, body) {
+	case jsonBodyType:
+		return parseJSONBody(body)
+	case xmlBodyType:
+		return parseXMLBody(body)
+	case formBodyType:
+		return parseFormBody(body)
+	case multipartBodyType:
+		_, params, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse content type")
+		}
+		return parseMultipartBody(body, params["boundary"])
+	}
+	return nil, errors.New("unsupported body content type")
+}
+
+// jsonBody is a parsed json request body
+type jsonBody struct {
+	root   interface{}
+	values []*bodyLeaf
+}
+
+func parseJSONBody(body string) (*jsonBody, error) {
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+
+	document := &jsonBody{}
+	if err := decoder.Decode(&document.root); err != nil {
+		return nil, errors.Wrap(err, "could not decode json body")
+	}
+	document.walk("", "", document.root, func(value interface{}) { document.root = value })
+	return document, nil
+}
+
+// walk collects the leaves of the json value with setters
+// updating the value in its parent object or array.
+func (j *jsonBody) walk(key, path string, value interface{}, set func(interface{})) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			k := k
+			childPath := k
+			if path != "" {
+				childPath = path + "." + k
+			}
+			j.walk(k, childPath, v[k], func(value interface{}) { v[k] = value })
+		}
+	case []interface{}:
+		for i := range v {
+			i := i
+			j.walk(key, path+"["+strconv.Itoa(i)+"]", v[i], func(value interface{}) { v[i] = value })
+		}
+	default:
+		j.values = append(j.values, &bodyLeaf{
+			key:     key,
+			path:    path,
+			value:   jsonValueToString(v),
+			set:     func(value string) { set(value) },
+			restore: func() { set(v) },
+		})
+	}
+}
+
+func (j *jsonBody) leaves() []*bodyLeaf {
+	return j.values
+}
+
+func (j *jsonBody) encode() (string, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(j.root); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+// jsonValueToString returns the string representation of a json scalar
+func jsonValueToString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
+
+// xmlNode is a generic xml element preserving attributes and children
+type xmlNode struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Content string     `xml:",chardata"`
+	Nodes   []*xmlNode `xml:",any"`
+}
+
+// xmlBody is a parsed xml request body
+type xmlBody struct {
+	header string
+	root   *xmlNode
+	values []*bodyLeaf
+}
+
+func parseXMLBody(body string) (*xmlBody, error) {
+	document := &xmlBody{root: &xmlNode{}}
+	trimmed := strings.TrimSpace(body)
+	if strings.HasPrefix(trimmed, "<?xml") {
+		if index := strings.Index(trimmed, "?>"); index != -1 {
+			document.header = trimmed[:index+2]
+		}
+	}
+	if err := xml.Unmarshal([]byte(trimmed), document.root); err != nil {
+		return nil, errors.Wrap(err, "could not decode xml body")
+	}
+	document.walk(document.root, "")
+	return document, nil
+}
+
+// walk collects the attributes and text of leaf elements of the node
+func (x *xmlBody) walk(node *xmlNode, path string) {
+	if path != "" {
+		path += "."
+	}
+	path += node.XMLName.Local
+
+	for i := range node.Attrs {
+		attr := &node.Attrs[i]
+		x.values = append(x.values, &bodyLeaf{
+			key:   attr.Name.Local,
+			path:  path + "@" + attr.Name.Local,
+			value: attr.Value,
+			set:   func(value string) { attr.Value = value },
+		})
+	}
+	if len(node.Nodes) == 0 {
+		x.values = append(x.values, &bodyLeaf{
+			key:   node.XMLName.Local,
+			path:  path,
+			value: node.Content,
+			set:   func(value string) { node.Content = value },
+		})
+		return
+	}
+	// drop the indentation between child elements
+	if strings.TrimSpace(node.Content) == "" {
+		node.Content = ""
+	}
+	for _, child := range node.Nodes {
+		x.walk(child, path)
+	}
+}
+
+func (x *xmlBody) leaves() []*bodyLeaf {
+	return x.values
+}
+
+func (x *xmlBody) encode() (string, error) {
+	data, err := xml.Marshal(x.root)
+	if err != nil {
+		return "", err
+	}
+	if x.header != "" {
+		return x.header + "\n" + string(data), nil
+	}
+	return string(data), nil
+}
+
+// formParam is a single parameter of a form body
+type formParam struct {
+	key   string
+	value string
+}
+
+// formBody is a parsed application/x-www-form-urlencoded request body.
+// The parameters are kept in their original order.
+type formBody struct {
+	params []*formParam
+	values []*bodyLeaf
+}
+
+func parseFormBody(body string) (*formBody, error) {
+	document := &formBody{}
+	for _, pair := range strings.Split(body, "&") {
+		if pair == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(pair, "=")
+		unescapedKey, err := url.QueryUnescape(key)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not decode form body")
+		}
+		unescapedValue, err := url.QueryUnescape(value)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not decode form body")
+		}
+		param := &formParam{key: unescapedKey, value: unescapedValue}
+		document.params = append(document.params, param)
+		document.values = append(document.values, &bodyLeaf{
+			key:   param.key,
+			path:  param.key,
+			value: param.value,
+			set:   func(value string) { param.value = value },
+		})
+	}
+	return document, nil
+}
+
+func (f *formBody) leaves() []*bodyLeaf {
+	return f.values
+}
+
+func (f *formBody) encode() (string, error) {
+	var builder strings.Builder
+	for i, param := range f.params {
+		if i > 0 {
+			builder.WriteString("&")
+		}
+		builder.WriteString(url.QueryEscape(param.key))
+		builder.WriteString("=")
+		builder.WriteString(url.QueryEscape(param.value))
+	}
+	return builder.String(), nil
+}
+
+// multipartPart is a single part of a multipart body
+type multipartPart struct {
+	header  textproto.MIMEHeader
+	content string
+}
+
+// multipartBody is a parsed multipart/form-data request body
+type multipartBody struct {
+	boundary string
+	parts    []*multipartPart
+	values   []*bodyLeaf
+}
+
+func parseMultipartBody(body, boundary string) (*multipartBody, error) {
+	if boundary == "" {
+		return nil, errors.New("no boundary found for multipart body")
+	}
+	document := &multipartBody{boundary: boundary}
+	reader := multipart.NewReader(strings.NewReader(body), boundary)
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "could not decode multipart body")
+		}
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read multipart part")
+		}
+		item := &multipartPart{header: part.Header, content: string(content)}
+		document.parts = append(document.parts, item)
+
+		name := part.FormName()
+		document.values = append(document.values, &bodyLeaf{
+			key:   name,
+			path:  name,
+			value: item.content,
+			set:   func(value string) { item.content = value },
+		})
+	}
+	return document, nil
+}
+
+func (m *multipartBody) leaves() []*bodyLeaf {
+	return m.values
+}
+
+func (m *multipartBody) encode() (string, error) {
+	var buffer bytes.Buffer
+	writer := multipart.NewWriter(&buffer)
+	if err := writer.SetBoundary(m.boundary); err != nil {
+		return "", err
+	}
+	for _, part := range m.parts {
+		partWriter, err := writer.CreatePart(part.header)
+		if err != nil {
+			return "", err
+		}
+		if _, err := partWriter.Write([]byte(part.content)); err != nil {
+			return "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
@@ -0,0 +1,69 @@
+package fuzz
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		keys        []string
+		fuzzed      string
+	}{
+		{
+			name:        "json",
+			contentType: "application/json",
+			body:        `{"b":[1,{"c":true}],"a":"x"}`,
+			keys:        []string{"a", "b", "c"},
+			fuzzed:      `{"a":"x'","b":[1,{"c":true}]}`,
+		},
+		{
+			name:        "xml",
+			contentType: "application/xml",
+			body:        "<user id=\"1\">\n  <name>john</name>\n</user>",
+			keys:        []string{"id", "name"},
+			fuzzed:      `<user id="1&#39;"><name>john</name></user>`,
+		},
+		{
+			name:        "form",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "q=a+b&page=1",
+			keys:        []string{"q", "page"},
+			fuzzed:      "q=a+b%27&page=1",
+		},
+		{
+			name:        "multipart",
+			contentType: "multipart/form-data; boundary=boundary",
+			body:        "--boundary\r\nContent-Disposition: form-data; name=\"q\"\r\n\r\nvalue\r\n--boundary--\r\n",
+			keys:        []string{"q"},
+			fuzzed:      "--boundary\r\nContent-Disposition: form-data; name=\"q\"\r\n\r\nvalue'\r\n--boundary--\r\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			document, err := parseBody(test.contentType, test.body)
+			require.NoError(t, err, "could not parse body")
+
+			leaves := document.leaves()
+			var keys []string
+			for _, leaf := range leaves {
+				keys = append(keys, leaf.key)
+			}
+			require.ElementsMatch(t, test.keys, keys, "could not get correct keys")
+
+			leaves[0].set(leaves[0].value + "'")
+			fuzzed, err := document.encode()
+			require.NoError(t, err, "could not encode body")
+			require.Equal(t, test.fuzzed, fuzzed, "could not get fuzzed body")
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := parseBody("text/plain", "plain text")
+		require.Error(t, err, "could parse unsupported body")
+	})
+}
@@ -0,0 +1,317 @@
+package fuzz
+
+import (
+	"bufio"
+	"context"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/gologger"
+	readerutil "github.com/khulnasoft-lab/utils/reader"
+	urlutil "github.com/khulnasoft-lab/utils/url"
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// defaultDiscoveryBatchSize is the default number of candidate parameters sent per request
+const defaultDiscoveryBatchSize = 64
+
+// DiscoveryOptions are the options of hidden parameter discovery
+type DiscoveryOptions struct {
+	// description: |
+	//   Wordlist is the file containing candidate parameter names, one per line.
+	//
+	//   A multiline string is used as the list of candidates directly.
+	// examples:
+	//   - name: Wordlist file
+	//     value: "\"helpers/wordlists/params.txt\""
+	Wordlist string `yaml:"wordlist" json:"wordlist" jsonschema:"title=wordlist of parameter names,description=Wordlist of candidate parameter names"`
+	// description: |
+	//   BatchSize is the number of candidate parameters sent in a single request.
+	//
+	//   Batches changing the response are bisected to find the responsible parameters.
+	//   Default is 64.
+	BatchSize int `yaml:"batch-size,omitempty" json:"batch-size,omitempty" jsonschema:"title=batch size of candidates,description=Number of candidate parameters sent in a single request"`
+	// description: |
+	//   Value is the value of candidate parameters. A random value is used by default.
+	Value string `yaml:"value,omitempty" json:"value,omitempty" jsonschema:"title=value of candidates,description=Value of candidate parameters"`
+}
+
+// compileDiscovery validates the discovery options and loads the wordlist
+func (rule *Rule) compileDiscovery() error {
+	switch rule.partType {
+	case queryPartType, headersPartType, bodyPartType:
+	default:
+		return errors.Errorf("discovery is not supported for %s part", rule.Part)
+	}
+	if rule.Discovery.BatchSize <= 0 {
+		rule.Discovery.BatchSize = defaultDiscoveryBatchSize
+	}
+	if rule.Discovery.Value == "" {
+		rule.Discovery.Value = strconv.FormatInt(rand.Int63(), 36)
+	}
+
+	var words []string
+	if elements := strings.Split(rule.Discovery.Wordlist, "\n"); len(elements) >= 2 {
+		words = elements
+	} else {
+		file, err := rule.options.Options.LoadHelperFile(rule.Discovery.Wordlist, rule.options.TemplatePath, rule.options.Catalog)
+		if err != nil {
+			return errors.Wrap(err, "could not load wordlist")
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			words = append(words, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return errors.Wrap(err, "could not read wordlist")
+		}
+	}
+
+	seen := make(map[string]struct{})
+	for _, word := range words {
+		word = strings.TrimSpace(word)
+		if _, ok := seen[word]; ok || word == "" {
+			continue
+		}
+		seen[word] = struct{}{}
+		rule.discoveryWords = append(rule.discoveryWords, word)
+	}
+	if len(rule.discoveryWords) == 0 {
+		return errors.New("no parameters found in wordlist")
+	}
+	return nil
+}
+
+// responseFingerprint is the fingerprint of a response used
+// to detect changes caused by candidate parameters.
+type responseFingerprint struct {
+	statusCode  int
+	lines       int
+	words       int
+	reflections int
+}
+
+// executeDiscovery discovers hidden parameters of the part of the base
+// request. Candidates from the wordlist are sent in batches and batches
+// changing the response are bisected to find the responsible parameters.
+//
+// Discovered parameters are added to the base request of the input and
+// the request is returned to the callback to be reported.
+func (rule *Rule) executeDiscovery(input *ExecuteRuleInput) error {
+	if input.SendRequest == nil {
+		return errors.New("parameter discovery requires a request sender")
+	}
+	candidates := rule.discoveryCandidates(input.BaseRequest)
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	// the response must be stable for changes to be attributed to parameters
+	baseline, err := rule.sendDiscoveryRequest(input, nil)
+	if err != nil {
+		return errors.Wrap(err, "could not send discovery baseline request")
+	}
+	if confirmed, err := rule.sendDiscoveryRequest(input, nil); err != nil || confirmed != baseline {
+		gologger.Verbose().Msgf("Skipping parameter discovery for %s as response is not stable\n", input.BaseRequest.URL.String())
+		return nil
+	}
+
+	var discovered []string
+	for i := 0; i < len(candidates); i += rule.Discovery.BatchSize {
+		end := i + rule.Discovery.BatchSize
+		if end > len(candidates) {
+			end = len(candidates)
+		}
+		found, err := rule.bisectParameters(input, baseline, candidates[i:end])
+		if err != nil {
+			gologger.Verbose().Msgf("Could not discover parameters for %s: %s\n", input.BaseRequest.URL.String(), err)
+			continue
+		}
+		discovered = append(discovered, found...)
+	}
+	if len(discovered) == 0 {
+		return nil
+	}
+
+	req, err := rule.addParameters(input.BaseRequest, discovered)
+	if err != nil {
+		return err
+	}
+	input.BaseRequest = req
+	generated := GeneratedRequest{
+		Request:              req.Clone(context.TODO()),
+		DynamicValues:        input.Values,
+		DiscoveredParameters: discovered,
+	}
+	if !input.Callback(generated) {
+		return types.ErrNoMoreRequests
+	}
+	return nil
+}
+
+// bisectParameters returns the parameters of a batch changing the response
+func (rule *Rule) bisectParameters(input *ExecuteRuleInput, baseline responseFingerprint, names []string) ([]string, error) {
+	fingerprint, err := rule.sendDiscoveryRequest(input, names)
+	if err != nil {
+		return nil, err
+	}
+	if fingerprint == baseline {
+		return nil, nil
+	}
+	if len(names) == 1 {
+		return names, nil
+	}
+	middle := len(names) / 2
+	left, err := rule.bisectParameters(input, baseline, names[:middle])
+	if err != nil {
+		return nil, err
+	}
+	right, err := rule.bisectParameters(input, baseline, names[middle:])
+	if err != nil {
+		return nil, err
+	}
+	return append(left, right...), nil
+}
+
+// sendDiscoveryRequest sends the base request with the parameters added
+// and returns the fingerprint of the response.
+func (rule *Rule) sendDiscoveryRequest(input *ExecuteRuleInput, names []string) (responseFingerprint, error) {
+	req, err := rule.addParameters(input.BaseRequest, names)
+	if err != nil {
+		return responseFingerprint{}, err
+	}
+	resp, err := input.SendRequest(req)
+	if err != nil {
+		return responseFingerprint{}, err
+	}
+	return responseFingerprint{
+		statusCode:  resp.StatusCode,
+		lines:       strings.Count(resp.Body, "\n"),
+		words:       len(strings.Fields(resp.Body)),
+		reflections: strings.Count(resp.Body, rule.Discovery.Value),
+	}, nil
+}
+
+// discoveryCandidates returns the wordlist parameters not present in the request part
+func (rule *Rule) discoveryCandidates(req *retryablehttp.Request) []string {
+	existing := make(map[string]struct{})
+	switch rule.partType {
+	case queryPartType:
+		req.Query().Iterate(func(key string, _ []string) bool {
+			existing[key] = struct{}{}
+			return true
+		})
+	case headersPartType:
+		for key := range req.Header {
+			existing[strings.ToLower(key)] = struct{}{}
+		}
+	case bodyPartType:
+		if body, err := req.BodyBytes(); err == nil && len(body) > 0 {
+			if document, err := parseBody(req.Header.Get("Content-Type"), string(body)); err == nil {
+				for _, leaf := range document.leaves() {
+					existing[leaf.key] = struct{}{}
+				}
+			}
+		}
+	}
+
+	var candidates []string
+	for _, word := range rule.discoveryWords {
+		key := word
+		if rule.partType == headersPartType {
+			key = strings.ToLower(word)
+		}
+		if _, ok := existing[key]; !ok {
+			candidates = append(candidates, word)
+		}
+	}
+	return candidates
+}
+
+// addParameters returns a clone of the request with the parameters added to the part
+func (rule *Rule) addParameters(base *retryablehttp.Request, names []string) (*retryablehttp.Request, error) {
+	req := base.Clone(context.TODO())
+	switch rule.partType {
+	case queryPartType:
+		parsed, err := urlutil.Parse(base.URL.String())
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse request url")
+		}
+		for _, name := range names {
+			parsed.Params.Update(name, []string{rule.Discovery.Value})
+		}
+		req.SetURL(parsed)
+	case headersPartType:
+		req.Header = base.Header.Clone()
+		for _, name := range names {
+			req.Header.Set(name, rule.Discovery.Value)
+		}
+	case bodyPartType:
+		body, err := base.BodyBytes()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read request body")
+		}
+		contentType := base.Header.Get("Content-Type")
+		updated, err := addBodyParameters(contentType, string(body), names, rule.Discovery.Value)
+		if err != nil {
+			return nil, err
+		}
+		bodyReader, err := readerutil.NewReusableReadCloser([]byte(updated))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create reusable reader for request body")
+		}
+		req.Header = base.Header.Clone()
+		if contentType == "" {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		req.Body = bodyReader
+		req.ContentLength = int64(len(updated))
+		if req.Header.Get("Content-Length") != "" {
+			req.Header.Set("Content-Length", strconv.Itoa(len(updated)))
+		}
+	}
+	return req, nil
+}
+
+// addBodyParameters adds parameters to a form or json object body.
+// Empty bodies are treated as form bodies.
+func addBodyParameters(contentType, body string, names []string, value string) (string, error) {
+	bodyType := getBodyType(contentType, body)
+	if strings.TrimSpace(body) == "" && bodyType != jsonBodyType {
+		bodyType = formBodyType
+	}
+	switch bodyType {
+	case formBodyType:
+		document, err := parseFormBody(body)
+		if err != nil {
+			return "", err
+		}
+		for _, name := range names {
+			document.params = append(document.params, &formParam{key: name, value: value})
+		}
+		return document.encode()
+	case jsonBodyType:
+		if strings.TrimSpace(body) == "" {
+			body = "{}"
+		}
+		document, err := parseJSONBody(body)
+		if err != nil {
+			return "", err
+		}
+		object, ok := document.root.(map[string]interface{})
+		if !ok {
+			return "", errors.New("json body is not an object")
+		}
+		for _, name := range names {
+			object[name] = value
+		}
+		return document.encode()
+	}
+	return "", errors.Errorf("discovery is not supported for body content type %q", contentType)
+}
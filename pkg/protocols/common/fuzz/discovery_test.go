@@ -0,0 +1,88 @@
+package fuzz
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/interactsh"
+)
+
+func TestExecuteDiscovery(t *testing.T) {
+	options := &protocols.ExecutorOptions{
+		Interactsh: &interactsh.Client{},
+	}
+	rule := &Rule{
+		Part: "query",
+		Type: "postfix",
+		Mode: "single",
+		Fuzz: []string{"'"},
+		Discovery: &DiscoveryOptions{
+			Wordlist:  "id\nfoo\nbar\nbaz\ndebug\nqux\nadmin\nuser",
+			BatchSize: 4,
+			Value:     "1",
+		},
+	}
+	err := rule.Compile(nil, options)
+	require.NoError(t, err, "could not compile rule")
+
+	req, err := retryablehttp.NewRequest("GET", "http://localhost:8080/?id=1", nil)
+	require.NoError(t, err, "can't build request")
+
+	// the debug and admin parameters change the response
+	var sent int
+	var generated []GeneratedRequest
+	input := &ExecuteRuleInput{
+		Input:       contextargs.New(),
+		BaseRequest: req,
+		SendRequest: func(req *retryablehttp.Request) (*Response, error) {
+			sent++
+			body := "welcome"
+			for _, name := range []string{"debug", "admin"} {
+				if strings.Contains(req.URL.String(), name+"=") {
+					body += " " + name + " enabled"
+				}
+			}
+			return &Response{StatusCode: 200, Body: body}, nil
+		},
+		Callback: func(gr GeneratedRequest) bool {
+			generated = append(generated, gr)
+			return true
+		},
+	}
+	err = rule.Execute(input)
+	require.NoError(t, err, "could not execute rule")
+
+	require.NotEmpty(t, generated, "could not get generated requests")
+	require.Equal(t, []string{"debug", "admin"}, generated[0].DiscoveredParameters, "could not discover parameters")
+	require.Contains(t, input.BaseRequest.URL.String(), "debug=1", "could not add parameter to base request")
+
+	var injectionPoints []string
+	for _, gr := range generated[1:] {
+		injectionPoints = append(injectionPoints, gr.InjectionPoints...)
+	}
+	require.ElementsMatch(t, []string{"id", "debug", "admin"}, injectionPoints, "could not fuzz discovered parameters")
+	// 2 baseline requests and 5 requests for bisecting each batch
+	require.Equal(t, 12, sent, "could not send correct number of discovery requests")
+}
+
+func TestAddBodyParameters(t *testing.T) {
+	body, err := addBodyParameters("application/x-www-form-urlencoded", "a=1", []string{"debug"}, "1")
+	require.NoError(t, err, "could not add form parameters")
+	require.Equal(t, "a=1&debug=1", body)
+
+	body, err = addBodyParameters("application/json", `{"a":1}`, []string{"debug"}, "1")
+	require.NoError(t, err, "could not add json parameters")
+	require.Equal(t, `{"a":1,"debug":"1"}`, body)
+
+	body, err = addBodyParameters("", "", []string{"debug"}, "1")
+	require.NoError(t, err, "could not add parameters to empty body")
+	require.Equal(t, "debug=1", body)
+
+	_, err = addBodyParameters("application/json", `[1]`, []string{"debug"}, "1")
+	require.Error(t, err, "could add parameters to json array")
+}
@@ -0,0 +1,183 @@
+package fuzz
+
+import (
+	"encoding/base64"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxEncodingLayers is the maximum number of encoding layers decoded for a value
+const maxEncodingLayers = 3
+
+var (
+	// urlEncodedRegex matches values containing url encoded characters
+	urlEncodedRegex = regexp.MustCompile(`%[0-9a-fA-F]{2}`)
+	// base64Regex matches values made of standard or url safe base64 alphabet
+	base64Regex = regexp.MustCompile(`^[A-Za-z0-9+/_-]{8,}={0,2}$`)
+)
+
+// encodingLayer is a single encoding applied on a value
+type encodingLayer struct {
+	name   string
+	encode func(value string) string
+}
+
+// fuzzedValue is a fuzzed value of a parameter along with
+// the paths of the injection points in the value.
+type fuzzedValue struct {
+	value string
+	paths []string
+}
+
+// executeValueRule executes the rule on a parameter value. If the value is
+// JSON or layered encoded data (base64, url encoding), the layers are decoded
+// and the payload is injected into the inner leaf values, after which the
+// value is re-encoded with the original layering.
+//
+// In single mode a value is returned for each injection point while multiple
+// mode returns a single value with all the injection points fuzzed.
+func (rule *Rule) executeValueRule(input *ExecuteRuleInput, key, path, value, payload string) []*fuzzedValue {
+	layers, inner := decodeEncodingLayers(value)
+	document := parseNestedJSON(inner)
+	if len(layers) == 0 && document == nil {
+		var evaluated string
+		evaluated, input.InteractURLs = rule.executeEvaluate(input, key, value, payload, input.InteractURLs)
+		return []*fuzzedValue{{value: evaluated, paths: []string{path}}}
+	}
+
+	for _, layer := range layers {
+		path += ":" + layer.name
+	}
+	if document == nil {
+		var evaluated string
+		evaluated, input.InteractURLs = rule.executeEvaluate(input, key, inner, payload, input.InteractURLs)
+		return []*fuzzedValue{{value: encodeLayers(layers, evaluated), paths: []string{path}}}
+	}
+	path += ":json"
+
+	var fuzzedValues []*fuzzedValue
+	var paths []string
+	for _, leaf := range document.leaves() {
+		var evaluated string
+		evaluated, input.InteractURLs = rule.executeEvaluate(input, leaf.key, leaf.value, payload, input.InteractURLs)
+		leaf.set(evaluated)
+		leafPath := path + ":" + leaf.path
+
+		if rule.modeType == singleModeType {
+			if encoded, err := document.encode(); err == nil {
+				fuzzedValues = append(fuzzedValues, &fuzzedValue{value: encodeLayers(layers, encoded), paths: []string{leafPath}})
+			}
+			leaf.reset()
+			continue
+		}
+		paths = append(paths, leafPath)
+	}
+	if rule.modeType == multipleModeType {
+		if encoded, err := document.encode(); err == nil {
+			fuzzedValues = append(fuzzedValues, &fuzzedValue{value: encodeLayers(layers, encoded), paths: paths})
+		}
+	}
+	return fuzzedValues
+}
+
+// trackInjectionPoints records the injection points of the request being built
+func (rule *Rule) trackInjectionPoints(input *ExecuteRuleInput, paths []string) {
+	if rule.modeType == singleModeType {
+		input.injectionPoints = paths
+		return
+	}
+	input.injectionPoints = append(input.injectionPoints, paths...)
+}
+
+// decodeEncodingLayers decodes the url and base64 encoding layers of a value
+// returning the layers in the order they were decoded and the inner value.
+func decodeEncodingLayers(value string) ([]*encodingLayer, string) {
+	var layers []*encodingLayer
+	for len(layers) < maxEncodingLayers {
+		layer, decoded, ok := decodeEncodingLayer(value)
+		if !ok {
+			break
+		}
+		layers = append(layers, layer)
+		value = decoded
+	}
+	return layers, value
+}
+
+// unescapeQueryValue decodes the url encoding a query value is sent with, so
+// only values still encoded after it are decoded as a url encoding layer.
+// The returned function restores the encoding of the fuzzed value.
+func unescapeQueryValue(value string) (string, func(value string) string) {
+	if urlEncodedRegex.MatchString(value) {
+		if decoded, err := url.QueryUnescape(value); err == nil {
+			return decoded, url.QueryEscape
+		}
+	}
+	return value, func(value string) string { return value }
+}
+
+// decodeEncodingLayer decodes a single encoding layer of the value
+func decodeEncodingLayer(value string) (*encodingLayer, string, bool) {
+	if urlEncodedRegex.MatchString(value) {
+		if decoded, err := url.QueryUnescape(value); err == nil && decoded != value {
+			return &encodingLayer{name: "url", encode: url.QueryEscape}, decoded, true
+		}
+	}
+	if !base64Regex.MatchString(value) {
+		return nil, "", false
+	}
+	encodings := []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding}
+	if strings.ContainsAny(value, "-_") {
+		encodings = []*base64.Encoding{base64.URLEncoding, base64.RawURLEncoding}
+	}
+	for _, encoding := range encodings {
+		encoding := encoding
+		decoded, err := encoding.DecodeString(value)
+		if err != nil || !isPrintableText(decoded) {
+			continue
+		}
+		encode := func(value string) string {
+			return encoding.EncodeToString([]byte(value))
+		}
+		return &encodingLayer{name: "base64", encode: encode}, string(decoded), true
+	}
+	return nil, "", false
+}
+
+// encodeLayers encodes the value with the layers in reverse order of decoding
+func encodeLayers(layers []*encodingLayer, value string) string {
+	for i := len(layers) - 1; i >= 0; i-- {
+		value = layers[i].encode(value)
+	}
+	return value
+}
+
+// parseNestedJSON returns the parsed json document if the value
+// is a json object or array, otherwise nil is returned.
+func parseNestedJSON(value string) *jsonBody {
+	trimmed := strings.TrimSpace(value)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return nil
+	}
+	document, err := parseJSONBody(trimmed)
+	if err != nil || len(document.leaves()) == 0 {
+		return nil
+	}
+	return document
+}
+
+// isPrintableText returns true if the data is valid printable utf-8 text
+func isPrintableText(data []byte) bool {
+	if len(data) == 0 || !utf8.Valid(data) {
+		return false
+	}
+	for _, r := range string(data) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package fuzz
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/interactsh"
+)
+
+func TestDecodeEncodingLayers(t *testing.T) {
+	tests := []struct {
+		value  string
+		layers []string
+		inner  string
+	}{
+		{"localhost", nil, "localhost"},
+		{"password", nil, "password"},
+		{"eyJ1c2VyIjp7ImlkIjoxfX0=", []string{"base64"}, `{"user":{"id":1}}`},
+		{"a%2527b", []string{"url", "url"}, "a'b"},
+		{"eyJpZCI6MX0%253D", []string{"url", "url", "base64"}, `{"id":1}`},
+	}
+	for _, test := range tests {
+		layers, inner := decodeEncodingLayers(test.value)
+		var names []string
+		for _, layer := range layers {
+			names = append(names, layer.name)
+		}
+		require.Equal(t, test.layers, names, "could not get correct layers for %s", test.value)
+		require.Equal(t, test.inner, inner, "could not get correct inner value for %s", test.value)
+		require.Equal(t, test.value, encodeLayers(layers, inner), "could not re-encode %s", test.value)
+	}
+}
+
+func TestExecuteValueRule(t *testing.T) {
+	options := &protocols.ExecutorOptions{
+		Interactsh: &interactsh.Client{},
+	}
+	// base64 of {"id":1,"name":"john"}
+	value := "eyJpZCI6MSwibmFtZSI6ImpvaG4ifQ=="
+
+	t.Run("single", func(t *testing.T) {
+		rule := &Rule{ruleType: postfixRuleType, modeType: singleModeType, options: options}
+		fuzzed := rule.executeValueRule(&ExecuteRuleInput{}, "data", "data", value, "'")
+		require.Len(t, fuzzed, 2, "could not get fuzzed values for leaves")
+
+		require.Equal(t, "eyJpZCI6IjEnIiwibmFtZSI6ImpvaG4ifQ==", fuzzed[0].value, "could not get re-encoded value")
+		require.Equal(t, []string{"data:base64:json:id"}, fuzzed[0].paths, "could not get injection point")
+		require.Equal(t, []string{"data:base64:json:name"}, fuzzed[1].paths, "could not get injection point")
+	})
+
+	t.Run("multiple", func(t *testing.T) {
+		rule := &Rule{ruleType: replaceRuleType, modeType: multipleModeType, options: options}
+		fuzzed := rule.executeValueRule(&ExecuteRuleInput{}, "data", "data", value, "x")
+		require.Len(t, fuzzed, 1, "could not get fuzzed value")
+
+		// base64 of {"id":"x","name":"x"}
+		require.Equal(t, "eyJpZCI6IngiLCJuYW1lIjoieCJ9", fuzzed[0].value, "could not get re-encoded value")
+		require.Equal(t, []string{"data:base64:json:id", "data:base64:json:name"}, fuzzed[0].paths, "could not get injection points")
+	})
+
+	t.Run("plain", func(t *testing.T) {
+		rule := &Rule{ruleType: postfixRuleType, modeType: singleModeType, options: options}
+		fuzzed := rule.executeValueRule(&ExecuteRuleInput{}, "url", "url", "localhost", "'")
+		require.Equal(t, []*fuzzedValue{{value: "localhost'", paths: []string{"url"}}}, fuzzed, "could not get fuzzed value")
+	})
+}
@@ -3,15 +3,20 @@ package fuzz
 import (
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/fuzz/analyzers"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/generators"
 	"github.com/projectdiscovery/retryablehttp-go"
 	errorutil "github.com/khulnasoft-lab/utils/errors"
 )
 
+// ErrRuleNotApplicable is returned when a rule cannot be applied on the base request
+var ErrRuleNotApplicable = errors.New("rule not applicable")
+
 // ExecuteRuleInput is the input for rule Execute function
 type ExecuteRuleInput struct {
 	// Input is the context args input
@@ -24,6 +29,22 @@ type ExecuteRuleInput struct {
 	Values map[string]interface{}
 	// BaseRequest is the base http request for fuzzing rule
 	BaseRequest *retryablehttp.Request
+	// SendRequest sends a request and returns its response. It is required
+	// by rules using an analyzer or parameter discovery to send requests.
+	SendRequest func(req *retryablehttp.Request) (*Response, error)
+
+	// injectionPoints contains the paths of values fuzzed in current request
+	injectionPoints []string
+}
+
+// Response is the response of a request sent by a rule
+type Response struct {
+	// StatusCode is the status code of the response
+	StatusCode int
+	// Body is the body of the response
+	Body string
+	// Duration is the time taken for the response
+	Duration time.Duration
 }
 
 // GeneratedRequest is a single generated request for rule
@@ -34,6 +55,15 @@ type GeneratedRequest struct {
 	InteractURLs []string
 	// DynamicValues contains dynamic values map
 	DynamicValues map[string]interface{}
+	// InjectionPoints contains the paths of fuzzed values in the request.
+	// Values nested in encoded parameters have their decoded path recorded
+	// (eg. data:base64:json:user.id).
+	InjectionPoints []string
+	// AnalyzerResult is the result of the analyzer confirming the request
+	AnalyzerResult *analyzers.Result
+	// DiscoveredParameters contains the hidden parameters discovered and
+	// added to the request by parameter discovery.
+	DiscoveredParameters []string
 }
 
 // Execute executes a fuzzing rule accepting a callback on which
@@ -45,8 +75,16 @@ func (rule *Rule) Execute(input *ExecuteRuleInput) error {
 	if input.BaseRequest == nil {
 		return errorutil.NewWithTag("fuzz", "base request is nil for rule %v", rule)
 	}
+	if rule.Discovery != nil {
+		if err := rule.executeDiscovery(input); err != nil {
+			return err
+		}
+		if len(rule.Fuzz) == 0 {
+			return nil
+		}
+	}
 	if !rule.isExecutable(input.BaseRequest) {
-		return errorutil.NewWithTag("fuzz", "rule is not executable on %v", input.BaseRequest.URL.String())
+		return ErrRuleNotApplicable
 	}
 	baseValues := input.Values
 	if rule.generator == nil {
@@ -80,12 +118,27 @@ func (rule *Rule) isExecutable(req *retryablehttp.Request) bool {
 	if len(req.Header) > 0 && rule.partType == headersPartType {
 		return true
 	}
+	if req.Body != nil && req.ContentLength != 0 && rule.partType == bodyPartType {
+		return true
+	}
+	if strings.Trim(req.URL.Path, "/") != "" && rule.partType == pathPartType {
+		return true
+	}
+	if req.Header.Get("Cookie") != "" && rule.partType == cookiePartType {
+		return true
+	}
 	return false
 }
 
 // executeRuleValues executes a rule with a set of values
 func (rule *Rule) executeRuleValues(input *ExecuteRuleInput) error {
 	for _, payload := range rule.Fuzz {
+		if rule.analyzer != nil && strings.Contains(payload, rule.analyzer.Placeholder()) {
+			if err := rule.executeAnalyzerRule(input, payload); err != nil {
+				return err
+			}
+			continue
+		}
 		if err := rule.executePartRule(input, payload); err != nil {
 			return err
 		}
@@ -132,6 +185,22 @@ func (rule *Rule) Compile(generator *generators.PayloadGenerator, options *proto
 		rule.ruleType = replaceRuleType
 	}
 
+	if rule.Analyzer != nil {
+		analyzer, err := analyzers.Get(rule.Analyzer.Name)
+		if err != nil {
+			return err
+		}
+		if _, err := analyzer.Variants(rule.Analyzer.Parameters); err != nil {
+			return errors.Wrap(err, "could not compile analyzer")
+		}
+		rule.analyzer = analyzer
+	}
+	if rule.Discovery != nil {
+		if err := rule.compileDiscovery(); err != nil {
+			return errors.Wrap(err, "could not compile discovery")
+		}
+	}
+
 	// Initialize other required regexes and maps
 	if len(rule.Keys) > 0 {
 		rule.keysMap = make(map[string]struct{})
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/fuzz/analyzers"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/generators"
 )
 
@@ -25,10 +26,16 @@ type Rule struct {
 	// description: |
 	//   Part is the part of request to fuzz.
 	//
-	//   query fuzzes the query part of url. More parts will be added later.
+	//   query fuzzes the query part of url, headers fuzzes the header values,
+	//   body fuzzes the values of json, xml, form-urlencoded and multipart bodies,
+	//   path fuzzes each segment of url path and cookie fuzzes each cookie value.
 	// values:
 	//   - "query"
-	Part     string `yaml:"part,omitempty" json:"part,omitempty" jsonschema:"title=part of rule,description=Part of request rule to fuzz,enum=query"`
+	//   - "headers"
+	//   - "body"
+	//   - "path"
+	//   - "cookie"
+	Part     string `yaml:"part,omitempty" json:"part,omitempty" jsonschema:"title=part of rule,description=Part of request rule to fuzz,enum=query,enum=headers,enum=body,enum=path,enum=cookie"`
 	partType partType
 	// description: |
 	//   Mode is the mode of fuzzing to perform.
@@ -73,6 +80,30 @@ type Rule struct {
 	//       []string{"{{ssrf}}", "{{interactsh-url}}", "example-value"}
 	Fuzz []string `yaml:"fuzz,omitempty" json:"fuzz,omitempty" jsonschema:"title=payloads of fuzz rule,description=Payloads to perform fuzzing substitutions with"`
 
+	// description: |
+	//   Analyzer is the optional analyzer to confirm injection points with.
+	//
+	//   Payloads containing the placeholder of the analyzer (eg. [SLEEPTIME] for time_delay)
+	//   are sent with variants of the placeholder and only confirmed injection points are reported.
+	// examples:
+	//   - name: Time based blind detection
+	//     value: >
+	//       &analyzers.AnalyzerTemplate{Name: "time_delay"}
+	Analyzer *analyzers.AnalyzerTemplate `yaml:"analyzer,omitempty" json:"analyzer,omitempty" jsonschema:"title=analyzer of fuzz rule,description=Analyzer to confirm injection points with"`
+	analyzer analyzers.Analyzer
+
+	// description: |
+	//   Discovery is the optional hidden parameter discovery to perform on the part.
+	//
+	//   Parameters discovered from the wordlist are added to the base request so that
+	//   they are fuzzed by this and the following rules, and are reported as info findings.
+	// examples:
+	//   - name: Query parameter discovery
+	//     value: >
+	//       &DiscoveryOptions{Wordlist: "helpers/wordlists/params.txt", BatchSize: 64}
+	Discovery      *DiscoveryOptions `yaml:"discovery,omitempty" json:"discovery,omitempty" jsonschema:"title=parameter discovery of fuzz rule,description=Hidden parameter discovery to perform on the part"`
+	discoveryWords []string
+
 	options   *protocols.ExecutorOptions
 	generator *generators.PayloadGenerator
 }
@@ -100,11 +131,17 @@ type partType int
 const (
 	queryPartType partType = iota + 1
 	headersPartType
+	bodyPartType
+	pathPartType
+	cookiePartType
 )
 
 var stringToPartType = map[string]partType{
 	"query":   queryPartType,
 	"headers": headersPartType,
+	"body":    bodyPartType,
+	"path":    pathPartType,
+	"cookie":  cookiePartType,
 }
 
 // modeType is the mode of rule enum declaration
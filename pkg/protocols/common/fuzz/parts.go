@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -14,17 +15,26 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/generators"
 	"github.com/khulnasoft-lab/vulmap/pkg/types"
 	"github.com/projectdiscovery/retryablehttp-go"
+	readerutil "github.com/khulnasoft-lab/utils/reader"
 	sliceutil "github.com/khulnasoft-lab/utils/slice"
 	urlutil "github.com/khulnasoft-lab/utils/url"
 )
 
 // executePartRule executes part rules based on type
 func (rule *Rule) executePartRule(input *ExecuteRuleInput, payload string) error {
+	input.injectionPoints = nil
+
 	switch rule.partType {
 	case queryPartType:
 		return rule.executeQueryPartRule(input, payload)
 	case headersPartType:
 		return rule.executeHeadersPartRule(input, payload)
+	case bodyPartType:
+		return rule.executeBodyPartRule(input, payload)
+	case pathPartType:
+		return rule.executePathPartRule(input, payload)
+	case cookiePartType:
+		return rule.executeCookiePartRule(input, payload)
 	}
 	return nil
 }
@@ -43,17 +53,18 @@ func (rule *Rule) executeHeadersPartRule(input *ExecuteRuleInput, payload string
 			if !rule.matchKeyOrValue(key, value) {
 				continue
 			}
-			var evaluated string
-			evaluated, input.InteractURLs = rule.executeEvaluate(input, key, value, payload, input.InteractURLs)
-			cloned[i] = evaluated
+			for _, fuzzed := range rule.executeValueRule(input, key, key, value, payload) {
+				cloned[i] = fuzzed.value
+				rule.trackInjectionPoints(input, fuzzed.paths)
 
-			if rule.modeType == singleModeType {
-				headers[key] = cloned
-				if err := rule.buildHeadersInput(input, headers, input.InteractURLs); err != nil && err != io.EOF {
-					gologger.Error().Msgf("Could not build request for headers part rule %v: %s\n", rule, err)
-					return err
+				if rule.modeType == singleModeType {
+					headers[key] = cloned
+					if err := rule.buildHeadersInput(input, headers, input.InteractURLs); err != nil && err != io.EOF {
+						gologger.Error().Msgf("Could not build request for headers part rule %v: %s\n", rule, err)
+						return err
+					}
+					cloned[i] = value // change back to previous value for headers
 				}
-				cloned[i] = value // change back to previous value for headers
 			}
 		}
 		headers[key] = cloned
@@ -69,7 +80,11 @@ func (rule *Rule) executeHeadersPartRule(input *ExecuteRuleInput, payload string
 
 // executeQueryPartRule executes query part rules
 func (rule *Rule) executeQueryPartRule(input *ExecuteRuleInput, payload string) error {
-	requestURL, err := urlutil.Parse(input.Input.MetaInput.Input)
+	rawURL := input.Input.MetaInput.Input
+	if input.BaseRequest != nil {
+		rawURL = input.BaseRequest.URL.String()
+	}
+	requestURL, err := urlutil.Parse(rawURL)
 	if err != nil {
 		return err
 	}
@@ -83,18 +98,20 @@ func (rule *Rule) executeQueryPartRule(input *ExecuteRuleInput, payload string)
 			if !rule.matchKeyOrValue(key, value) {
 				continue
 			}
-			var evaluated string
-			evaluated, input.InteractURLs = rule.executeEvaluate(input, key, value, payload, input.InteractURLs)
-			cloned[i] = evaluated
+			decoded, escape := unescapeQueryValue(value)
+			for _, fuzzed := range rule.executeValueRule(input, key, key, decoded, payload) {
+				cloned[i] = escape(fuzzed.value)
+				rule.trackInjectionPoints(input, fuzzed.paths)
 
-			if rule.modeType == singleModeType {
-				temp.Update(key, cloned)
-				requestURL.Params = temp
-				if qerr := rule.buildQueryInput(input, requestURL, input.InteractURLs); qerr != nil {
-					err = qerr
-					return false
+				if rule.modeType == singleModeType {
+					temp.Update(key, cloned)
+					requestURL.Params = temp
+					if qerr := rule.buildQueryInput(input, requestURL, input.InteractURLs); qerr != nil {
+						err = qerr
+						return false
+					}
+					cloned[i] = value // change back to previous value for temp
 				}
-				cloned[i] = value // change back to previous value for temp
 			}
 		}
 		temp.Update(key, cloned)
@@ -111,6 +128,198 @@ func (rule *Rule) executeQueryPartRule(input *ExecuteRuleInput, payload string)
 	return err
 }
 
+// executePathPartRule executes path part rules. Each non-empty
+// segment of the path is fuzzed with its 1-based index as key.
+func (rule *Rule) executePathPartRule(input *ExecuteRuleInput, payload string) error {
+	requestURL, err := urlutil.Parse(input.BaseRequest.URL.String())
+	if err != nil {
+		return err
+	}
+	segments := strings.Split(requestURL.Path, "/")
+	// clone the segments to avoid modifying the original
+	temp := sliceutil.Clone(segments)
+
+	var index int
+	for i, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		index++
+		key := strconv.Itoa(index)
+		if !rule.matchKeyOrValue(key, segment) {
+			continue
+		}
+		for _, fuzzed := range rule.executeValueRule(input, key, key, segment, payload) {
+			temp[i] = fuzzed.value
+			rule.trackInjectionPoints(input, fuzzed.paths)
+
+			if rule.modeType == singleModeType {
+				generatedURL := requestURL.Clone()
+				generatedURL.Path = strings.Join(temp, "/")
+				if err := rule.buildQueryInput(input, generatedURL, input.InteractURLs); err != nil {
+					return err
+				}
+				temp[i] = segment // change back to previous value for temp
+			}
+		}
+	}
+
+	if rule.modeType == multipleModeType {
+		requestURL.Path = strings.Join(temp, "/")
+		if err := rule.buildQueryInput(input, requestURL, input.InteractURLs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// executeCookiePartRule executes cookie part rules. Each cookie
+// of the Cookie header is fuzzed with its name as key.
+func (rule *Rule) executeCookiePartRule(input *ExecuteRuleInput, payload string) error {
+	cookies := parseCookieHeader(strings.Join(input.BaseRequest.Header.Values("Cookie"), "; "))
+	// clone the values to avoid modifying the original
+	values := make([]string, len(cookies))
+	for i, cookie := range cookies {
+		values[i] = cookie.Value
+	}
+
+	for i, cookie := range cookies {
+		if !rule.matchKeyOrValue(cookie.Name, cookie.Value) {
+			continue
+		}
+		for _, fuzzed := range rule.executeValueRule(input, cookie.Name, cookie.Name, cookie.Value, payload) {
+			values[i] = fuzzed.value
+			rule.trackInjectionPoints(input, fuzzed.paths)
+
+			if rule.modeType == singleModeType {
+				if err := rule.buildCookieInput(input, cookies, values, input.InteractURLs); err != nil {
+					return err
+				}
+				values[i] = cookie.Value // change back to previous value for values
+			}
+		}
+	}
+
+	if rule.modeType == multipleModeType {
+		if err := rule.buildCookieInput(input, cookies, values, input.InteractURLs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildCookieInput returns created request for a Cookie Input
+func (rule *Rule) buildCookieInput(input *ExecuteRuleInput, cookies []*http.Cookie, values []string, interactURLs []string) error {
+	headers := input.BaseRequest.Header.Clone()
+	headers.Set("Cookie", buildCookieHeader(cookies, values))
+	if err := rule.buildHeadersInput(input, headers, interactURLs); err != nil {
+		if err == io.EOF {
+			return types.ErrNoMoreRequests
+		}
+		return err
+	}
+	return nil
+}
+
+// parseCookieHeader parses the name and value pairs of a Cookie header.
+// Unlike http.Request.Cookies, cookies with invalid values are kept.
+func parseCookieHeader(header string) []*http.Cookie {
+	var cookies []*http.Cookie
+	for _, part := range strings.Split(header, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(part, "=")
+		cookies = append(cookies, &http.Cookie{Name: name, Value: value})
+	}
+	return cookies
+}
+
+// buildCookieHeader builds the Cookie header value from cookie names and
+// values. Values are written as is so payloads are not sanitized.
+func buildCookieHeader(cookies []*http.Cookie, values []string) string {
+	var builder strings.Builder
+	for i, cookie := range cookies {
+		if i > 0 {
+			builder.WriteString("; ")
+		}
+		builder.WriteString(cookie.Name)
+		builder.WriteString("=")
+		builder.WriteString(values[i])
+	}
+	return builder.String()
+}
+
+// executeBodyPartRule executes body part rules
+func (rule *Rule) executeBodyPartRule(input *ExecuteRuleInput, payload string) error {
+	body, err := input.BaseRequest.BodyBytes()
+	if err != nil {
+		return errors.Wrap(err, "could not read request body")
+	}
+	if len(body) == 0 {
+		return ErrRuleNotApplicable
+	}
+	// parse the body for each payload as leaves are updated in place
+	document, err := parseBody(input.BaseRequest.Header.Get("Content-Type"), string(body))
+	if err != nil {
+		gologger.Verbose().Msgf("Could not parse body for body part rule %v: %s\n", rule, err)
+		return ErrRuleNotApplicable
+	}
+
+	for _, leaf := range document.leaves() {
+		if !rule.matchKeyOrValue(leaf.key, leaf.value) {
+			continue
+		}
+		for _, fuzzed := range rule.executeValueRule(input, leaf.key, leaf.path, leaf.value, payload) {
+			leaf.set(fuzzed.value)
+			rule.trackInjectionPoints(input, fuzzed.paths)
+
+			if rule.modeType == singleModeType {
+				if err := rule.buildBodyInput(input, document, input.InteractURLs); err != nil {
+					return err
+				}
+				leaf.reset() // change back to previous value for body
+			}
+		}
+	}
+
+	if rule.modeType == multipleModeType {
+		if err := rule.buildBodyInput(input, document, input.InteractURLs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildBodyInput returns created request for a Body Input
+func (rule *Rule) buildBodyInput(input *ExecuteRuleInput, document bodyDocument, interactURLs []string) error {
+	body, err := document.encode()
+	if err != nil {
+		return errors.Wrap(err, "could not encode request body")
+	}
+	bodyReader, err := readerutil.NewReusableReadCloser([]byte(body))
+	if err != nil {
+		return errors.Wrap(err, "could not create reusable reader for request body")
+	}
+	req := input.BaseRequest.Clone(context.TODO())
+	req.Body = bodyReader
+	req.ContentLength = int64(len(body))
+	if req.Header.Get("Content-Length") != "" {
+		req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	request := GeneratedRequest{
+		Request:         req,
+		InteractURLs:    interactURLs,
+		DynamicValues:   input.Values,
+		InjectionPoints: input.injectionPoints,
+	}
+	if !input.Callback(request) {
+		return types.ErrNoMoreRequests
+	}
+	return nil
+}
+
 // buildHeadersInput returns created request for a Headers Input
 func (rule *Rule) buildHeadersInput(input *ExecuteRuleInput, headers http.Header, interactURLs []string) error {
 	var req *retryablehttp.Request
@@ -118,15 +327,17 @@ func (rule *Rule) buildHeadersInput(input *ExecuteRuleInput, headers http.Header
 		return errors.New("Base request cannot be nil when fuzzing headers")
 	} else {
 		req = input.BaseRequest.Clone(context.TODO())
-		req.Header = headers
+		// clone the headers as they are modified after the request is built
+		req.Header = headers.Clone()
 		// update host of request and not URL
 		// URL.Host is used to dial the connection
 		req.Request.Host = req.Header.Get("Host")
 	}
 	request := GeneratedRequest{
-		Request:       req,
-		InteractURLs:  interactURLs,
-		DynamicValues: input.Values,
+		Request:         req,
+		InteractURLs:    interactURLs,
+		DynamicValues:   input.Values,
+		InjectionPoints: input.injectionPoints,
 	}
 	if !input.Callback(request) {
 		return io.EOF
@@ -138,6 +349,8 @@ func (rule *Rule) buildHeadersInput(input *ExecuteRuleInput, headers http.Header
 func (rule *Rule) buildQueryInput(input *ExecuteRuleInput, parsed *urlutil.URL, interactURLs []string) error {
 	var req *retryablehttp.Request
 	var err error
+	// clone the url as it is modified after the request is built
+	parsed = parsed.Clone()
 	if input.BaseRequest == nil {
 		req, err = retryablehttp.NewRequestFromURL(http.MethodGet, parsed, nil)
 		if err != nil {
@@ -149,9 +362,10 @@ func (rule *Rule) buildQueryInput(input *ExecuteRuleInput, parsed *urlutil.URL,
 		req.SetURL(parsed)
 	}
 	request := GeneratedRequest{
-		Request:       req,
-		InteractURLs:  interactURLs,
-		DynamicValues: input.Values,
+		Request:         req,
+		InteractURLs:    interactURLs,
+		DynamicValues:   input.Values,
+		InjectionPoints: input.injectionPoints,
 	}
 	if !input.Callback(request) {
 		return types.ErrNoMoreRequests
@@ -8,6 +8,7 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/interactsh"
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
 	"github.com/stretchr/testify/require"
 )
 
@@ -120,6 +121,30 @@ func TestExecuteQueryPartRule(t *testing.T) {
 		require.NoError(t, err, "could not execute part rule")
 		require.Equal(t, "http://localhost:8080/?url=localhost1337'&mode=multiple1337'&file=passwdfile1337'", generatedURL, "could not get generated url")
 	})
+	t.Run("encoded", func(t *testing.T) {
+		rule := &Rule{
+			ruleType: postfixRuleType,
+			partType: queryPartType,
+			modeType: singleModeType,
+			options:  options,
+		}
+		var generatedURL string
+		var injectionPoints []string
+		// base64 of {"id":1} sent with its padding url encoded
+		input := contextargs.NewWithInput("http://localhost:8080/?data=eyJpZCI6MX0%3D")
+		err := rule.executeQueryPartRule(&ExecuteRuleInput{
+			Input: input,
+			Callback: func(gr GeneratedRequest) bool {
+				generatedURL = gr.Request.URL.String()
+				injectionPoints = gr.InjectionPoints
+				return true
+			},
+		}, "'")
+		require.NoError(t, err, "could not execute part rule")
+		// base64 of {"id":"1'"}
+		require.Equal(t, "http://localhost:8080/?data=eyJpZCI6IjEnIn0%3D", generatedURL, "could not get generated url")
+		require.Equal(t, []string{"data:base64:json:id"}, injectionPoints, "could not get injection points")
+	})
 }
 
 func TestExecuteReplaceRule(t *testing.T) {
@@ -142,3 +167,183 @@ func TestExecuteReplaceRule(t *testing.T) {
 		require.Equal(t, test.expected, returned, "could not get correct value")
 	}
 }
+
+func TestExecuteBodyPartRule(t *testing.T) {
+	options := &protocols.ExecutorOptions{
+		Interactsh: &interactsh.Client{},
+	}
+	req, err := retryablehttp.NewRequest("POST", "http://localhost:8080/", `{"user":{"name":"john","ids":[1]},"admin":false}`)
+	require.NoError(t, err, "can't build request")
+	req.Header.Set("Content-Type", "application/json")
+
+	t.Run("single", func(t *testing.T) {
+		rule := &Rule{
+			ruleType: postfixRuleType,
+			partType: bodyPartType,
+			modeType: singleModeType,
+			options:  options,
+		}
+		var generatedBodies []string
+		err := rule.executeBodyPartRule(&ExecuteRuleInput{
+			Input:       contextargs.New(),
+			BaseRequest: req,
+			Callback: func(gr GeneratedRequest) bool {
+				body, _ := gr.Request.BodyBytes()
+				require.Equal(t, int64(len(body)), gr.Request.ContentLength, "could not get correct content length")
+				generatedBodies = append(generatedBodies, string(body))
+				return true
+			},
+		}, "1337'")
+		require.NoError(t, err, "could not execute part rule")
+		require.ElementsMatch(t, []string{
+			`{"admin":"false1337'","user":{"ids":[1],"name":"john"}}`,
+			`{"admin":false,"user":{"ids":["11337'"],"name":"john"}}`,
+			`{"admin":false,"user":{"ids":[1],"name":"john1337'"}}`,
+		}, generatedBodies, "could not get generated bodies")
+	})
+
+	t.Run("multiple", func(t *testing.T) {
+		rule := &Rule{
+			ruleType: replaceRuleType,
+			partType: bodyPartType,
+			modeType: multipleModeType,
+			options:  options,
+		}
+		var generatedBody string
+		err := rule.executeBodyPartRule(&ExecuteRuleInput{
+			Input:       contextargs.New(),
+			BaseRequest: req,
+			Callback: func(gr GeneratedRequest) bool {
+				body, _ := gr.Request.BodyBytes()
+				generatedBody = string(body)
+				return true
+			},
+		}, "1337'")
+		require.NoError(t, err, "could not execute part rule")
+		require.Equal(t, `{"admin":"1337'","user":{"ids":["1337'"],"name":"1337'"}}`, generatedBody, "could not get generated body")
+	})
+}
+
+func TestExecutePathPartRule(t *testing.T) {
+	options := &protocols.ExecutorOptions{
+		Interactsh: &interactsh.Client{},
+	}
+	req, err := retryablehttp.NewRequest("GET", "http://localhost:8080/users/1337/profile?tab=info", nil)
+	require.NoError(t, err, "can't build request")
+
+	t.Run("single", func(t *testing.T) {
+		rule := &Rule{
+			ruleType: postfixRuleType,
+			partType: pathPartType,
+			modeType: singleModeType,
+			options:  options,
+		}
+		var generatedURL []string
+		err := rule.executePathPartRule(&ExecuteRuleInput{
+			Input:       contextargs.New(),
+			BaseRequest: req,
+			Callback: func(gr GeneratedRequest) bool {
+				generatedURL = append(generatedURL, gr.Request.URL.String())
+				return true
+			},
+		}, "'")
+		require.NoError(t, err, "could not execute part rule")
+		require.ElementsMatch(t, []string{
+			"http://localhost:8080/users'/1337/profile?tab=info",
+			"http://localhost:8080/users/1337'/profile?tab=info",
+			"http://localhost:8080/users/1337/profile'?tab=info",
+		}, generatedURL, "could not get generated url")
+	})
+
+	t.Run("keys", func(t *testing.T) {
+		rule := &Rule{Keys: []string{"2"}, Type: "replace", Part: "path", Mode: "single"}
+		err := rule.Compile(nil, options)
+		require.NoError(t, err, "could not compile rule")
+
+		var generatedURL []string
+		err = rule.executePathPartRule(&ExecuteRuleInput{
+			Input:       contextargs.New(),
+			BaseRequest: req,
+			Callback: func(gr GeneratedRequest) bool {
+				generatedURL = append(generatedURL, gr.Request.URL.String())
+				return true
+			},
+		}, "1")
+		require.NoError(t, err, "could not execute part rule")
+		require.Equal(t, []string{"http://localhost:8080/users/1/profile?tab=info"}, generatedURL, "could not get generated url")
+	})
+}
+
+func TestExecuteCookiePartRule(t *testing.T) {
+	options := &protocols.ExecutorOptions{
+		Interactsh: &interactsh.Client{},
+	}
+	req, err := retryablehttp.NewRequest("GET", "http://localhost:8080/", nil)
+	require.NoError(t, err, "can't build request")
+	req.Header.Set("Cookie", "session=abc; lang=en")
+
+	t.Run("single", func(t *testing.T) {
+		rule := &Rule{
+			ruleType: postfixRuleType,
+			partType: cookiePartType,
+			modeType: singleModeType,
+			options:  options,
+		}
+		var generatedCookies []string
+		err := rule.executeCookiePartRule(&ExecuteRuleInput{
+			Input:       contextargs.New(),
+			BaseRequest: req,
+			Callback: func(gr GeneratedRequest) bool {
+				generatedCookies = append(generatedCookies, gr.Request.Header.Get("Cookie"))
+				return true
+			},
+		}, "1337'")
+		require.NoError(t, err, "could not execute part rule")
+		require.ElementsMatch(t, []string{
+			"session=abc1337'; lang=en",
+			"session=abc; lang=en1337'",
+		}, generatedCookies, "could not get generated cookies")
+	})
+
+	t.Run("multiple", func(t *testing.T) {
+		rule := &Rule{
+			ruleType: postfixRuleType,
+			partType: cookiePartType,
+			modeType: multipleModeType,
+			options:  options,
+		}
+		var generatedCookie string
+		err := rule.executeCookiePartRule(&ExecuteRuleInput{
+			Input:       contextargs.New(),
+			BaseRequest: req,
+			Callback: func(gr GeneratedRequest) bool {
+				generatedCookie = gr.Request.Header.Get("Cookie")
+				return true
+			},
+		}, "1337'")
+		require.NoError(t, err, "could not execute part rule")
+		require.Equal(t, "session=abc1337'; lang=en1337'", generatedCookie, "could not get generated cookie")
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		for _, mode := range []modeType{singleModeType, multipleModeType} {
+			rule := &Rule{
+				ruleType: postfixRuleType,
+				partType: cookiePartType,
+				modeType: mode,
+				options:  options,
+			}
+			var generated int
+			err := rule.executeCookiePartRule(&ExecuteRuleInput{
+				Input:       contextargs.New(),
+				BaseRequest: req,
+				Callback: func(gr GeneratedRequest) bool {
+					generated++
+					return false
+				},
+			}, "1337'")
+			require.ErrorIs(t, err, types.ErrNoMoreRequests, "could not stop cookie part rule")
+			require.Equal(t, 1, generated, "could generate requests after callback stopped")
+		}
+	})
+}
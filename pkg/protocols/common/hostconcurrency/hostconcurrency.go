@@ -0,0 +1,100 @@
+// Package hostconcurrency caps the number of requests in flight per host
+// shared across all templates and protocols.
+package hostconcurrency
+
+import (
+	"sync"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/utils"
+)
+
+// Limiter caps the number of requests in flight per host. A nil
+// limiter does not limit any request.
+type Limiter struct {
+	maxInFlight int
+
+	mu    sync.Mutex
+	hosts map[string]*host
+}
+
+// host contains the slots of requests in flight to a host. It is
+// removed once no request holds or waits for a slot.
+type host struct {
+	slots chan struct{}
+	refs  int
+	// batch serializes the acquisitions of several slots so that
+	// batches holding some of the slots do not wait for each other
+	batch sync.Mutex
+}
+
+// New creates a new limiter allowing maxInFlight requests in flight per host
+func New(maxInFlight int) *Limiter {
+	return &Limiter{maxInFlight: maxInFlight, hosts: make(map[string]*host)}
+}
+
+// Acquire blocks until a request can be sent to the host of the value
+// which can be a URL, a host:port or a host. It returns the function
+// releasing the slot of the request which can be called more than once.
+func (l *Limiter) Acquire(value string) func() {
+	return l.AcquireN(value, 1)
+}
+
+// AcquireN blocks until n requests sent together, like the requests of a
+// race condition, can be sent to the host of the value. At most all the
+// slots of the host are acquired. It returns the function releasing the
+// slots which can be called more than once.
+func (l *Limiter) AcquireN(value string, n int) func() {
+	if l == nil || l.maxInFlight <= 0 || n <= 0 {
+		return func() {}
+	}
+	n = min(n, l.maxInFlight)
+	key := utils.Hostname(value)
+
+	l.mu.Lock()
+	h, ok := l.hosts[key]
+	if !ok {
+		h = &host{slots: make(chan struct{}, l.maxInFlight)}
+		l.hosts[key] = h
+	}
+	h.refs++
+	l.mu.Unlock()
+
+	if n == 1 {
+		h.slots <- struct{}{}
+	} else {
+		h.batch.Lock()
+		for i := 0; i < n; i++ {
+			h.slots <- struct{}{}
+		}
+		h.batch.Unlock()
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			for i := 0; i < n; i++ {
+				<-h.slots
+			}
+
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			h.refs--
+			if h.refs == 0 {
+				delete(l.hosts, key)
+			}
+		})
+	}
+}
+
+// InFlight returns the number of requests in flight to the host of the value
+func (l *Limiter) InFlight(value string) int {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if h, ok := l.hosts[utils.Hostname(value)]; ok {
+		return len(h.slots)
+	}
+	return 0
+}
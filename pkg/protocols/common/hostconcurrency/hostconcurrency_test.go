@@ -0,0 +1,82 @@
+package hostconcurrency
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiterAcquire(t *testing.T) {
+	limiter := New(2)
+
+	var inFlight, maxInFlight atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release := limiter.Acquire("https://example.com/path")
+			defer release()
+
+			current := inFlight.Add(1)
+			for {
+				previous := maxInFlight.Load()
+				if current <= previous || maxInFlight.CompareAndSwap(previous, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			inFlight.Add(-1)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(2), maxInFlight.Load(), "could not cap requests in flight to the host")
+	require.Empty(t, limiter.hosts, "could not remove idle hosts")
+}
+
+func TestLimiterHosts(t *testing.T) {
+	limiter := New(1)
+
+	release := limiter.Acquire("example.com:443")
+	require.Equal(t, 1, limiter.InFlight("http://example.com"), "could not share slots of the host across schemes and ports")
+
+	// other hosts are not limited by the requests in flight to the host
+	other := limiter.Acquire("other.com")
+	other()
+
+	release()
+	release()
+	require.Zero(t, limiter.InFlight("example.com"), "could not release slot once")
+
+	var unset *Limiter
+	unset.Acquire("example.com")()
+	require.Zero(t, New(0).InFlight("example.com"))
+	New(0).Acquire("example.com")()
+}
+
+func TestLimiterAcquireN(t *testing.T) {
+	limiter := New(2)
+
+	release := limiter.AcquireN("example.com", 5)
+	require.Equal(t, 2, limiter.InFlight("example.com"), "could not acquire all slots of the host")
+	release()
+	release()
+	require.Zero(t, limiter.InFlight("example.com"), "could not release slots once")
+
+	// batches and single requests do not wait for each other forever
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			release := limiter.AcquireN("example.com", n%3+1)
+			time.Sleep(time.Millisecond)
+			release()
+		}(i)
+	}
+	wg.Wait()
+	require.Empty(t, limiter.hosts, "could not remove idle hosts")
+}
@@ -31,6 +31,7 @@ type Cache struct {
 	verbose       bool
 	failedTargets gcache.Cache
 	TrackError    []string
+	throttled     func(value string) bool
 }
 
 type cacheItem struct {
@@ -53,6 +54,13 @@ func (c *Cache) SetVerbose(verbose bool) {
 	c.verbose = verbose
 }
 
+// SetThrottled sets the check of hosts slowed down for throttling requests.
+// Timeouts of throttled hosts are not counted as they are not unresponsive,
+// connection errors are still counted.
+func (c *Cache) SetThrottled(throttled func(value string) bool) {
+	c.throttled = throttled
+}
+
 // Close closes the host errors cache
 func (c *Cache) Close() {
 	c.failedTargets.Purge()
@@ -111,6 +119,9 @@ func (c *Cache) MarkFailed(value string, err error) {
 	if !c.checkError(err) {
 		return
 	}
+	if c.throttled != nil && !reConnectionError.MatchString(err.Error()) && c.throttled(value) {
+		return
+	}
 	finalValue := c.normalizeCacheValue(value)
 	existingCacheItem, err := c.failedTargets.GetIFPresent(finalValue)
 	if err != nil || existingCacheItem == nil {
@@ -126,6 +137,9 @@ func (c *Cache) MarkFailed(value string, err error) {
 
 var reCheckError = regexp.MustCompile(`(no address found for host|Client\.Timeout exceeded while awaiting headers|could not resolve host|connection refused)`)
 
+// reConnectionError matches the errors counted for hosts being throttled
+var reConnectionError = regexp.MustCompile(`(no address found for host|could not resolve host|connection refused)`)
+
 // checkError checks if an error represents a type that should be
 // added to the host skipping table.
 func (c *Cache) checkError(err error) bool {
@@ -139,3 +139,22 @@ func TestCacheMarkFailedConcurrent(t *testing.T) {
 		require.EqualValues(t, test.expected, value.errors.Load())
 	}
 }
+
+func TestCacheMarkFailedThrottled(t *testing.T) {
+	cache := New(3, DefaultMaxHostsCount, nil)
+	cache.SetThrottled(func(value string) bool {
+		return value == "http://throttled.com"
+	})
+
+	for i := 0; i < 3; i++ {
+		cache.MarkFailed("http://throttled.com", fmt.Errorf("Client.Timeout exceeded while awaiting headers"))
+		cache.MarkFailed("http://example.com", fmt.Errorf("Client.Timeout exceeded while awaiting headers"))
+	}
+	require.False(t, cache.Check("http://throttled.com"), "could count errors of throttled host")
+	require.True(t, cache.Check("http://example.com"))
+
+	for i := 0; i < 3; i++ {
+		cache.MarkFailed("http://throttled.com", fmt.Errorf("dial tcp: connection refused"))
+	}
+	require.True(t, cache.Check("http://throttled.com"), "could not count connection errors of throttled host")
+}
@@ -0,0 +1,307 @@
+// Package hostratelimit implements adaptive per-host rate limiting of
+// requests layered under the global rate limiter.
+//
+// Hosts responding with 429 or 503 status codes or resetting connections
+// are backed off honouring Retry-After headers, and the rate of requests
+// sent to them is halved. The rate is then increased gradually while the
+// host responds normally until it is recovered.
+package hostratelimit
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/bluele/gcache"
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/progress"
+	"github.com/khulnasoft-lab/vulmap/pkg/utils"
+)
+
+const (
+	// DefaultMaxHostsCount is the maximum number of hosts tracked at a time
+	DefaultMaxHostsCount = 10000
+
+	// minRate is the minimum number of requests per second of backed off hosts
+	minRate = 1.0
+	// initialBackoff is the first wait of hosts throttling requests without Retry-After
+	initialBackoff = time.Second
+	// maxBackoff is the maximum wait of hosts throttling requests
+	maxBackoff = time.Minute
+	// adjustInterval is the minimum interval between rate adjustments of a
+	// host so that responses of requests already in flight are not counted twice
+	adjustInterval = time.Second
+	// recoverySteps is the number of increases to recover the rate of a host
+	recoverySteps = 10
+)
+
+// Options contains the configuration of the limiter
+type Options struct {
+	// MaxRate is the maximum number of requests per second sent to
+	// a host. Zero means only the global rate limit is applied.
+	MaxRate int
+	// Adaptive enables slowing down hosts throttling requests
+	Adaptive bool
+	// Progress is the progress client the backoff state is reported to
+	Progress progress.Progress
+}
+
+// Limiter is an adaptive per-host rate limiter. A nil limiter does
+// not limit any request.
+type Limiter struct {
+	options *Options
+
+	mu    sync.Mutex
+	hosts gcache.Cache
+}
+
+// hostState is the token bucket of a host
+type hostState struct {
+	mu sync.Mutex
+
+	// rate is the current number of requests per second, zero if unlimited
+	rate float64
+	// next is the time the next request can be sent at
+	next time.Time
+	// until is the time the host is blocked until
+	until time.Time
+
+	// backoff is true while the host is being slowed down
+	backoff bool
+	// ceiling is the rate the host is recovered to
+	ceiling float64
+	// wait is the current wait without Retry-After
+	wait time.Duration
+	// adjusted is the time of the last rate adjustment
+	adjusted time.Time
+
+	// window, count and previous track the number of requests sent
+	// in the current and the previous second to estimate the rate
+	window   time.Time
+	count    int
+	previous int
+}
+
+// New creates a new per-host rate limiter
+func New(options *Options) *Limiter {
+	limiter := &Limiter{options: options}
+	limiter.hosts = gcache.New(DefaultMaxHostsCount).
+		LRU().
+		EvictedFunc(func(_, value interface{}) {
+			state := value.(*hostState)
+			state.mu.Lock()
+			defer state.mu.Unlock()
+			if state.backoff {
+				limiter.addBackoffHosts(-1)
+			}
+		}).
+		Build()
+	return limiter
+}
+
+// Take blocks until a request can be sent to the host of the value
+// which can be a URL, a host:port or a host.
+func (l *Limiter) Take(value string) {
+	if l == nil {
+		return
+	}
+	if wait := l.state(value).reserve(time.Now(), float64(l.options.MaxRate)); wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+// Observe adjusts the rate of the host of the value from the
+// response or the error of a request sent to it.
+func (l *Limiter) Observe(value string, resp *http.Response, err error) {
+	if l == nil || !l.options.Adaptive {
+		return
+	}
+	switch {
+	case err != nil:
+		if isConnectionReset(err) {
+			l.throttle(value, 0)
+		}
+	case resp == nil:
+		return
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable:
+		l.throttle(value, RetryAfter(resp.Header, time.Now()))
+	default:
+		l.recover(value)
+	}
+}
+
+// Throttled returns true if the host of the value is being slowed down
+func (l *Limiter) Throttled(value string) bool {
+	if l == nil {
+		return false
+	}
+	item, err := l.hosts.GetIFPresent(utils.Hostname(value))
+	if err != nil {
+		return false
+	}
+	state := item.(*hostState)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	return state.backoff
+}
+
+// Close purges the state of the hosts
+func (l *Limiter) Close() {
+	if l == nil {
+		return
+	}
+	l.hosts.Purge()
+}
+
+// throttle halves the rate of the host and blocks it for the
+// retry-after duration or the exponential backoff wait.
+func (l *Limiter) throttle(value string, retryAfter time.Duration) {
+	if l.options.Progress != nil {
+		l.options.Progress.IncrementThrottled()
+	}
+	key := utils.Hostname(value)
+	state := l.state(value)
+	now := time.Now()
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if !state.backoff {
+		state.backoff = true
+		state.ceiling = state.rate
+		if state.ceiling == 0 {
+			state.ceiling = state.estimate()
+		}
+		state.rate = state.ceiling
+		l.addBackoffHosts(1)
+		gologger.Verbose().Msgf("Slowing down requests to %s as it is throttling requests\n", key)
+	}
+	if now.Sub(state.adjusted) >= adjustInterval {
+		state.adjusted = now
+		state.rate = max(state.rate/2, minRate)
+		state.wait = min(max(state.wait*2, initialBackoff), maxBackoff)
+	}
+	wait := state.wait
+	if retryAfter > 0 {
+		wait = min(retryAfter, maxBackoff)
+	}
+	if until := now.Add(wait); until.After(state.until) {
+		state.until = until
+	}
+}
+
+// recover increases the rate of a backed off host by a step of its
+// ceiling at most once per interval until the ceiling is reached.
+func (l *Limiter) recover(value string) {
+	item, err := l.hosts.GetIFPresent(utils.Hostname(value))
+	if err != nil {
+		return
+	}
+	state := item.(*hostState)
+	now := time.Now()
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if !state.backoff || now.Before(state.until) || now.Sub(state.adjusted) < adjustInterval {
+		return
+	}
+	state.adjusted = now
+	state.rate += max(state.ceiling/recoverySteps, minRate)
+	state.wait /= 2
+	if state.rate < state.ceiling {
+		return
+	}
+	state.backoff = false
+	state.rate = float64(l.options.MaxRate)
+	state.wait = 0
+	l.addBackoffHosts(-1)
+	gologger.Verbose().Msgf("Recovered rate of requests to %s\n", utils.Hostname(value))
+}
+
+// state returns the state of the host of the value
+func (l *Limiter) state(value string) *hostState {
+	key := utils.Hostname(value)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if item, err := l.hosts.GetIFPresent(key); err == nil {
+		return item.(*hostState)
+	}
+	state := &hostState{rate: float64(l.options.MaxRate)}
+	_ = l.hosts.Set(key, state)
+	return state
+}
+
+// addBackoffHosts reports the change of the number of backed off hosts
+func (l *Limiter) addBackoffHosts(delta int) {
+	if l.options.Progress != nil {
+		l.options.Progress.AddBackoffHosts(delta)
+	}
+}
+
+// reserve reserves a slot for a request returning the duration
+// to wait for before sending it.
+func (s *hostState) reserve(now time.Time, maxRate float64) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elapsed := now.Sub(s.window); elapsed >= time.Second {
+		s.previous = 0
+		if elapsed < 2*time.Second {
+			s.previous = s.count
+		}
+		s.window = now
+		s.count = 0
+	}
+	s.count++
+
+	start := now
+	if s.until.After(start) {
+		start = s.until
+	}
+	if s.rate == 0 && maxRate == 0 {
+		return start.Sub(now)
+	}
+	rate := s.rate
+	if rate == 0 {
+		rate = maxRate
+	}
+	if s.next.Before(start) {
+		s.next = start
+	}
+	wait := s.next.Sub(now)
+	s.next = s.next.Add(time.Duration(float64(time.Second) / rate))
+	return wait
+}
+
+// estimate returns the number of requests per second sent to the host
+func (s *hostState) estimate() float64 {
+	return max(float64(max(s.count, s.previous)), minRate)
+}
+
+// RetryAfter returns the duration of the Retry-After header which
+// can either be a number of seconds or a date.
+func RetryAfter(header http.Header, now time.Time) time.Duration {
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0)
+	}
+	return 0
+}
+
+// isConnectionReset returns true if the error is a connection reset by the host
+func isConnectionReset(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) || strings.Contains(err.Error(), "connection reset by peer")
+}
@@ -0,0 +1,109 @@
+package hostratelimit
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/progress"
+)
+
+// backoffProgress records the backoff state reported by the limiter
+type backoffProgress struct {
+	progress.Progress
+	throttled atomic.Int32
+	backoff   atomic.Int32
+}
+
+func (b *backoffProgress) IncrementThrottled() {
+	b.throttled.Add(1)
+}
+
+func (b *backoffProgress) AddBackoffHosts(delta int) {
+	b.backoff.Add(int32(delta))
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	require.Equal(t, 30*time.Second, RetryAfter(http.Header{"Retry-After": []string{"30"}}, now))
+	require.Equal(t, 2*time.Minute, RetryAfter(http.Header{"Retry-After": []string{"Sun, 01 Jan 2023 00:02:00 GMT"}}, now))
+	require.Zero(t, RetryAfter(http.Header{"Retry-After": []string{"Sat, 31 Dec 2022 23:00:00 GMT"}}, now), "could return duration of past date")
+	require.Zero(t, RetryAfter(http.Header{"Retry-After": []string{"soon"}}, now))
+	require.Zero(t, RetryAfter(http.Header{}, now))
+}
+
+func TestReserve(t *testing.T) {
+	now := time.Now()
+	state := &hostState{rate: 2}
+
+	require.Zero(t, state.reserve(now, 0))
+	require.Equal(t, 500*time.Millisecond, state.reserve(now, 0))
+	require.Equal(t, time.Second, state.reserve(now, 0))
+
+	// blocked hosts are not sent requests before the block ends
+	state = &hostState{until: now.Add(5 * time.Second)}
+	require.Equal(t, 5*time.Second, state.reserve(now, 0))
+	require.Zero(t, (&hostState{}).reserve(now, 0), "could limit unlimited host")
+}
+
+func TestThrottle(t *testing.T) {
+	stats := &backoffProgress{}
+	limiter := New(&Options{Adaptive: true, Progress: stats})
+
+	state := limiter.state("https://example.com")
+	for i := 0; i < 40; i++ {
+		state.reserve(time.Now(), 0)
+	}
+	limiter.Observe("https://example.com/login", &http.Response{StatusCode: http.StatusOK}, nil)
+	require.False(t, limiter.Throttled("example.com"), "could throttle host responding normally")
+
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"20"}}}
+	limiter.Observe("https://example.com/login", resp, nil)
+	limiter.Observe("https://example.com/login", resp, nil)
+	require.True(t, limiter.Throttled("example.com:443"), "could not throttle host")
+	require.Equal(t, float64(20), state.rate, "could not halve rate of host once for requests in flight")
+	require.WithinDuration(t, time.Now().Add(20*time.Second), state.until, time.Second, "could not honour retry-after")
+	require.Equal(t, int32(2), stats.throttled.Load())
+	require.Equal(t, int32(1), stats.backoff.Load())
+
+	// the rate is not recovered while the host is blocked
+	limiter.Observe("example.com", &http.Response{StatusCode: http.StatusOK}, nil)
+	require.Equal(t, float64(20), state.rate)
+
+	for i := 0; limiter.Throttled("example.com"); i++ {
+		require.Less(t, i, recoverySteps, "could not recover rate of host")
+		state.until = time.Time{}
+		state.adjusted = time.Time{}
+		limiter.Observe("example.com", &http.Response{StatusCode: http.StatusOK}, nil)
+	}
+	require.Zero(t, state.rate, "could not recover unlimited rate of host")
+	require.Zero(t, stats.backoff.Load())
+}
+
+func TestThrottleConnectionReset(t *testing.T) {
+	limiter := New(&Options{Adaptive: true, MaxRate: 10})
+
+	limiter.Observe("example.com", nil, errors.New("context deadline exceeded"))
+	require.False(t, limiter.Throttled("example.com"))
+
+	limiter.Observe("example.com", nil, syscall.ECONNRESET)
+	require.True(t, limiter.Throttled("example.com"), "could not throttle host resetting connections")
+	state := limiter.state("example.com")
+	require.Equal(t, float64(5), state.rate)
+	require.WithinDuration(t, time.Now().Add(initialBackoff), state.until, time.Second, "could not back off host")
+
+	disabled := New(&Options{MaxRate: 10})
+	disabled.Observe("example.com", &http.Response{StatusCode: http.StatusServiceUnavailable}, nil)
+	require.False(t, disabled.Throttled("example.com"), "could throttle host with adaptive rate limit disabled")
+
+	var unset *Limiter
+	unset.Take("example.com")
+	unset.Observe("example.com", &http.Response{StatusCode: http.StatusTooManyRequests}, nil)
+	require.False(t, unset.Throttled("example.com"))
+}
@@ -0,0 +1,240 @@
+package grpc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/jhump/protoreflect/dynamic/grpcdynamic"
+	"github.com/jhump/protoreflect/grpcreflect"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v3"
+)
+
+// inlineProtoName is the name the inline proto definition of a request is parsed as
+const inlineProtoName = "inline.proto"
+
+// contextDialer dials a connection to an address
+type contextDialer func(ctx context.Context, address string) (net.Conn, error)
+
+// dial connects to the grpc server at the address with the dialer. TLS
+// connections are established by the dialer with the tls settings of the
+// engine so that the connection is used as is by the grpc client.
+func dial(ctx context.Context, address string, dialer contextDialer) (*grpc.ClientConn, error) {
+	dialOptions := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithBlock(),
+		grpc.WithReturnConnectionError(),
+	}
+	if dialer != nil {
+		dialOptions = append(dialOptions, grpc.WithContextDialer(dialer))
+	}
+	return grpc.DialContext(ctx, address, dialOptions...)
+}
+
+// parseProtoFiles parses the proto files and the inline proto definition
+// into file descriptors. Imports are resolved through the accessor.
+func parseProtoFiles(files []string, inline string, accessor protoparse.FileAccessor) ([]*desc.FileDescriptor, error) {
+	if inline != "" {
+		files = append(files, inlineProtoName)
+	}
+	parser := protoparse.Parser{
+		Accessor: func(filename string) (io.ReadCloser, error) {
+			if inline != "" && filename == inlineProtoName {
+				return io.NopCloser(strings.NewReader(inline)), nil
+			}
+			return accessor(filename)
+		},
+	}
+	return parser.ParseFiles(files...)
+}
+
+// splitMethod returns the fully qualified service name and the method name
+// of a method in the package.Service/Method or package.Service.Method format.
+func splitMethod(method string) (string, string, error) {
+	method = strings.TrimPrefix(strings.TrimSpace(method), "/")
+	index := strings.LastIndex(method, "/")
+	if index == -1 {
+		index = strings.LastIndex(method, ".")
+	}
+	if index <= 0 || index == len(method)-1 {
+		return "", "", errors.Errorf("invalid method %s, expected package.Service/Method", method)
+	}
+	return method[:index], method[index+1:], nil
+}
+
+// resolveMethod resolves the descriptor of the method from the parsed
+// proto files, or through server reflection when no files are given.
+func resolveMethod(ctx context.Context, conn *grpc.ClientConn, files []*desc.FileDescriptor, method string) (*desc.MethodDescriptor, error) {
+	serviceName, methodName, err := splitMethod(method)
+	if err != nil {
+		return nil, err
+	}
+
+	var service *desc.ServiceDescriptor
+	if len(files) > 0 {
+		for _, file := range files {
+			if service = file.FindService(serviceName); service != nil {
+				break
+			}
+		}
+		if service == nil {
+			return nil, errors.Errorf("service %s not found in proto files", serviceName)
+		}
+	} else {
+		client := grpcreflect.NewClientAuto(ctx, conn)
+		defer client.Reset()
+
+		service, err = client.ResolveService(serviceName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not resolve service %s through server reflection", serviceName)
+		}
+	}
+
+	descriptor := service.FindMethodByName(methodName)
+	if descriptor == nil {
+		return nil, errors.Errorf("method %s not found in service %s", methodName, serviceName)
+	}
+	if descriptor.IsClientStreaming() {
+		return nil, errors.Errorf("client streaming method %s is not supported", descriptor.GetFullyQualifiedName())
+	}
+	return descriptor, nil
+}
+
+// buildMessage builds the input message of the method from a JSON or YAML document.
+func buildMessage(method *desc.MethodDescriptor, document string) (*dynamic.Message, error) {
+	message := dynamic.NewMessage(method.GetInputType())
+
+	data := []byte(strings.TrimSpace(document))
+	if len(data) == 0 {
+		return message, nil
+	}
+	if !json.Valid(data) {
+		var value interface{}
+		if err := yaml.Unmarshal(data, &value); err != nil {
+			return nil, errors.Wrap(err, "could not parse message")
+		}
+		converted, err := json.Marshal(value)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not convert message to json")
+		}
+		data = converted
+	}
+	if err := message.UnmarshalJSON(data); err != nil {
+		return nil, errors.Wrapf(err, "could not build %s message", method.GetInputType().GetFullyQualifiedName())
+	}
+	return message, nil
+}
+
+// callResult is the result of a grpc call
+type callResult struct {
+	// responses are the json encoded messages received from the server
+	responses []string
+	header    metadata.MD
+	trailer   metadata.MD
+	status    *status.Status
+}
+
+// invoke calls the unary or server streaming method with the message and metadata.
+//
+// A call failing with a grpc status is not an error, the status is
+// returned in the result to allow matching on it.
+func invoke(ctx context.Context, conn *grpc.ClientConn, method *desc.MethodDescriptor, message proto.Message, md metadata.MD) (*callResult, error) {
+	ctx = metadata.NewOutgoingContext(ctx, md)
+	stub := grpcdynamic.NewStub(conn)
+	result := &callResult{}
+
+	var err error
+	if !method.IsServerStreaming() {
+		var response proto.Message
+		response, err = stub.InvokeRpc(ctx, method, message, grpc.Header(&result.header), grpc.Trailer(&result.trailer))
+		if err == nil {
+			if err = result.addResponse(response); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		var stream *grpcdynamic.ServerStream
+		stream, err = stub.InvokeRpcServerStream(ctx, method, message)
+		if err == nil {
+			for {
+				var response proto.Message
+				if response, err = stream.RecvMsg(); err != nil {
+					break
+				}
+				if err := result.addResponse(response); err != nil {
+					return nil, err
+				}
+			}
+			if err == io.EOF {
+				err = nil
+			}
+			result.header, _ = stream.Header()
+			result.trailer = stream.Trailer()
+		}
+	}
+
+	statusValue, ok := status.FromError(err)
+	if !ok {
+		return nil, err
+	}
+	result.status = statusValue
+	return result, nil
+}
+
+// addResponse adds the json encoding of a response message to the result
+func (result *callResult) addResponse(response proto.Message) error {
+	marshaller := &jsonpb.Marshaler{}
+	encoded, err := marshaller.MarshalToString(response)
+	if err != nil {
+		return errors.Wrap(err, "could not encode response to json")
+	}
+	result.responses = append(result.responses, encoded)
+	return nil
+}
+
+// response returns the json response of the call. Messages of server
+// streaming calls are returned as a json array.
+func (result *callResult) response(streaming bool) string {
+	if streaming {
+		return "[" + strings.Join(result.responses, ",") + "]"
+	}
+	if len(result.responses) == 0 {
+		return ""
+	}
+	return result.responses[0]
+}
+
+// metadataToString returns the metadata as sorted key: value lines.
+// Values of binary keys are base64 encoded.
+func metadataToString(md metadata.MD) string {
+	keys := make([]string, 0, len(md))
+	for key := range md {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	builder := &strings.Builder{}
+	for _, key := range keys {
+		for _, value := range md[key] {
+			if strings.HasSuffix(key, "-bin") {
+				value = base64.StdEncoding.EncodeToString([]byte(value))
+			}
+			builder.WriteString(key + ": " + value + "\n")
+		}
+	}
+	return builder.String()
+}
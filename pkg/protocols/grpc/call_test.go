@@ -0,0 +1,259 @@
+package grpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+const testProto = `syntax = "proto3";
+package test;
+
+message HelloRequest {
+  string name = 1;
+  int32 count = 2;
+}
+
+message HelloReply {
+  string message = 1;
+}
+
+service Greeter {
+  rpc SayHello(HelloRequest) returns (HelloReply);
+  rpc StreamHello(HelloRequest) returns (stream HelloReply);
+}
+`
+
+func TestSplitMethod(t *testing.T) {
+	for method, expected := range map[string][2]string{
+		"test.Greeter/SayHello":       {"test.Greeter", "SayHello"},
+		"/test.Greeter/SayHello":      {"test.Greeter", "SayHello"},
+		"test.Greeter.SayHello":       {"test.Greeter", "SayHello"},
+		"grpc.health.v1.Health/Check": {"grpc.health.v1.Health", "Check"},
+		"grpc.health.v1.Health.Watch": {"grpc.health.v1.Health", "Watch"},
+	} {
+		service, name, err := splitMethod(method)
+		require.Nil(t, err, "could not split method %s", method)
+		require.Equal(t, expected[0], service, "could not get service of %s", method)
+		require.Equal(t, expected[1], name, "could not get method of %s", method)
+	}
+	for _, method := range []string{"", "SayHello", "test.Greeter/"} {
+		_, _, err := splitMethod(method)
+		require.NotNil(t, err, "could split invalid method %q", method)
+	}
+}
+
+func TestBuildMessage(t *testing.T) {
+	files, err := parseProtoFiles(nil, testProto, nil)
+	require.Nil(t, err, "could not parse proto")
+	method := files[0].FindService("test.Greeter").FindMethodByName("SayHello")
+
+	for _, document := range []string{`{"name": "vulmap", "count": 2}`, "name: vulmap\ncount: 2"} {
+		message, err := buildMessage(method, document)
+		require.Nil(t, err, "could not build message from %q", document)
+		require.Equal(t, "vulmap", message.GetFieldByName("name"), "could not set string field")
+		require.Equal(t, int32(2), message.GetFieldByName("count"), "could not set int field")
+	}
+
+	_, err = buildMessage(method, `{"unknown": true}`)
+	require.NotNil(t, err, "could build message with unknown field")
+}
+
+func TestInvoke(t *testing.T) {
+	files, err := parseProtoFiles(nil, testProto, nil)
+	require.Nil(t, err, "could not parse proto")
+	address := startTestServer(t, files[0].FindService("test.Greeter"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	conn, err := dial(ctx, address, nil)
+	require.Nil(t, err, "could not dial server")
+	defer conn.Close()
+
+	t.Run("reflection", func(t *testing.T) {
+		method, err := resolveMethod(ctx, conn, nil, "grpc.health.v1.Health/Check")
+		require.Nil(t, err, "could not resolve method through reflection")
+		message, err := buildMessage(method, "service: ''")
+		require.Nil(t, err, "could not build message")
+
+		result, err := invoke(ctx, conn, method, message, nil)
+		require.Nil(t, err, "could not invoke method")
+		require.Equal(t, codes.OK, result.status.Code(), "could not get status")
+		require.Equal(t, `{"status":"SERVING"}`, result.response(false), "could not get response")
+
+		_, err = resolveMethod(ctx, conn, nil, "test.Unknown/Method")
+		require.NotNil(t, err, "could resolve unknown service")
+	})
+
+	t.Run("unary", func(t *testing.T) {
+		method, err := resolveMethod(ctx, conn, files, "test.Greeter/SayHello")
+		require.Nil(t, err, "could not resolve method from proto")
+		message, err := buildMessage(method, `{"name": "vulmap"}`)
+		require.Nil(t, err, "could not build message")
+
+		result, err := invoke(ctx, conn, method, message, metadata.Pairs("x-greeting", "Hi"))
+		require.Nil(t, err, "could not invoke method")
+		require.Equal(t, `{"message":"Hi vulmap"}`, result.response(false), "could not get response")
+		require.Contains(t, metadataToString(result.header), "x-server: test\n", "could not get header")
+		require.Equal(t, "x-trace-bin: AQI=\n", metadataToString(result.trailer), "could not get trailer")
+
+		message, _ = buildMessage(method, `{"name": "admin"}`)
+		result, err = invoke(ctx, conn, method, message, nil)
+		require.Nil(t, err, "could not invoke method failing with status")
+		require.Equal(t, codes.PermissionDenied, result.status.Code(), "could not get status")
+		require.Equal(t, "admin is not allowed", result.status.Message(), "could not get status message")
+		require.Empty(t, result.response(false), "could get response of failed call")
+	})
+
+	t.Run("server-streaming", func(t *testing.T) {
+		method, err := resolveMethod(ctx, conn, files, "test.Greeter.StreamHello")
+		require.Nil(t, err, "could not resolve method from proto")
+		message, err := buildMessage(method, "name: vulmap\ncount: 3")
+		require.Nil(t, err, "could not build message")
+
+		result, err := invoke(ctx, conn, method, message, nil)
+		require.Nil(t, err, "could not invoke method")
+		require.Equal(t, codes.OK, result.status.Code(), "could not get status")
+		require.Equal(t, `[{"message":"Hello vulmap"},{"message":"Hello vulmap"},{"message":"Hello vulmap"}]`, result.response(true), "could not get responses")
+	})
+}
+
+func TestDialTLS(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen")
+	server := grpc.NewServer(grpc.Creds(credentials.NewServerTLSFromCert(testCertificate(t))))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// connections established by the dialer over tls are used as is
+	conn, err := dial(ctx, listener.Addr().String(), func(ctx context.Context, address string) (net.Conn, error) {
+		dialer := &tls.Dialer{Config: &tls.Config{InsecureSkipVerify: true, NextProtos: []string{"h2"}}}
+		return dialer.DialContext(ctx, "tcp", address)
+	})
+	require.Nil(t, err, "could not dial tls server")
+	defer conn.Close()
+
+	response, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	require.Nil(t, err, "could not call method over tls")
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, response.Status)
+}
+
+// testCertificate returns a self-signed certificate for the tls server
+func testCertificate(t *testing.T) *tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err, "could not generate key")
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+	}
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err, "could not create certificate")
+	return &tls.Certificate{Certificate: [][]byte{certificate}, PrivateKey: key}
+}
+
+func TestParseProtoFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.Nil(t, os.WriteFile(dir+"/greeter.proto", []byte(testProto), 0600), "could not write proto")
+
+	files, err := parseProtoFiles([]string{"greeter.proto"}, "", func(filename string) (io.ReadCloser, error) {
+		return os.Open(dir + "/" + filename)
+	})
+	require.Nil(t, err, "could not parse proto file")
+	require.NotNil(t, files[0].FindService("test.Greeter"), "could not find service")
+
+	_, err = parseProtoFiles(nil, "syntax = \"proto3\";\nmessage {", nil)
+	require.NotNil(t, err, "could parse invalid proto")
+}
+
+// startTestServer starts a grpc server with reflection, the health service
+// and the greeter service implemented with dynamic messages.
+func startTestServer(t *testing.T, greeter *desc.ServiceDescriptor) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen")
+
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	sayHello := greeter.FindMethodByName("SayHello")
+	streamHello := greeter.FindMethodByName("StreamHello")
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: greeter.GetFullyQualifiedName(),
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: sayHello.GetName(),
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				request := dynamic.NewMessage(sayHello.GetInputType())
+				if err := dec(request); err != nil {
+					return nil, err
+				}
+				name := request.GetFieldByName("name").(string)
+				if name == "admin" {
+					return nil, status.Error(codes.PermissionDenied, "admin is not allowed")
+				}
+				greeting := "Hello"
+				if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("x-greeting")) > 0 {
+					greeting = md.Get("x-greeting")[0]
+				}
+				_ = grpc.SetHeader(ctx, metadata.Pairs("x-server", "test"))
+				_ = grpc.SetTrailer(ctx, metadata.Pairs("x-trace-bin", string([]byte{1, 2})))
+
+				reply := dynamic.NewMessage(sayHello.GetOutputType())
+				reply.SetFieldByName("message", strings.Join([]string{greeting, name}, " "))
+				return reply, nil
+			},
+		}},
+		Streams: []grpc.StreamDesc{{
+			StreamName:    streamHello.GetName(),
+			ServerStreams: true,
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				request := dynamic.NewMessage(streamHello.GetInputType())
+				if err := stream.RecvMsg(request); err != nil {
+					return err
+				}
+				for i := int32(0); i < request.GetFieldByName("count").(int32); i++ {
+					reply := dynamic.NewMessage(streamHello.GetOutputType())
+					reply.SetFieldByName("message", "Hello "+request.GetFieldByName("name").(string))
+					if err := stream.SendMsg(reply); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}},
+	}, struct{}{})
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
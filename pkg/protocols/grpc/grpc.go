@@ -0,0 +1,170 @@
+package grpc
+
+import (
+	"io"
+	"path/filepath"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/operators"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/generators"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/network/networkclientpool"
+	"github.com/projectdiscovery/fastdialer/fastdialer"
+)
+
+// Request is a request for the gRPC protocol
+type Request struct {
+	// Operators for the current request go here.
+	operators.Operators `yaml:",inline,omitempty" json:",inline,omitempty"`
+	CompiledOperators   *operators.Operators `yaml:"-" json:"-"`
+
+	// ID is the optional id of the request
+	ID string `yaml:"id,omitempty" json:"id,omitempty" jsonschema:"title=id of the request,description=ID of the gRPC request"`
+	// description: |
+	//   Address is the host:port of the gRPC server.
+	//
+	//   Default value is `{{Hostname}}`. Port 443 is used for addresses without a port.
+	// examples:
+	//   - value: "\"{{Host}}:50051\""
+	Address string `yaml:"address,omitempty" json:"address,omitempty" jsonschema:"title=address for the grpc request,description=Address is the host:port of the gRPC server"`
+	// description: |
+	//   TLS enables TLS for the connection to the server. Plaintext is used by default.
+	TLS bool `yaml:"tls,omitempty" json:"tls,omitempty" jsonschema:"title=use tls for the connection,description=TLS enables TLS for the connection to the server"`
+	// description: |
+	//   Method is the fully qualified method to call.
+	//
+	//   Both the package.Service/Method and package.Service.Method formats are supported.
+	//   Unary and server streaming methods can be called.
+	// examples:
+	//   - value: "\"grpc.health.v1.Health/Check\""
+	Method string `yaml:"method,omitempty" json:"method,omitempty" jsonschema:"title=method to call,description=Method is the fully qualified method to call"`
+	// description: |
+	//   Message is the request message as a JSON or YAML document.
+	//
+	//   It supports DSL Helper Functions as well as normal expressions.
+	// examples:
+	//   - value: "\"{\\\"service\\\": \\\"\\\"}\""
+	Message string `yaml:"message,omitempty" json:"message,omitempty" jsonschema:"title=message to send,description=Message is the request message as a JSON or YAML document"`
+	// description: |
+	//   Metadata contains the metadata headers sent with the call.
+	Metadata map[string]string `yaml:"metadata,omitempty" json:"metadata,omitempty" jsonschema:"title=metadata headers of the call,description=Metadata contains the metadata headers sent with the call"`
+	// description: |
+	//   ProtoFiles contains the .proto files describing the service.
+	//
+	//   Relative paths are resolved from the template directory. The service is
+	//   resolved through server reflection when no proto definition is provided.
+	// examples:
+	//   - value: >
+	//       []string{"helloworld.proto"}
+	ProtoFiles []string `yaml:"proto-files,omitempty" json:"proto-files,omitempty" jsonschema:"title=proto files of the service,description=ProtoFiles contains the .proto files describing the service"`
+	// description: |
+	//   Proto is an inline .proto definition describing the service.
+	Proto string `yaml:"proto,omitempty" json:"proto,omitempty" jsonschema:"title=inline proto definition of the service,description=Proto is an inline .proto definition describing the service"`
+
+	// description: |
+	//   Attack is the type of payload combinations to perform.
+	//
+	//   Batteringram is inserts the same payload into all defined payload positions at once, pitchfork combines multiple payload sets and clusterbomb generates
+	//   permutations and combinations for all payloads.
+	AttackType generators.AttackTypeHolder `yaml:"attack,omitempty" json:"attack,omitempty" jsonschema:"title=attack is the payload combination,description=Attack is the type of payload combinations to perform,enum=batteringram,enum=pitchfork,enum=clusterbomb"`
+	// description: |
+	//   Payloads contains any payloads for the current request.
+	//
+	//   Payloads support both key-values combinations where a list
+	//   of payloads is provided, or optionally a single file can also
+	//   be provided as payload which will be read on run-time.
+	Payloads map[string]interface{} `yaml:"payloads,omitempty" json:"payloads,omitempty" jsonschema:"title=payloads for the grpc request,description=Payloads contains any payloads for the current request"`
+
+	generator *generators.PayloadGenerator
+	// files are the parsed proto definitions of the request
+	files []*desc.FileDescriptor
+
+	// cache any variables that may be needed for operation.
+	dialer  *fastdialer.Dialer
+	options *protocols.ExecutorOptions
+}
+
+// RequestPartDefinitions contains a mapping of request part definitions and their
+// description. Multiple definitions are separated by commas.
+// Definitions not having a name (generated on runtime) are prefixed & suffixed by <>.
+var RequestPartDefinitions = map[string]string{
+	"type":           "Type is the type of request made",
+	"host":           "Host is the input to the template",
+	"matched":        "Matched is the address which was matched upon",
+	"method":         "Method is the fully qualified method called",
+	"request":        "gRPC request made to the server",
+	"response":       "JSON response received from the server (a JSON array for server streaming calls)",
+	"header":         "Metadata headers received from the server",
+	"trailer":        "Metadata trailers received from the server",
+	"status_code":    "Numeric gRPC status code of the call",
+	"status":         "Name of the gRPC status code of the call",
+	"status_message": "Message of the gRPC status of the call",
+}
+
+// GetID returns the unique ID of the request if any.
+func (request *Request) GetID() string {
+	return request.ID
+}
+
+// Compile compiles the request generators preparing any requests possible.
+func (request *Request) Compile(options *protocols.ExecutorOptions) error {
+	request.options = options
+
+	if _, _, err := splitMethod(request.Method); err != nil {
+		return errors.Wrap(err, "could not parse method")
+	}
+	if request.Address == "" {
+		request.Address = "{{Hostname}}"
+	}
+
+	var err error
+	if len(request.ProtoFiles) > 0 || request.Proto != "" {
+		request.files, err = parseProtoFiles(request.ProtoFiles, request.Proto, request.openProtoFile)
+		if err != nil {
+			return errors.Wrap(err, "could not parse proto files")
+		}
+	}
+
+	client, err := networkclientpool.Get(options.Options, &networkclientpool.Configuration{})
+	if err != nil {
+		return errors.Wrap(err, "could not get network client")
+	}
+	request.dialer = client
+
+	if len(request.Payloads) > 0 {
+		request.generator, err = generators.New(request.Payloads, request.AttackType.Value, request.options.TemplatePath, options.Catalog, options.Options.AttackType, options.Options)
+		if err != nil {
+			return errors.Wrap(err, "could not parse payloads")
+		}
+	}
+
+	if len(request.Matchers) > 0 || len(request.Extractors) > 0 {
+		compiled := &request.Operators
+		compiled.ExcludeMatchers = options.ExcludeMatchers
+		compiled.TemplateID = options.TemplateID
+		if err := compiled.Compile(); err != nil {
+			return errors.Wrap(err, "could not compile operators")
+		}
+		request.CompiledOperators = compiled
+	}
+	return nil
+}
+
+// openProtoFile opens a proto file relative to the template directory
+// respecting the local file access restrictions.
+func (request *Request) openProtoFile(filename string) (io.ReadCloser, error) {
+	if !filepath.IsAbs(filename) {
+		filename = filepath.Join(filepath.Dir(request.options.TemplatePath), filename)
+	}
+	return request.options.Options.LoadHelperFile(filename, request.options.TemplatePath, request.options.Catalog)
+}
+
+// Requests returns the total number of requests the rule will perform
+func (request *Request) Requests() int {
+	if request.generator != nil {
+		return request.generator.NewIterator().Total()
+	}
+	return 1
+}
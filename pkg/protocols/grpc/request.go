@@ -0,0 +1,229 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/http2"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/khulnasoft-lab/vulmap/pkg/output"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/expressions"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/generators"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/helpers/eventcreator"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/helpers/responsehighlighter"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/utils/vardump"
+	protocolutils "github.com/khulnasoft-lab/vulmap/pkg/protocols/utils"
+	templateTypes "github.com/khulnasoft-lab/vulmap/pkg/templates/types"
+)
+
+var _ protocols.Request = &Request{}
+
+const (
+	evaluateTemplateExpressionErrorMessage = "could not evaluate template expressions"
+	// defaultPort is the port of addresses without a port
+	defaultPort = "443"
+)
+
+// Type returns the type of the protocol request
+func (request *Request) Type() templateTypes.ProtocolType {
+	return templateTypes.GRPCProtocol
+}
+
+// ExecuteWithResults executes the protocol requests and returns results instead of writing them.
+func (request *Request) ExecuteWithResults(target *contextargs.Context, dynamicValues, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+	address, err := getAddress(target.MetaInput.Input)
+	if err != nil {
+		request.options.Output.Request(request.options.TemplatePath, target.MetaInput.Input, request.Type().String(), err)
+		request.options.Progress.IncrementFailedRequestsBy(1)
+		return errors.Wrap(err, "could not get address from url")
+	}
+	variables := protocolutils.GenerateVariables(address, false, nil)
+	// add template ctx variables to varMap
+	variables = generators.MergeMaps(variables, request.options.GetTemplateCtx(target.MetaInput).GetAll())
+	variablesMap := request.options.Variables.Evaluate(variables)
+	variables = generators.MergeMaps(variablesMap, variables, request.options.Constants, generators.BuildPayloadFromOptions(request.options.Options), dynamicValues)
+
+	if request.generator != nil {
+		iterator := request.generator.NewIterator()
+		for {
+			value, ok := iterator.Value()
+			if !ok {
+				break
+			}
+			if err := request.executeRequestWithPayloads(target, address, generators.MergeMaps(variables, value), previous, callback); err != nil {
+				gologger.Warning().Msgf("[%s] Could not make grpc request for %s: %s\n", request.options.TemplateID, address, err)
+			}
+		}
+		return nil
+	}
+	return request.executeRequestWithPayloads(target, address, variables, previous, callback)
+}
+
+// executeRequestWithPayloads calls the method of the request with the payload values
+func (request *Request) executeRequestWithPayloads(target *contextargs.Context, address string, payloadValues map[string]interface{}, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+	requestOptions := request.options
+	input := target.MetaInput.Input
+
+	if vardump.EnableVarDump {
+		gologger.Debug().Msgf("gRPC Protocol request variables: \n%s\n", vardump.DumpVariables(payloadValues))
+	}
+
+	fail := func(err error, message string) error {
+		requestOptions.Output.Request(requestOptions.TemplatePath, address, request.Type().String(), err)
+		requestOptions.Progress.IncrementFailedRequestsBy(1)
+		return errors.Wrap(err, message)
+	}
+
+	actualAddress, err := expressions.Evaluate(request.Address, payloadValues)
+	if err != nil {
+		return fail(err, evaluateTemplateExpressionErrorMessage)
+	}
+	// addresses without a port, like inputs of the default address, use port 443
+	if _, _, err := net.SplitHostPort(actualAddress); err != nil {
+		actualAddress = net.JoinHostPort(strings.Trim(actualAddress, "[]"), defaultPort)
+	}
+	hostname, _, err := net.SplitHostPort(actualAddress)
+	if err != nil {
+		return fail(err, "invalid address in grpc protocol request")
+	}
+	message, err := expressions.Evaluate(request.Message, payloadValues)
+	if err != nil {
+		return fail(err, evaluateTemplateExpressionErrorMessage)
+	}
+	md := metadata.MD{}
+	for key, value := range request.Metadata {
+		finalValue, err := expressions.Evaluate(value, payloadValues)
+		if err != nil {
+			return fail(err, evaluateTemplateExpressionErrorMessage)
+		}
+		md.Append(key, finalValue)
+	}
+	if err := expressions.ContainsUnresolvedVariables(message); err != nil {
+		gologger.Warning().Msgf("[%s] Could not make grpc request for %s: %v\n", requestOptions.TemplateID, actualAddress, err)
+		return nil
+	}
+
+	dialer := func(ctx context.Context, address string) (net.Conn, error) {
+		return request.dialer.Dial(ctx, "tcp", address)
+	}
+	if request.TLS {
+		config, err := request.tlsConfig(hostname)
+		if err != nil {
+			return fail(err, "could not create tls config")
+		}
+		dialer = func(ctx context.Context, address string) (net.Conn, error) {
+			return request.dialer.DialTLSWithConfig(ctx, "tcp", address, config)
+		}
+	}
+
+	release := requestOptions.HostConcurrency.Acquire(actualAddress)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(requestOptions.Options.Timeout)*time.Second)
+	defer cancel()
+
+	conn, err := dial(ctx, actualAddress, dialer)
+	if err != nil {
+		return fail(err, "could not connect to server")
+	}
+	defer conn.Close()
+
+	method, err := resolveMethod(ctx, conn, request.files, request.Method)
+	if err != nil {
+		return fail(err, "could not resolve method")
+	}
+	requestMessage, err := buildMessage(method, message)
+	if err != nil {
+		return fail(err, "could not build request message")
+	}
+	result, err := invoke(ctx, conn, method, requestMessage, md)
+	if err != nil {
+		return fail(err, "could not call method")
+	}
+	requestOptions.Progress.IncrementRequests()
+
+	methodName := method.GetService().GetFullyQualifiedName() + "/" + method.GetName()
+	requestBody, _ := requestMessage.MarshalJSON()
+	requestOutput := methodName + "\n" + metadataToString(md) + "\n" + string(requestBody)
+	responseOutput := result.response(method.IsServerStreaming())
+
+	if requestOptions.Options.Debug || requestOptions.Options.DebugRequests {
+		gologger.Debug().Str("address", actualAddress).Msgf("[%s] Dumped gRPC request for %s", requestOptions.TemplateID, actualAddress)
+		gologger.Print().Msgf("%s", requestOutput)
+	}
+	requestOptions.Output.Request(requestOptions.TemplatePath, actualAddress, request.Type().String(), nil)
+	gologger.Verbose().Msgf("Sent gRPC request to %s", actualAddress)
+
+	data := make(map[string]interface{})
+	data["type"] = request.Type().String()
+	data["host"] = input
+	data["matched"] = actualAddress
+	data["ip"] = request.dialer.GetDialedIP(hostname)
+	data["method"] = methodName
+	data["request"] = requestOutput
+	data["response"] = responseOutput
+	data["header"] = metadataToString(result.header)
+	data["trailer"] = metadataToString(result.trailer)
+	data["status_code"] = int(result.status.Code())
+	data["status"] = result.status.Code().String()
+	data["status_message"] = result.status.Message()
+
+	// add response fields to template context and merge templatectx variables to output event
+	requestOptions.AddTemplateVars(target.MetaInput, request.Type(), request.ID, data)
+	data = generators.MergeMaps(data, requestOptions.GetTemplateCtx(target.MetaInput).GetAll())
+	for k, v := range previous {
+		data[k] = v
+	}
+
+	event := eventcreator.CreateEventWithAdditionalOptions(request, data, requestOptions.Options.Debug || requestOptions.Options.DebugResponse, func(internalWrappedEvent *output.InternalWrappedEvent) {
+		internalWrappedEvent.OperatorsResult.PayloadValues = payloadValues
+	})
+	if requestOptions.Options.Debug || requestOptions.Options.DebugResponse {
+		gologger.Debug().Msgf("[%s] Dumped gRPC response for %s (%s)", requestOptions.TemplateID, actualAddress, result.status.Code())
+		gologger.Print().Msgf("%s", responsehighlighter.Highlight(event.OperatorsResult, responseOutput, requestOptions.Options.NoColor, false))
+	}
+
+	callback(event)
+	return nil
+}
+
+// tlsConfig returns the tls configuration of the connections built like
+// the one of the tls dials of the engine, negotiating http2 for grpc.
+func (request *Request) tlsConfig(hostname string) (*tls.Config, error) {
+	config := &tls.Config{
+		Renegotiation:      tls.RenegotiateOnceAsClient,
+		InsecureSkipVerify: true,
+		ServerName:         hostname,
+		MinVersion:         tls.VersionTLS10,
+		NextProtos:         []string{http2.NextProtoTLS},
+	}
+	if request.options.Options.SNI != "" {
+		config.ServerName = request.options.Options.SNI
+	}
+	config, err := protocolutils.AddConfiguredClientCertToRequest(config, request.options.Options)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create client certificate")
+	}
+	return config, nil
+}
+
+// getAddress returns the address of the host to make request to
+func getAddress(toTest string) (string, error) {
+	if strings.Contains(toTest, "://") {
+		parsed, err := url.Parse(toTest)
+		if err != nil {
+			return "", err
+		}
+		toTest = parsed.Host
+	}
+	return toTest, nil
+}
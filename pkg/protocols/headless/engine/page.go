@@ -11,6 +11,7 @@ import (
 
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/proto"
+	"github.com/khulnasoft-lab/vulmap/pkg/authprovider"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/utils"
 	"github.com/khulnasoft-lab/vulmap/pkg/types"
@@ -39,9 +40,10 @@ type HistoryData struct {
 
 // Options contains additional configuration options for the browser instance
 type Options struct {
-	Timeout     time.Duration
-	CookieReuse bool
-	Options     *types.Options
+	Timeout      time.Duration
+	CookieReuse  bool
+	Options      *types.Options
+	AuthProvider authprovider.AuthProvider
 }
 
 // Run runs a list of actions by creating a new page in the browser.
@@ -67,8 +69,9 @@ func (i *Instance) Run(input *contextargs.Context, actions []*Action, payloads m
 		payloads: payloads,
 	}
 
-	// in case the page has request/response modification rules - enable global hijacking
-	if createdPage.hasModificationRules() || containsModificationActions(actions...) {
+	// in case the page has request/response modification rules or requests
+	// need to be authenticated - enable global hijacking
+	if createdPage.hasModificationRules() || containsModificationActions(actions...) || options.AuthProvider != nil {
 		hijackRouter := page.HijackRequests()
 		if err := hijackRouter.Add("*", "", createdPage.routingRuleHandler); err != nil {
 			return nil, nil, err
@@ -37,6 +37,12 @@ func (p *Page) routingRuleHandler(ctx *rod.Hijack) {
 		}
 	}
 
+	if p.options.AuthProvider != nil {
+		for _, strategy := range p.options.AuthProvider.LookupURL(ctx.Request.URL()) {
+			strategy.Apply(ctx.Request.Req())
+		}
+	}
+
 	if p.options.CookieReuse {
 		// each http request is performed via the native go http client
 		// we first inject the shared cookies
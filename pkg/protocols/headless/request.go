@@ -130,16 +130,19 @@ func (request *Request) executeRequestWithPayloads(input *contextargs.Context, p
 		return errors.Wrap(err, errCouldGetHtmlElement)
 	}
 	options := &engine.Options{
-		Timeout:     time.Duration(request.options.Options.PageTimeout) * time.Second,
-		CookieReuse: request.CookieReuse,
-		Options:     request.options.Options,
+		Timeout:      time.Duration(request.options.Options.PageTimeout) * time.Second,
+		CookieReuse:  request.CookieReuse,
+		Options:      request.options.Options,
+		AuthProvider: request.options.AuthProvider,
 	}
 
 	if options.CookieReuse && input.CookieJar == nil {
 		return errors.New("cookie-reuse set but cookie-jar is nil")
 	}
 
+	release := request.options.HostConcurrency.Acquire(input.MetaInput.Input)
 	out, page, err := instance.Run(input, request.Steps, payloads, options)
+	release()
 	if err != nil {
 		request.options.Output.Request(request.options.TemplatePath, input.MetaInput.Input, request.Type().String(), err)
 		request.options.Progress.IncrementFailedRequestsBy(1)
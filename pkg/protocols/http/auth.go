@@ -0,0 +1,122 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/authprovider/authx"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// getAuthStrategy returns the ntlm or negotiate auth strategy of the template
+func (request *Request) getAuthStrategy() authx.AuthStrategy {
+	switch {
+	case request.NTLMAuthUsername != "":
+		return authx.NewNTLMAuthStrategy(&authx.Secret{
+			Type:     authx.NTLMAuth,
+			Username: request.NTLMAuthUsername,
+			Password: request.NTLMAuthPassword,
+		})
+	case request.NegotiateAuthUsername != "":
+		return authx.NewNegotiateAuthStrategy(&authx.Secret{
+			Type:     authx.NegotiateAuth,
+			Username: request.NegotiateAuthUsername,
+			Password: request.NegotiateAuthPassword,
+			KDC:      request.NegotiateAuthKDC,
+		})
+	}
+	return nil
+}
+
+// authStrategies returns the auth strategies of the generated request target.
+// The auth strategy of the template is applied last to take precedence.
+func (request *Request) authStrategies(req *generatedRequest) []authx.AuthStrategy {
+	var strategies []authx.AuthStrategy
+	if request.options.AuthProvider != nil {
+		if req.request != nil {
+			strategies = request.options.AuthProvider.LookupURL(req.request.Request.URL)
+		} else if parsed, err := url.Parse(req.URL()); err == nil && parsed.Host != "" {
+			strategies = request.options.AuthProvider.LookupURL(parsed)
+		}
+	}
+	if request.authStrategy != nil {
+		strategies = append(strategies, request.authStrategy)
+	}
+	return strategies
+}
+
+// applyAuth applies the auth strategies of the target to the generated request
+func (request *Request) applyAuth(req *generatedRequest) {
+	strategies := request.authStrategies(req)
+	if len(strategies) == 0 {
+		return
+	}
+	if req.request != nil {
+		for _, strategy := range strategies {
+			strategy.ApplyOnRR(req.request)
+		}
+		return
+	}
+	applyAuthOnRaw(req, strategies)
+}
+
+// applyAuthOnRaw applies the auth strategies to a raw request by applying
+// them to a temporary request and copying back the changed headers and query.
+func applyAuthOnRaw(req *generatedRequest, strategies []authx.AuthStrategy) {
+	tmp, err := http.NewRequest(req.rawRequest.Method, req.rawRequest.FullURL, nil)
+	if err != nil {
+		return
+	}
+	for key, value := range req.rawRequest.Headers {
+		tmp.Header.Set(key, value)
+	}
+	original := tmp.Header.Clone()
+	originalQuery := tmp.URL.RawQuery
+
+	for _, strategy := range strategies {
+		strategy.Apply(tmp)
+	}
+
+	for key, values := range tmp.Header {
+		value := strings.Join(values, ", ")
+		if key == "Cookie" {
+			value = strings.Join(values, "; ")
+		}
+		if original.Get(key) == value {
+			continue
+		}
+		for existing := range req.rawRequest.Headers {
+			if strings.EqualFold(existing, key) {
+				delete(req.rawRequest.Headers, existing)
+			}
+		}
+		req.rawRequest.Headers[key] = value
+	}
+	if tmp.URL.RawQuery != originalQuery {
+		base, _, _ := strings.Cut(req.rawRequest.FullURL, "?")
+		req.rawRequest.FullURL = base + "?" + tmp.URL.RawQuery
+		path, _, _ := strings.Cut(req.rawRequest.Path, "?")
+		req.rawRequest.Path = path + "?" + tmp.URL.RawQuery
+	}
+}
+
+// retryAuthChallenge retries the request once if a strategy of the target
+// consumed the challenge of the response, like a digest challenge of an
+// unauthorized response or a logged-out response of a dynamic secret.
+// The response is returned as is otherwise.
+func (request *Request) retryAuthChallenge(client *retryablehttp.Client, req *generatedRequest, resp *http.Response) (*http.Response, error) {
+	for _, strategy := range request.authStrategies(req) {
+		challenger, ok := strategy.(authx.ChallengeStrategy)
+		if !ok || !challenger.Challenge(resp) {
+			continue
+		}
+		_, _ = io.CopyN(io.Discard, resp.Body, drainReqSize)
+		resp.Body.Close()
+
+		strategy.ApplyOnRR(req.request)
+		return client.Do(req.request)
+	}
+	return resp, nil
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http/httputil"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/operators"
+	"github.com/khulnasoft-lab/vulmap/pkg/operators/matchers"
+	"github.com/khulnasoft-lab/vulmap/pkg/output"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/utils"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// hasDiffMatchers returns true if operators contain a diff matcher
+func hasDiffMatchers(op *operators.Operators) bool {
+	if op == nil {
+		return false
+	}
+	for _, matcher := range op.Matchers {
+		if matcher.GetType() == matchers.DiffMatcher {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchBaseline sends the unmodified base request of fuzzing and returns the
+// response fields prefixed with baseline_ for comparison by diff matchers.
+func (request *Request) fetchBaseline(baseRequest *retryablehttp.Request) (output.InternalEvent, error) {
+	req := baseRequest.Clone(context.Background())
+	request.setCustomHeaders(&generatedRequest{request: req})
+	request.options.HostRateLimiter.Take(req.URL.Host)
+	request.options.RateLimiter.Take()
+	release := request.options.HostConcurrency.Acquire(req.URL.Host)
+	defer release()
+
+	resp, err := request.httpClient.Do(req)
+	request.options.HostRateLimiter.Observe(req.URL.Host, resp, err)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not send baseline request")
+	}
+	defer resp.Body.Close()
+
+	var bodyReader io.Reader = resp.Body
+	if request.MaxSize != 0 {
+		bodyReader = io.LimitReader(resp.Body, int64(request.MaxSize))
+	} else if request.options.Options.ResponseReadSize != 0 {
+		bodyReader = io.LimitReader(resp.Body, int64(request.options.Options.ResponseReadSize))
+	}
+	body, err := io.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read baseline body")
+	}
+	headers, err := httputil.DumpResponse(resp, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not dump baseline headers")
+	}
+
+	return output.InternalEvent{
+		matchers.BaselinePrefix + "status_code":    resp.StatusCode,
+		matchers.BaselinePrefix + "body":           string(body),
+		matchers.BaselinePrefix + "all_headers":    string(headers),
+		matchers.BaselinePrefix + "header":         string(headers),
+		matchers.BaselinePrefix + "content_length": utils.CalculateContentLength(resp.ContentLength, int64(len(body))),
+	}, nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/khulnasoft-lab/gologger"
+	"github.com/khulnasoft-lab/vulmap/pkg/output"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/expressions"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/generators"
@@ -47,6 +48,10 @@ type generatedRequest struct {
 	dynamicValues        map[string]interface{}
 	interactshURLs       []string
 	customCancelFunction context.CancelFunc
+	// baseline is the baseline response of fuzzed requests
+	baseline output.InternalEvent
+	// raceResult is the response of the request sent with a race strategy
+	raceResult *race.Result
 }
 
 func (g *generatedRequest) URL() string {
@@ -305,7 +310,7 @@ func (r *requestGenerator) generateRawRequest(ctx context.Context, rawRequest st
 
 	// override the body with a new one that will be used to read the request body in parallel threads
 	// for race condition testing
-	if r.request.Threads > 0 && r.request.Race {
+	if r.request.Threads > 0 && r.request.Race && r.request.RaceStrategy == "" {
 		req.Body = race.NewOpenGateWithTimeout(req.Body, time.Duration(2)*time.Second)
 	}
 	for key, value := range rawRequestData.Headers {
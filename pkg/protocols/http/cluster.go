@@ -17,6 +17,7 @@ func (request *Request) CanCluster(other *Request) bool {
 	if request.Method != other.Method ||
 		request.MaxRedirects != other.MaxRedirects ||
 		request.CookieReuse != other.CookieReuse ||
+		request.Protocol != other.Protocol ||
 		request.Redirects != other.Redirects {
 		return false
 	}
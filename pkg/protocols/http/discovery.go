@@ -0,0 +1,57 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/khulnasoft-lab/vulmap/pkg/model/types/severity"
+	"github.com/khulnasoft-lab/vulmap/pkg/operators"
+	"github.com/khulnasoft-lab/vulmap/pkg/output"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/fuzz"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/helpers/eventcreator"
+)
+
+// discoveredParametersExtractor is the extractor name of discovered parameter findings
+const discoveredParametersExtractor = "discovered-parameters"
+
+// reportDiscoveredParameters sends the request with the hidden parameters
+// found by parameter discovery and reports them as an info finding.
+func (request *Request) reportDiscoveredParameters(input *contextargs.Context, gr fuzz.GeneratedRequest, callback protocols.OutputEventCallback) bool {
+	request.options.HostRateLimiter.Take(input.MetaInput.Input)
+	request.options.RateLimiter.Take()
+	release := request.options.HostConcurrency.Acquire(input.MetaInput.Input)
+	defer release()
+	req := &generatedRequest{
+		request:       gr.Request,
+		dynamicValues: gr.DynamicValues,
+		original:      request,
+		meta:          map[string]interface{}{"discovered_parameters": strings.Join(gr.DiscoveredParameters, ",")},
+	}
+
+	var reported bool
+	err := request.executeRequest(input, req, gr.DynamicValues, false, func(event *output.InternalWrappedEvent) {
+		// only report the final response of redirect chains once
+		if reported {
+			return
+		}
+		reported = true
+
+		info := request.options.TemplateInfo
+		info.SeverityHolder = severity.Holder{Severity: severity.Info}
+		event.InternalEvent["template-info"] = info
+		callback(eventcreator.CreateEventWithOperatorResults(request, event.InternalEvent, &operators.Result{
+			Matched:        true,
+			Extracted:      true,
+			Extracts:       map[string][]string{discoveredParametersExtractor: gr.DiscoveredParameters},
+			OutputExtracts: gr.DiscoveredParameters,
+			PayloadValues:  req.meta,
+		}))
+	}, 0)
+	if err != nil {
+		gologger.Verbose().Msgf("[%s] Could not report discovered parameters for %s: %s\n", request.options.TemplateID, input.MetaInput.Input, err)
+	}
+	request.options.Progress.IncrementRequests()
+	return true
+}
@@ -0,0 +1,522 @@
+package h2c
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+const (
+	// maxFrameSize is the maximum size of frames sent to the server
+	maxFrameSize = 16384
+	// defaultWindowSize is the initial flow control window of the server
+	defaultWindowSize = 65535
+	// windowSize is the flow control window granted to the server
+	windowSize = 1 << 30
+)
+
+var (
+	// errConnUnusable is returned when the request could not be sent
+	// because the connection was closed by the server.
+	errConnUnusable = errors.New("http2 connection is unusable")
+	// errStreamClosed is returned when the server closed the stream
+	// before the body of the request was sent.
+	errStreamClosed = errors.New("http2 stream was closed by the server")
+)
+
+// clientConn is a client HTTP/2 connection sending one request at a time.
+// Frames are read by a single goroutine dispatching them to their streams.
+type clientConn struct {
+	// reqMu is held while a request is sent and its response is awaited
+	reqMu sync.Mutex
+	// wmu is held while frames are written, it may be acquired with mu held
+	wmu     sync.Mutex
+	conn    net.Conn
+	writer  *bufio.Writer
+	framer  *http2.Framer
+	encoder *hpack.Encoder
+	headers *bytes.Buffer
+	// maxBodySize is the maximum size of buffered response bodies
+	maxBodySize int64
+
+	// mu guards the state updated by the frames read from the server
+	mu      sync.Mutex
+	streams map[uint32]*clientStream
+
+	nextStreamID uint32
+	// lastStreamID is the last stream processed by the server after a goaway
+	lastStreamID uint32
+	goAway       bool
+	closed       atomic.Bool
+	// done is closed once the connection can't read frames anymore
+	done chan struct{}
+	// windowUpdated is signalled when the server grants flow control window
+	windowUpdated chan struct{}
+
+	frameMax     uint32
+	streamWindow int64
+	connWindow   int64
+}
+
+// clientStream is a stream of a request awaiting its response
+type clientStream struct {
+	id       uint32
+	window   int64
+	received bool
+	resp     *http.Response
+	body     bytes.Buffer
+	err      error
+	// done is closed when the response was read or the stream failed
+	done chan struct{}
+}
+
+// newClientConn creates a new client connection reading frames from the reader,
+// response bodies larger than the max body size are truncated if it is set.
+func newClientConn(conn net.Conn, reader io.Reader, maxBodySize int64) *clientConn {
+	cc := &clientConn{
+		conn:          conn,
+		writer:        bufio.NewWriter(conn),
+		headers:       &bytes.Buffer{},
+		maxBodySize:   maxBodySize,
+		streams:       make(map[uint32]*clientStream),
+		nextStreamID:  1,
+		done:          make(chan struct{}),
+		windowUpdated: make(chan struct{}, 1),
+		frameMax:      maxFrameSize,
+		streamWindow:  defaultWindowSize,
+		connWindow:    defaultWindowSize,
+	}
+	cc.framer = http2.NewFramer(cc.writer, reader)
+	cc.framer.ReadMetaHeaders = hpack.NewDecoder(4096, nil)
+	cc.encoder = hpack.NewEncoder(cc.headers)
+	return cc
+}
+
+// writePreface writes the connection preface and the settings of the client
+func (cc *clientConn) writePreface() error {
+	if _, err := cc.writer.WriteString(http2.ClientPreface); err != nil {
+		return errors.Wrap(err, "could not write http2 preface")
+	}
+	if err := cc.framer.WriteSettings(clientSettings...); err != nil {
+		return errors.Wrap(err, "could not write http2 settings")
+	}
+	if err := cc.framer.WriteWindowUpdate(0, windowSize-defaultWindowSize); err != nil {
+		return errors.Wrap(err, "could not write http2 window update")
+	}
+	return errors.Wrap(cc.writer.Flush(), "could not write http2 preface")
+}
+
+// start starts reading the frames of the connection
+func (cc *clientConn) start() {
+	go cc.readLoop()
+}
+
+// upgradeResponse reads the response of the upgrade request which
+// is sent by the server on the first stream.
+func (cc *clientConn) upgradeResponse(req *http.Request) (*http.Response, error) {
+	cc.reqMu.Lock()
+	defer cc.reqMu.Unlock()
+
+	cc.mu.Lock()
+	cs := &clientStream{id: 1, done: make(chan struct{})}
+	cc.streams[cs.id] = cs
+	cc.nextStreamID = 3
+	cc.mu.Unlock()
+
+	cc.start()
+	return cc.awaitResponse(req, cs)
+}
+
+// roundTrip sends the request on a new stream and reads its response
+func (cc *clientConn) roundTrip(req *http.Request, body []byte) (*http.Response, error) {
+	cc.reqMu.Lock()
+	defer cc.reqMu.Unlock()
+
+	cs, err := cc.newStream()
+	if err != nil {
+		return nil, err
+	}
+	if err := cc.writeRequest(req.Context(), cs, req, body); err != nil {
+		select {
+		case <-cs.done:
+			// the server responded before the whole body was sent
+			if cs.err == nil {
+				_ = cc.writeFrame(func() error { return cc.framer.WriteRSTStream(cs.id, http2.ErrCodeCancel) })
+			}
+			return cc.response(req, cs)
+		default:
+		}
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			cc.cancelStream(cs)
+			return nil, ctxErr
+		}
+		cc.close()
+		return nil, errors.Wrap(errConnUnusable, err.Error())
+	}
+	return cc.awaitResponse(req, cs)
+}
+
+// newStream opens a new stream for a request
+func (cc *clientConn) newStream() (*clientStream, error) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if cc.closed.Load() || cc.goAway {
+		return nil, errConnUnusable
+	}
+	cs := &clientStream{id: cc.nextStreamID, window: cc.streamWindow, done: make(chan struct{})}
+	cc.nextStreamID += 2
+	cc.streams[cs.id] = cs
+	return cs, nil
+}
+
+// awaitResponse waits for the response of the stream, a cancelled request
+// only resets its stream so that the connection stays usable.
+func (cc *clientConn) awaitResponse(req *http.Request, cs *clientStream) (*http.Response, error) {
+	select {
+	case <-cs.done:
+		return cc.response(req, cs)
+	case <-req.Context().Done():
+		cc.cancelStream(cs)
+		return nil, req.Context().Err()
+	}
+}
+
+// response returns the response of a completed stream
+func (cc *clientConn) response(req *http.Request, cs *clientStream) (*http.Response, error) {
+	if cs.err != nil {
+		return nil, cs.err
+	}
+	cs.resp.Request = req
+	return cs.resp, nil
+}
+
+// cancelStream resets the stream if it is still awaiting its response
+func (cc *clientConn) cancelStream(cs *clientStream) {
+	cc.mu.Lock()
+	_, ok := cc.streams[cs.id]
+	delete(cc.streams, cs.id)
+	cc.mu.Unlock()
+
+	if ok {
+		_ = cc.writeFrame(func() error { return cc.framer.WriteRSTStream(cs.id, http2.ErrCodeCancel) })
+	}
+}
+
+// writeRequest writes the headers and the body of the request on the stream
+func (cc *clientConn) writeRequest(ctx context.Context, cs *clientStream, req *http.Request, body []byte) error {
+	block := cc.encodeHeaders(req, len(body))
+	cc.mu.Lock()
+	frameMax := int(cc.frameMax)
+	cc.mu.Unlock()
+
+	err := cc.writeFrame(func() error {
+		for first := true; first || len(block) > 0; first = false {
+			fragment := block[:min(len(block), frameMax)]
+			block = block[len(fragment):]
+			var err error
+			if first {
+				err = cc.framer.WriteHeaders(http2.HeadersFrameParam{StreamID: cs.id, BlockFragment: fragment, EndStream: len(body) == 0, EndHeaders: len(block) == 0})
+			} else {
+				err = cc.framer.WriteContinuation(cs.id, len(block) == 0, fragment)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for len(body) > 0 {
+		size, err := cc.takeWindow(ctx, cs, len(body))
+		if err != nil {
+			return err
+		}
+		chunk := body[:size]
+		body = body[size:]
+		if err := cc.writeFrame(func() error { return cc.framer.WriteData(cs.id, len(body) == 0, chunk) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// takeWindow waits for the server to grant flow control window and takes
+// up to size bytes of the windows of the stream and of the connection.
+func (cc *clientConn) takeWindow(ctx context.Context, cs *clientStream, size int) (int, error) {
+	for {
+		select {
+		case <-cs.done:
+			return 0, errStreamClosed
+		default:
+		}
+
+		cc.mu.Lock()
+		available := min(int64(size), cs.window, cc.connWindow, int64(cc.frameMax))
+		if available > 0 {
+			cs.window -= available
+			cc.connWindow -= available
+			cc.mu.Unlock()
+			return int(available), nil
+		}
+		cc.mu.Unlock()
+
+		select {
+		case <-cc.windowUpdated:
+		case <-cs.done:
+			return 0, errStreamClosed
+		case <-cc.done:
+			return 0, errConnUnusable
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+}
+
+// signalWindow wakes up the request waiting for flow control window
+func (cc *clientConn) signalWindow() {
+	select {
+	case cc.windowUpdated <- struct{}{}:
+	default:
+	}
+}
+
+// writeFrame writes frames with the write function and flushes them
+func (cc *clientConn) writeFrame(write func() error) error {
+	cc.wmu.Lock()
+	defer cc.wmu.Unlock()
+
+	if err := write(); err != nil {
+		return err
+	}
+	return cc.writer.Flush()
+}
+
+// encodeHeaders returns the hpack encoded header block of the request
+func (cc *clientConn) encodeHeaders(req *http.Request, contentLength int) []byte {
+	cc.headers.Reset()
+	authority := req.Host
+	if authority == "" {
+		authority = req.URL.Host
+	}
+	_ = cc.encoder.WriteField(hpack.HeaderField{Name: ":method", Value: req.Method})
+	_ = cc.encoder.WriteField(hpack.HeaderField{Name: ":scheme", Value: req.URL.Scheme})
+	_ = cc.encoder.WriteField(hpack.HeaderField{Name: ":authority", Value: authority})
+	_ = cc.encoder.WriteField(hpack.HeaderField{Name: ":path", Value: req.URL.RequestURI()})
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		switch name {
+		case "host", "connection", "keep-alive", "proxy-connection", "transfer-encoding", "upgrade", "http2-settings", "content-length":
+			continue
+		}
+		for _, value := range values {
+			_ = cc.encoder.WriteField(hpack.HeaderField{Name: name, Value: value})
+		}
+	}
+	if contentLength > 0 {
+		_ = cc.encoder.WriteField(hpack.HeaderField{Name: "content-length", Value: strconv.Itoa(contentLength)})
+	}
+	return append([]byte(nil), cc.headers.Bytes()...)
+}
+
+// readLoop reads the frames of the connection until it is closed
+// and fails the streams still awaiting their response.
+func (cc *clientConn) readLoop() {
+	var err error
+	for err == nil {
+		var frame http2.Frame
+		if frame, err = cc.framer.ReadFrame(); err == nil {
+			err = cc.handleFrame(frame)
+		}
+	}
+	cc.close()
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	for _, cs := range cc.streams {
+		// connections closed by the server before it responded
+		// are reported as unusable so that the request is retried
+		if !cs.received && errors.Is(err, io.EOF) {
+			cc.finish(cs, errors.Wrap(errConnUnusable, err.Error()))
+		} else {
+			cc.finish(cs, errors.Wrap(err, "could not read http2 response"))
+		}
+	}
+	close(cc.done)
+}
+
+// handleFrame handles a frame read from the server
+func (cc *clientConn) handleFrame(frame http2.Frame) error {
+	if err := cc.handleConnFrame(frame); err != nil {
+		return errors.Wrap(err, "could not write http2 frame")
+	}
+	if err := cc.handleStreamFrame(frame); err != nil {
+		return errors.Wrap(err, "could not write http2 frame")
+	}
+	return nil
+}
+
+// handleStreamFrame adds a frame to the response of its stream, frames
+// of streams which were cancelled or already responded are discarded.
+func (cc *clientConn) handleStreamFrame(frame http2.Frame) error {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	cs, ok := cc.streams[frame.Header().StreamID]
+	if !ok {
+		return nil
+	}
+	if update, ok := frame.(*http2.WindowUpdateFrame); ok {
+		cs.window += int64(update.Increment)
+		cc.signalWindow()
+		return nil
+	}
+	cs.received = true
+
+	ended := frame.Header().Flags.Has(http2.FlagDataEndStream)
+	switch frame := frame.(type) {
+	case *http2.MetaHeadersFrame:
+		status, err := strconv.Atoi(frame.PseudoValue("status"))
+		if err != nil {
+			cc.finish(cs, errors.Wrap(err, "could not parse http2 status"))
+			return cc.resetStream(cs.id, ended)
+		}
+		// informational responses precede the final response
+		if status >= 100 && status < 200 {
+			return nil
+		}
+		if cs.resp == nil {
+			cs.resp = &http.Response{
+				Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+				StatusCode: status,
+				Proto:      "HTTP/2.0",
+				ProtoMajor: 2,
+				Header:     http.Header{},
+			}
+		}
+		for _, field := range frame.RegularFields() {
+			cs.resp.Header.Add(http.CanonicalHeaderKey(field.Name), field.Value)
+		}
+	case *http2.DataFrame:
+		data := frame.Data()
+		if cc.maxBodySize > 0 && int64(cs.body.Len()+len(data)) > cc.maxBodySize {
+			// bodies are truncated at the maximum response size
+			// and the server is asked to stop sending the rest
+			cs.body.Write(data[:cc.maxBodySize-int64(cs.body.Len())])
+			cc.finish(cs, nil)
+			return cc.resetStream(cs.id, ended)
+		}
+		cs.body.Write(data)
+		if length := uint32(len(data)); length > 0 && !ended {
+			return cc.writeFrame(func() error { return cc.framer.WriteWindowUpdate(cs.id, length) })
+		}
+	case *http2.RSTStreamFrame:
+		cc.finish(cs, errors.Errorf("server reset stream with %s", frame.ErrCode))
+		return nil
+	}
+	if ended {
+		cc.finish(cs, nil)
+	}
+	return nil
+}
+
+// resetStream resets a stream whose response is not needed anymore
+// unless the server already ended it.
+func (cc *clientConn) resetStream(id uint32, ended bool) error {
+	if ended {
+		return nil
+	}
+	return cc.writeFrame(func() error { return cc.framer.WriteRSTStream(id, http2.ErrCodeCancel) })
+}
+
+// finish completes the stream with its response or the error,
+// it must be called with mu held.
+func (cc *clientConn) finish(cs *clientStream, err error) {
+	delete(cc.streams, cs.id)
+	if err == nil && cs.resp == nil {
+		err = errors.New("server sent no http2 response headers")
+	}
+	if err == nil {
+		cs.resp.Body = io.NopCloser(bytes.NewReader(cs.body.Bytes()))
+		cs.resp.ContentLength = int64(cs.body.Len())
+	}
+	cs.err = err
+	close(cs.done)
+}
+
+// handleConnFrame handles the connection level frames
+func (cc *clientConn) handleConnFrame(frame http2.Frame) error {
+	switch frame := frame.(type) {
+	case *http2.SettingsFrame:
+		if frame.IsAck() {
+			return nil
+		}
+		cc.mu.Lock()
+		_ = frame.ForeachSetting(func(setting http2.Setting) error {
+			switch setting.ID {
+			case http2.SettingInitialWindowSize:
+				// the change of the initial window applies to open streams
+				for _, cs := range cc.streams {
+					cs.window += int64(setting.Val) - cc.streamWindow
+				}
+				cc.streamWindow = int64(setting.Val)
+			case http2.SettingMaxFrameSize:
+				cc.frameMax = min(setting.Val, maxFrameSize)
+			}
+			return nil
+		})
+		cc.mu.Unlock()
+		cc.signalWindow()
+		return cc.writeFrame(cc.framer.WriteSettingsAck)
+	case *http2.PingFrame:
+		if frame.IsAck() {
+			return nil
+		}
+		return cc.writeFrame(func() error { return cc.framer.WritePing(true, frame.Data) })
+	case *http2.WindowUpdateFrame:
+		if frame.StreamID == 0 {
+			cc.mu.Lock()
+			cc.connWindow += int64(frame.Increment)
+			cc.mu.Unlock()
+			cc.signalWindow()
+		}
+	case *http2.DataFrame:
+		// data frames of all streams count against the connection window
+		if length := uint32(len(frame.Data())); length > 0 {
+			return cc.writeFrame(func() error { return cc.framer.WriteWindowUpdate(0, length) })
+		}
+	case *http2.GoAwayFrame:
+		cc.mu.Lock()
+		cc.goAway = true
+		cc.lastStreamID = frame.LastStreamID
+		for id, cs := range cc.streams {
+			if id > cc.lastStreamID {
+				cc.finish(cs, errors.Wrap(errConnUnusable, "server closed connection before processing request"))
+			}
+		}
+		cc.mu.Unlock()
+	}
+	return nil
+}
+
+// close closes the connection, it can be called while a request is sent
+func (cc *clientConn) close() {
+	if cc.closed.CompareAndSwap(false, true) {
+		_ = cc.conn.Close()
+	}
+}
@@ -0,0 +1,245 @@
+// Package h2c implements a cleartext HTTP/2 transport connecting with an
+// HTTP/1.1 upgrade or with prior knowledge. Requests to a host are sent as
+// streams of the same connection so that requests following an upgrade
+// are sent over the upgraded connection, as used to test h2c smuggling.
+package h2c
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/http2"
+)
+
+// DialFunc dials a connection to the address
+type DialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// Transport is a cleartext HTTP/2 round tripper
+type Transport struct {
+	// Dial dials the connections to the hosts
+	Dial DialFunc
+	// TLSClientConfig is the tls configuration of https hosts
+	TLSClientConfig *tls.Config
+	// PriorKnowledge sends the requests over HTTP/2 without the HTTP/1.1
+	// upgrade, otherwise the first request to a host upgrades the connection.
+	PriorKnowledge bool
+	// MaxResponseBodySize is the size at which response bodies are
+	// truncated, they are buffered whole if it is zero.
+	MaxResponseBodySize int64
+
+	mu    sync.Mutex
+	conns map[string]*clientConn
+}
+
+// RoundTrip sends the request as a stream of the connection of its host
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	key := req.URL.Scheme + "://" + hostPort(req.URL.Scheme, req.URL.Host)
+
+	if cc := t.conn(key); cc != nil {
+		resp, err := cc.roundTrip(req, body)
+		if err == nil || !errors.Is(err, errConnUnusable) {
+			return resp, err
+		}
+		// the connection was closed by the server before the
+		// request was sent so that it can be sent again
+		t.remove(key, cc)
+	}
+
+	conn, err := t.dial(req)
+	if err != nil {
+		return nil, err
+	}
+	if t.PriorKnowledge {
+		cc := newClientConn(conn, bufio.NewReader(conn), t.MaxResponseBodySize)
+		if err := cc.writePreface(); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		cc.start()
+		t.add(key, cc)
+		return cc.roundTrip(req, body)
+	}
+	return t.upgrade(key, conn, req, body)
+}
+
+// upgrade sends the request over HTTP/1.1 asking to upgrade the connection to
+// h2c. The response is read from the first stream of the upgraded connection
+// or as HTTP/1.1 response if the server does not switch protocols.
+func (t *Transport) upgrade(key string, conn net.Conn, req *http.Request, body []byte) (*http.Response, error) {
+	upgradeReq := req.Clone(req.Context())
+	upgradeReq.Header.Set("Upgrade", "h2c")
+	upgradeReq.Header.Set("HTTP2-Settings", encodeSettings(clientSettings))
+	upgradeReq.Header.Set("Connection", "Upgrade, HTTP2-Settings")
+	upgradeReq.ContentLength = int64(len(body))
+	upgradeReq.Body = nil
+	if len(body) > 0 {
+		upgradeReq.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	// the connection is only closed on cancellation until it is upgraded,
+	// afterwards a cancelled request only resets its stream.
+	stop := context.AfterFunc(req.Context(), func() { _ = conn.Close() })
+	defer stop()
+
+	if err := upgradeReq.Write(conn); err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "could not write upgrade request")
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "could not read upgrade response")
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || !strings.EqualFold(resp.Header.Get("Upgrade"), "h2c") {
+		defer conn.Close()
+		defer resp.Body.Close()
+		var bodyReader io.Reader = resp.Body
+		if t.MaxResponseBodySize > 0 {
+			bodyReader = io.LimitReader(resp.Body, t.MaxResponseBodySize)
+		}
+		data, err := io.ReadAll(bodyReader)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read http body")
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		resp.ContentLength = int64(len(data))
+		return resp, nil
+	}
+
+	if !stop() {
+		return nil, errors.Wrap(req.Context().Err(), "could not upgrade connection")
+	}
+	cc := newClientConn(conn, reader, t.MaxResponseBodySize)
+	if err := cc.writePreface(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	t.add(key, cc)
+	return cc.upgradeResponse(req)
+}
+
+// CloseIdleConnections closes the connections of the transport
+func (t *Transport) CloseIdleConnections() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key, cc := range t.conns {
+		cc.close()
+		delete(t.conns, key)
+	}
+}
+
+// dial dials a connection to the host of the request
+func (t *Transport) dial(req *http.Request) (net.Conn, error) {
+	conn, err := t.Dial(req.Context(), "tcp", hostPort(req.URL.Scheme, req.URL.Host))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not connect to server")
+	}
+	if req.URL.Scheme != "https" {
+		return conn, nil
+	}
+	var config *tls.Config
+	if t.TLSClientConfig != nil {
+		config = t.TLSClientConfig.Clone()
+	} else {
+		config = &tls.Config{InsecureSkipVerify: true}
+	}
+	if config.ServerName == "" {
+		config.ServerName = req.URL.Hostname()
+	}
+	config.NextProtos = []string{"http/1.1"}
+	if t.PriorKnowledge {
+		config.NextProtos = []string{http2.NextProtoTLS}
+	}
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.HandshakeContext(req.Context()); err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "could not complete tls handshake")
+	}
+	return tlsConn, nil
+}
+
+// conn returns the open connection of the key
+func (t *Transport) conn(key string) *clientConn {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.conns[key]
+}
+
+// add stores the connection of the key
+func (t *Transport) add(key string, cc *clientConn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.conns == nil {
+		t.conns = make(map[string]*clientConn)
+	}
+	if previous, ok := t.conns[key]; ok {
+		previous.close()
+	}
+	t.conns[key] = cc
+}
+
+// remove removes the connection of the key
+func (t *Transport) remove(key string, cc *clientConn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.conns[key] == cc {
+		delete(t.conns, key)
+	}
+	cc.close()
+}
+
+// clientSettings are the settings sent to the server
+var clientSettings = []http2.Setting{
+	{ID: http2.SettingEnablePush, Val: 0},
+	{ID: http2.SettingInitialWindowSize, Val: windowSize},
+}
+
+// encodeSettings returns the HTTP2-Settings header value of the settings
+func encodeSettings(settings []http2.Setting) string {
+	payload := make([]byte, 0, 6*len(settings))
+	for _, setting := range settings {
+		payload = binary.BigEndian.AppendUint16(payload, uint16(setting.ID))
+		payload = binary.BigEndian.AppendUint32(payload, setting.Val)
+	}
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// hostPort returns the host:port of the host of an url with the scheme
+func hostPort(scheme, host string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	port := "80"
+	if scheme == "https" {
+		port = "443"
+	}
+	return net.JoinHostPort(strings.Trim(host, "[]"), port)
+}
+
+// readBody reads the body of the request to send it in frames
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read request body")
+	}
+	return body, nil
+}
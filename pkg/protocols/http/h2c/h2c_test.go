@@ -0,0 +1,180 @@
+package h2c
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// newServer starts a server counting its connections which
+// responds with the protocol, the path and the body of requests.
+func newServer(t *testing.T, handler func(http.Handler) http.Handler) (*httptest.Server, *atomic.Int32) {
+	var conns atomic.Int32
+	ts := httptest.NewUnstartedServer(handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Test", "true")
+		_, _ = w.Write([]byte(r.Proto + " " + r.URL.Path + " " + string(body)))
+	})))
+	ts.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	ts.Start()
+	t.Cleanup(ts.Close)
+	return ts, &conns
+}
+
+func h2cHandler(handler http.Handler) http.Handler {
+	return h2c.NewHandler(handler, &http2.Server{})
+}
+
+func send(t *testing.T, client *http.Client, method, url, body string) (*http.Response, string) {
+	req, err := http.NewRequestWithContext(context.Background(), method, url, strings.NewReader(body))
+	require.Nil(t, err, "could not create request")
+	resp, err := client.Do(req)
+	require.Nil(t, err, "could not send request")
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	require.Nil(t, err, "could not read response body")
+	return resp, string(data)
+}
+
+func TestTransportUpgrade(t *testing.T) {
+	ts, conns := newServer(t, h2cHandler)
+	dialer := &net.Dialer{}
+	client := &http.Client{Transport: &Transport{Dial: dialer.DialContext}}
+
+	resp, body := send(t, client, http.MethodGet, ts.URL+"/upgrade", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "HTTP/2.0", resp.Proto, "could not read response of upgrade request over http2")
+	// the server handles the upgrade request as received over http/1.1
+	require.Equal(t, "HTTP/1.1 /upgrade ", body)
+	require.Equal(t, "true", resp.Header.Get("X-Test"))
+
+	// subsequent requests are sent over the upgraded connection
+	resp, body = send(t, client, http.MethodPost, ts.URL+"/smuggled", "data")
+	require.Equal(t, "HTTP/2.0", resp.Proto)
+	require.Equal(t, "HTTP/2.0 /smuggled data", body)
+	require.Equal(t, int32(1), conns.Load(), "could not send requests over upgraded connection")
+
+	// closed connections are dialed and upgraded again
+	client.CloseIdleConnections()
+	_, body = send(t, client, http.MethodGet, ts.URL+"/again", "")
+	require.Equal(t, "HTTP/1.1 /again ", body)
+	require.Equal(t, int32(2), conns.Load())
+}
+
+func TestTransportUpgradeRefused(t *testing.T) {
+	ts, _ := newServer(t, func(handler http.Handler) http.Handler { return handler })
+	dialer := &net.Dialer{}
+	client := &http.Client{Transport: &Transport{Dial: dialer.DialContext}}
+
+	resp, body := send(t, client, http.MethodPost, ts.URL+"/refused", "data")
+	require.Equal(t, "HTTP/1.1", resp.Proto, "could not read http/1.1 response of refused upgrade")
+	require.Equal(t, "HTTP/1.1 /refused data", body)
+}
+
+func TestTransportPriorKnowledge(t *testing.T) {
+	ts, conns := newServer(t, h2cHandler)
+	dialer := &net.Dialer{}
+	client := &http.Client{Transport: &Transport{Dial: dialer.DialContext, PriorKnowledge: true}}
+
+	for _, path := range []string{"/first", "/second"} {
+		resp, body := send(t, client, http.MethodPost, ts.URL+path, strings.Repeat("a", 100000))
+		require.Equal(t, "HTTP/2.0", resp.Proto)
+		require.Equal(t, "HTTP/2.0 "+path+" "+strings.Repeat("a", 100000), body, "could not send request with prior knowledge")
+	}
+	require.Equal(t, int32(1), conns.Load(), "could not reuse http2 connection")
+}
+
+// h2cPathsHandler serves the paths with their handlers over h2c
+// and the other paths with the default handler of the server.
+func h2cPathsHandler(paths map[string]http.HandlerFunc) func(http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return h2cHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if pathHandler, ok := paths[r.URL.Path]; ok {
+				pathHandler(w, r)
+				return
+			}
+			handler.ServeHTTP(w, r)
+		}))
+	}
+}
+
+func TestTransportCancel(t *testing.T) {
+	cancelled := make(chan struct{})
+	ts, conns := newServer(t, h2cPathsHandler(map[string]http.HandlerFunc{
+		"/slow": func(_ http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+			close(cancelled)
+		},
+	}))
+	dialer := &net.Dialer{}
+	client := &http.Client{Transport: &Transport{Dial: dialer.DialContext, PriorKnowledge: true}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/slow", nil)
+	require.Nil(t, err, "could not create request")
+	_, err = client.Do(req)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "could not reset stream of cancelled request")
+	}
+
+	// the cancelled request does not close the shared connection
+	_, body := send(t, client, http.MethodGet, ts.URL+"/after", "")
+	require.Equal(t, "HTTP/2.0 /after ", body)
+	require.Equal(t, int32(1), conns.Load(), "could not reuse connection after cancelled request")
+}
+
+func TestTransportMaxResponseBodySize(t *testing.T) {
+	ts, conns := newServer(t, h2cPathsHandler(map[string]http.HandlerFunc{
+		"/large": func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(strings.Repeat("a", 1000000)))
+		},
+	}))
+	dialer := &net.Dialer{}
+	client := &http.Client{Transport: &Transport{Dial: dialer.DialContext, PriorKnowledge: true, MaxResponseBodySize: 1000}}
+
+	resp, body := send(t, client, http.MethodGet, ts.URL+"/large", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, strings.Repeat("a", 1000), body, "could not truncate response body")
+
+	_, body = send(t, client, http.MethodGet, ts.URL+"/after", "")
+	require.Equal(t, "HTTP/2.0 /after ", body)
+	require.Equal(t, int32(1), conns.Load(), "could not reuse connection after truncated response")
+}
+
+func TestTransportEarlyResponse(t *testing.T) {
+	ts, _ := newServer(t, h2cPathsHandler(map[string]http.HandlerFunc{
+		"/early": func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		},
+	}))
+	dialer := &net.Dialer{}
+	client := &http.Client{Transport: &Transport{Dial: dialer.DialContext, PriorKnowledge: true}}
+
+	// the body exceeds the flow control window granted by the server
+	// which responds before reading it.
+	resp, _ := send(t, client, http.MethodPost, ts.URL+"/early", strings.Repeat("a", 4<<20))
+	require.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode, "could not read response sent before request body")
+
+	_, body := send(t, client, http.MethodGet, ts.URL+"/after", "")
+	require.Equal(t, "HTTP/2.0 /after ", body)
+}
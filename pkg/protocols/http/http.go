@@ -8,6 +8,7 @@ import (
 	json "github.com/json-iterator/go"
 	"github.com/pkg/errors"
 
+	"github.com/khulnasoft-lab/vulmap/pkg/authprovider/authx"
 	"github.com/khulnasoft-lab/vulmap/pkg/operators"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/expressions"
@@ -132,6 +133,7 @@ type Request struct {
 	generator         *generators.PayloadGenerator // optional, only enabled when using payloads
 	httpClient        *retryablehttp.Client
 	rawhttpClient     *rawhttp.Client
+	authStrategy      authx.AuthStrategy // ntlm or negotiate auth of the template
 
 	// description: |
 	//   SelfContained specifies if the request is self-contained.
@@ -141,7 +143,10 @@ type Request struct {
 	//   Signature is the request signature method
 	// values:
 	//   - "AWS"
-	Signature SignatureTypeHolder `yaml:"signature,omitempty" json:"signature,omitempty" jsonschema:"title=signature is the http request signature method,description=Signature is the HTTP Request signature Method,enum=AWS"`
+	//   - "AZURE-SHARED-KEY"
+	//   - "GCP-HMAC"
+	//   - "HMAC"
+	Signature SignatureTypeHolder `yaml:"signature,omitempty" json:"signature,omitempty" jsonschema:"title=signature is the http request signature method,description=Signature is the HTTP Request signature Method,enum=AWS,enum=AZURE-SHARED-KEY,enum=GCP-HMAC,enum=HMAC"`
 
 	// description: |
 	//   CookieReuse is an optional setting that enables cookie reuse for
@@ -162,6 +167,20 @@ type Request struct {
 	//   This can be used in conjunction with `max-redirects` to control the HTTP request redirects.
 	HostRedirects bool `yaml:"host-redirects,omitempty" json:"host-redirects,omitempty" jsonschema:"title=follow same host http redirects,description=Specifies whether redirects to the same host should be followed by the HTTP Client"`
 	// description: |
+	//   Protocol is the HTTP protocol used to send the requests.
+	//
+	//   h3 sends the requests over HTTP/3 (QUIC), alt-svc sends the requests over HTTP/3
+	//   to the hosts advertising it with the Alt-Svc header. h2c upgrades the connection to
+	//   cleartext HTTP/2 with the first request and sends the next requests over the upgraded
+	//   connection, h2c-prior-knowledge sends the requests over cleartext HTTP/2 without upgrade.
+	//   HTTP/1.1 or HTTP/2 is used by default.
+	// values:
+	//   - "h3"
+	//   - "alt-svc"
+	//   - "h2c"
+	//   - "h2c-prior-knowledge"
+	Protocol string `yaml:"protocol,omitempty" json:"protocol,omitempty" jsonschema:"title=http protocol of the requests,description=HTTP protocol used to send the requests,enum=h3,enum=alt-svc,enum=h2c,enum=h2c-prior-knowledge"`
+	// description: |
 	//   Pipeline defines if the attack should be performed with HTTP 1.1 Pipelining
 	//
 	//   All requests must be idempotent (GET/POST). This can be used for race conditions/billions requests.
@@ -178,6 +197,17 @@ type Request struct {
 	//   The actual number of requests that will be sent is determined by the `race_count`  field.
 	Race bool `yaml:"race,omitempty" json:"race,omitempty" jsonschema:"title=perform race-http request coordination attack,description=Race determines if all the request have to be attempted at the same time (Race Condition)"`
 	// description: |
+	//   RaceStrategy is the strategy used to synchronise the race condition requests.
+	//
+	//   single-packet holds back the final DATA frame of the requests sent over a single HTTP/2
+	//   connection and flushes them together, falling back to last-byte for hosts not supporting HTTP/2.
+	//   last-byte holds back the last byte of the requests sent over their own HTTP/1.1 connections
+	//   and sends them together.
+	// values:
+	//   - "single-packet"
+	//   - "last-byte"
+	RaceStrategy string `yaml:"race-strategy,omitempty" json:"race-strategy,omitempty" jsonschema:"title=race condition synchronisation strategy,description=Strategy used to synchronise the race condition requests,enum=single-packet,enum=last-byte"`
+	// description: |
 	//   ReqCondition automatically assigns numbers to requests and preserves their history.
 	//
 	//   This allows matching on them later for multi-request conditions.
@@ -202,6 +232,25 @@ type Request struct {
 	// description: |
 	//  DisablePathAutomerge disables merging target url path with raw request path
 	DisablePathAutomerge bool `yaml:"disable-path-automerge,omitempty" json:"disable-path-automerge,omitempty" jsonschema:"title=disable auto merging of path,description=Disable merging target url path with raw request path"`
+	// description: |
+	//   NTLMAuthUsername specifies the username for NTLM authentication.
+	//
+	//   The domain can be specified as DOMAIN\user.
+	NTLMAuthUsername string `yaml:"ntlm-username,omitempty" json:"ntlm-username,omitempty" jsonschema:"title=specifies the username for ntlm authentication,description=Optional parameter which specifies the username for ntlm auth"`
+	// description: |
+	//   NTLMAuthPassword specifies the password for NTLM authentication
+	NTLMAuthPassword string `yaml:"ntlm-password,omitempty" json:"ntlm-password,omitempty" jsonschema:"title=specifies the password for ntlm authentication,description=Optional parameter which specifies the password for ntlm auth"`
+	// description: |
+	//   NegotiateAuthUsername specifies the user@REALM principal for Negotiate (Kerberos) authentication
+	NegotiateAuthUsername string `yaml:"negotiate-username,omitempty" json:"negotiate-username,omitempty" jsonschema:"title=specifies the principal for negotiate authentication,description=Optional parameter which specifies the user@REALM principal for negotiate auth"`
+	// description: |
+	//   NegotiateAuthPassword specifies the password for Negotiate (Kerberos) authentication
+	NegotiateAuthPassword string `yaml:"negotiate-password,omitempty" json:"negotiate-password,omitempty" jsonschema:"title=specifies the password for negotiate authentication,description=Optional parameter which specifies the password for negotiate auth"`
+	// description: |
+	//   NegotiateAuthKDC specifies the KDC for Negotiate (Kerberos) authentication.
+	//
+	//   The KDC of the realm is looked up with DNS by default.
+	NegotiateAuthKDC string `yaml:"negotiate-kdc,omitempty" json:"negotiate-kdc,omitempty" jsonschema:"title=specifies the kdc for negotiate authentication,description=Optional parameter which specifies the kdc for negotiate auth"`
 }
 
 // Options returns executer options for http request
@@ -226,6 +275,8 @@ var RequestPartDefinitions = map[string]string{
 	"content_length":        "HTTP Response content length",
 	"header,all_headers":    "HTTP response headers",
 	"duration":              "HTTP request time duration",
+	"redirect_chain":        "HTTP redirect chain hops in status url -> location format",
+	"redirect_count":        "HTTP redirects followed for the response",
 	"all":                   "HTTP response body + headers",
 	"cookies_from_response": "HTTP response cookies in name:value format",
 	"headers_from_response": "HTTP response headers in name:value format",
@@ -245,6 +296,13 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 	if err := request.validate(); err != nil {
 		return errors.Wrap(err, "validation error")
 	}
+	if request.Signature.Value == "" && options.Options.Signature != "" {
+		signature, err := toSignatureType(options.Options.Signature)
+		if err != nil {
+			return errors.Wrap(err, "could not parse signature")
+		}
+		request.Signature.Value = signature
+	}
 
 	connectionConfiguration := &httpclientpool.Configuration{
 		Threads:      request.Threads,
@@ -255,6 +313,7 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 			DisableKeepAlive: httputil.ShouldDisableKeepAlive(options.Options),
 		},
 		RedirectFlow: httpclientpool.DontFollowRedirect,
+		Protocol:     request.Protocol,
 	}
 
 	if request.Redirects || options.Options.FollowRedirects {
@@ -298,6 +357,7 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 		}
 		request.rawhttpClient = httpclientpool.GetRawHTTP(options.Options)
 	}
+	request.authStrategy = request.getAuthStrategy()
 	if len(request.Matchers) > 0 || len(request.Extractors) > 0 {
 		compiled := &request.Operators
 		compiled.ExcludeMatchers = options.ExcludeMatchers
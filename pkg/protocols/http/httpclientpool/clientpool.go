@@ -18,7 +18,9 @@ import (
 
 	"github.com/projectdiscovery/fastdialer/fastdialer"
 	"github.com/projectdiscovery/fastdialer/fastdialer/ja3/impersonate"
+	"github.com/khulnasoft-lab/vulmap/pkg/authprovider/authx"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolstate"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http/h2c"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/utils"
 	"github.com/khulnasoft-lab/vulmap/pkg/types"
 	"github.com/khulnasoft-lab/vulmap/pkg/types/scanstrategy"
@@ -101,6 +103,8 @@ type Configuration struct {
 	RedirectFlow RedirectFlow
 	// Connection defines custom connection configuration
 	Connection *ConnectionConfiguration
+	// Protocol is the http protocol used to send the requests, HTTP/1.1 or HTTP/2 if empty
+	Protocol string
 }
 
 // Hash returns the hash of the configuration to allow client pooling
@@ -119,13 +123,15 @@ func (c *Configuration) Hash() string {
 	builder.WriteString(strconv.FormatBool(c.CookieReuse))
 	builder.WriteString("c")
 	builder.WriteString(strconv.FormatBool(c.Connection != nil))
+	builder.WriteString("p")
+	builder.WriteString(c.Protocol)
 	hash := builder.String()
 	return hash
 }
 
 // HasStandardOptions checks whether the configuration requires custom settings
 func (c *Configuration) HasStandardOptions() bool {
-	return c.Threads == 0 && c.MaxRedirects == 0 && c.RedirectFlow == DontFollowRedirect && !c.CookieReuse && c.Connection == nil && !c.NoTimeout
+	return c.Threads == 0 && c.MaxRedirects == 0 && c.RedirectFlow == DontFollowRedirect && !c.CookieReuse && c.Connection == nil && !c.NoTimeout && c.Protocol == ""
 }
 
 // GetRawHTTP returns the rawhttp request client
@@ -249,24 +255,15 @@ func wrappedGet(options *types.Options, configuration *Configuration) (*retryabl
 			transport.Proxy = http.ProxyURL(proxyURL)
 		}
 	} else if types.ProxySocksURL != "" {
-		socksURL, proxyErr := url.Parse(types.ProxySocksURL)
-		if proxyErr != nil {
-			return nil, proxyErr
-		}
-
-		dialer, err := proxy.FromURL(socksURL, proxy.Direct)
+		dialContext, err := SocksDialContext(types.ProxySocksURL)
 		if err != nil {
 			return nil, err
 		}
 
-		dc := dialer.(interface {
-			DialContext(ctx context.Context, network, addr string) (net.Conn, error)
-		})
-
-		transport.DialContext = dc.DialContext
+		transport.DialContext = dialContext
 		transport.DialTLSContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
 			// upgrade proxy connection to tls
-			conn, err := dc.DialContext(ctx, network, addr)
+			conn, err := dialContext(ctx, network, addr)
 			if err != nil {
 				return nil, err
 			}
@@ -283,8 +280,40 @@ func wrappedGet(options *types.Options, configuration *Configuration) (*retryabl
 		}
 	}
 
+	var roundTripper http.RoundTripper = transport
+	protocol := configuration.Protocol
+	if protocol == "" {
+		protocol = options.HTTPProtocol
+	}
+	switch protocol {
+	case HTTP3Protocol, AltSvcProtocol:
+		// quic connections can't be tunneled through http or socks5 proxies
+		if types.ProxyURL != "" || types.ProxySocksURL != "" {
+			return nil, errors.New("http/3 requests can't be sent through a proxy")
+		}
+		timeout := time.Duration(options.Timeout) * time.Second
+		if protocol == HTTP3Protocol {
+			roundTripper = newHTTP3Transport(tlsConfig, timeout, resolveQUICAddress)
+		} else {
+			roundTripper = newAltSvcTransport(transport, tlsConfig, timeout)
+		}
+	case H2CProtocol, H2CPriorKnowledgeProtocol:
+		// h2c requests are sent over raw connections which can't be tunneled through
+		// http proxies, socks proxies are used through the dialer of the transport.
+		if types.ProxyURL != "" {
+			return nil, errors.New("h2c requests can't be sent through a http proxy")
+		}
+		roundTripper = &h2c.Transport{
+			Dial:            transport.DialContext,
+			TLSClientConfig: tlsConfig,
+			PriorKnowledge:  protocol == H2CPriorKnowledgeProtocol,
+			// bodies are buffered by the transport and read up to the same size
+			MaxResponseBodySize: int64(options.ResponseReadSize),
+		}
+	}
+
 	httpclient := &http.Client{
-		Transport:     transport,
+		Transport:     roundTripper,
 		CheckRedirect: makeCheckRedirectFunc(redirectFlow, maxRedirects),
 	}
 	if !configuration.NoTimeout {
@@ -294,6 +323,8 @@ func wrappedGet(options *types.Options, configuration *Configuration) (*retryabl
 	if jar != nil {
 		client.HTTPClient.Jar = jar
 	}
+	// connection based auth strategies like ntlm send requests themselves
+	client.HTTPClient.Transport = authx.NewTransport(client.HTTPClient.Transport)
 	client.CheckRetry = retryablehttp.HostSprayRetryPolicy()
 
 	// Only add to client pool if we don't have a cookie jar in place.
@@ -305,6 +336,23 @@ func wrappedGet(options *types.Options, configuration *Configuration) (*retryabl
 	return client, nil
 }
 
+// SocksDialContext returns a dial function connecting through the socks proxy
+func SocksDialContext(proxyURL string) (func(ctx context.Context, network, addr string) (net.Conn, error), error) {
+	socksURL, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	dialer, err := proxy.FromURL(socksURL, proxy.Direct)
+	if err != nil {
+		return nil, err
+	}
+	contextDialer, ok := dialer.(proxy.ContextDialer)
+	if !ok {
+		return nil, errors.Errorf("unsupported socks proxy %s", socksURL.Scheme)
+	}
+	return contextDialer.DialContext, nil
+}
+
 type RedirectFlow uint8
 
 const (
@@ -319,6 +367,9 @@ type checkRedirectFunc func(req *http.Request, via []*http.Request) error
 
 func makeCheckRedirectFunc(redirectType RedirectFlow, maxRedirects int) checkRedirectFunc {
 	return func(req *http.Request, via []*http.Request) error {
+		// the response redirecting to req was received by the last request
+		recordRedirect(req, len(via)-1)
+
 		switch redirectType {
 		case DontFollowRedirect:
 			return http.ErrUseLastResponse
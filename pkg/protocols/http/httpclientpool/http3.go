@@ -0,0 +1,215 @@
+package httpclientpool
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+
+	"github.com/projectdiscovery/fastdialer/fastdialer"
+)
+
+// defaultAltSvcMaxAge is the freshness lifetime of alternative
+// services advertised without the ma parameter.
+const defaultAltSvcMaxAge = 24 * time.Hour
+
+// newHTTP3Transport creates a new HTTP/3 round tripper dialing
+// QUIC connections to the address resolved by the fastdialer.
+func newHTTP3Transport(tlsConfig *tls.Config, timeout time.Duration, dial func(ctx context.Context, addr string) (string, error)) *http3.RoundTripper {
+	return &http3.RoundTripper{
+		TLSClientConfig: tlsConfig.Clone(),
+		QuicConfig: &quic.Config{
+			HandshakeIdleTimeout: timeout,
+			MaxIdleTimeout:       timeout,
+		},
+		Dial: func(ctx context.Context, addr string, tlsCfg *tls.Config, cfg *quic.Config) (quic.EarlyConnection, error) {
+			address, err := dial(ctx, addr)
+			if err != nil {
+				return nil, err
+			}
+			return quic.DialAddrEarly(ctx, address, tlsCfg, cfg)
+		},
+	}
+}
+
+// resolveQUICAddress resolves the host of the address with the fastdialer
+// honouring the custom ip of the request context.
+func resolveQUICAddress(ctx context.Context, addr string) (string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", err
+	}
+	if ip, ok := ctx.Value(fastdialer.IP).(string); ok && ip != "" {
+		return net.JoinHostPort(ip, port), nil
+	}
+	if net.ParseIP(host) != nil || Dialer == nil {
+		return addr, nil
+	}
+	data, err := Dialer.GetDNSData(host)
+	if err != nil {
+		return "", errors.Wrap(err, "could not resolve host")
+	}
+	switch {
+	case len(data.A) > 0:
+		return net.JoinHostPort(data.A[0], port), nil
+	case len(data.AAAA) > 0:
+		return net.JoinHostPort(data.AAAA[0], port), nil
+	}
+	return "", errors.Errorf("could not resolve host %s", host)
+}
+
+// altSvcTransport sends the requests over HTTP/3 to the hosts advertising
+// it with the Alt-Svc header and over the fallback transport otherwise.
+type altSvcTransport struct {
+	fallback http.RoundTripper
+	h3       *http3.RoundTripper
+
+	mu       sync.RWMutex
+	services map[string]altService
+}
+
+// altService is an HTTP/3 alternative service of an origin
+type altService struct {
+	address string
+	expires time.Time
+}
+
+// newAltSvcTransport creates a new Alt-Svc discovering transport
+func newAltSvcTransport(fallback http.RoundTripper, tlsConfig *tls.Config, timeout time.Duration) *altSvcTransport {
+	t := &altSvcTransport{fallback: fallback, services: make(map[string]altService)}
+	t.h3 = newHTTP3Transport(tlsConfig, timeout, func(ctx context.Context, addr string) (string, error) {
+		if service, ok := t.service(addr); ok {
+			addr = service.address
+		}
+		return resolveQUICAddress(ctx, addr)
+	})
+	return t
+}
+
+// RoundTrip sends the request over HTTP/3 if an alternative service is
+// known for its origin. Alternative services failing to connect are
+// forgotten and the request is retried over the fallback transport.
+func (t *altSvcTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	origin := originAddress(req)
+	if _, ok := t.service(origin); ok {
+		resp, err := t.h3.RoundTrip(req)
+		if err == nil {
+			return resp, nil
+		}
+		t.forget(origin)
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return nil, err
+			}
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+
+	resp, err := t.fallback.RoundTrip(req)
+	if err == nil && req.URL.Scheme == "https" {
+		t.discover(origin, req.URL.Hostname(), resp.Header.Get("Alt-Svc"))
+	}
+	return resp, err
+}
+
+// CloseIdleConnections closes the idle connections of the transports
+func (t *altSvcTransport) CloseIdleConnections() {
+	if closer, ok := t.fallback.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+	t.h3.CloseIdleConnections()
+}
+
+// service returns the fresh alternative service of the origin
+func (t *altSvcTransport) service(origin string) (altService, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	service, ok := t.services[origin]
+	if !ok || time.Now().After(service.expires) {
+		return altService{}, false
+	}
+	return service, true
+}
+
+// forget removes the alternative service of the origin
+func (t *altSvcTransport) forget(origin string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.services, origin)
+}
+
+// discover stores the HTTP/3 alternative service of the origin
+// advertised by the Alt-Svc header value.
+func (t *altSvcTransport) discover(origin, hostname, value string) {
+	if value == "" {
+		return
+	}
+	if strings.TrimSpace(value) == "clear" {
+		t.forget(origin)
+		return
+	}
+	service, ok := parseAltSvc(value, hostname)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.services[origin] = service
+}
+
+// parseAltSvc returns the first HTTP/3 alternative service of the
+// Alt-Svc header value, an alternative with an empty host uses the
+// hostname of the origin.
+//
+// Example: h3=":443"; ma=86400, h3-29=":443"; ma=86400
+func parseAltSvc(value, hostname string) (altService, bool) {
+	for _, alternative := range strings.Split(value, ",") {
+		params := strings.Split(alternative, ";")
+		protocol, authority, ok := strings.Cut(strings.TrimSpace(params[0]), "=")
+		if !ok || protocol != "h3" {
+			continue
+		}
+		host, port, err := net.SplitHostPort(strings.Trim(authority, `"`))
+		if err != nil {
+			continue
+		}
+		if host == "" {
+			host = hostname
+		}
+		maxAge := defaultAltSvcMaxAge
+		for _, param := range params[1:] {
+			if key, value, ok := strings.Cut(strings.TrimSpace(param), "="); ok && key == "ma" {
+				if seconds, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil {
+					maxAge = time.Duration(seconds) * time.Second
+				}
+			}
+		}
+		return altService{address: net.JoinHostPort(host, port), expires: time.Now().Add(maxAge)}, true
+	}
+	return altService{}, false
+}
+
+// originAddress returns the host:port of the origin of the request
+func originAddress(req *http.Request) string {
+	if port := req.URL.Port(); port != "" {
+		return req.URL.Host
+	}
+	port := "80"
+	if req.URL.Scheme == "https" {
+		port = "443"
+	}
+	return net.JoinHostPort(req.URL.Hostname(), port)
+}
@@ -0,0 +1,115 @@
+package httpclientpool
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/http3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAltSvc(t *testing.T) {
+	service, ok := parseAltSvc(`h3-29=":8443"; ma=60, h3=":443"; ma=3600`, "example.com")
+	require.True(t, ok, "could not parse alt-svc header")
+	require.Equal(t, "example.com:443", service.address, "could not get address of h3 alternative")
+	require.WithinDuration(t, time.Now().Add(time.Hour), service.expires, time.Second, "could not get max age of alternative")
+
+	service, ok = parseAltSvc(`h3="alt.example.com:8443"`, "example.com")
+	require.True(t, ok, "could not parse alt-svc header")
+	require.Equal(t, "alt.example.com:8443", service.address, "could not get alternative host")
+	require.WithinDuration(t, time.Now().Add(defaultAltSvcMaxAge), service.expires, time.Second, "could not get default max age")
+
+	_, ok = parseAltSvc(`h2=":443"; ma=60`, "example.com")
+	require.False(t, ok, "could not ignore non h3 alternatives")
+}
+
+// protoHandler writes the protocol of the request with the alt-svc header
+func protoHandler(altSvc func() string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if value := altSvc(); value != "" {
+			w.Header().Set("Alt-Svc", value)
+		}
+		_, _ = w.Write([]byte(r.Proto))
+	}
+}
+
+// newHTTP3Server starts a http/3 server returning its udp port
+func newHTTP3Server(t *testing.T, tlsConfig *tls.Config) int {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen for quic")
+	server := &http3.Server{
+		Handler:   protoHandler(func() string { return "" }),
+		TLSConfig: http3.ConfigureTLSConfig(tlsConfig),
+	}
+	go func() { _ = server.Serve(conn) }()
+	t.Cleanup(func() {
+		_ = server.Close()
+		_ = conn.Close()
+	})
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func sendProtoRequest(t *testing.T, client *http.Client, url string) string {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	require.Nil(t, err, "could not create request")
+	resp, err := client.Do(req)
+	require.Nil(t, err, "could not send request")
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.Nil(t, err, "could not read response body")
+	require.Equal(t, resp.Proto, string(body), "could not get protocol of response")
+	return resp.Proto
+}
+
+func TestHTTP3Transport(t *testing.T) {
+	ts := httptest.NewTLSServer(protoHandler(func() string { return "" }))
+	defer ts.Close()
+	port := newHTTP3Server(t, ts.TLS.Clone())
+
+	transport := newHTTP3Transport(&tls.Config{InsecureSkipVerify: true}, 5*time.Second, resolveQUICAddress)
+	defer transport.Close()
+	client := &http.Client{Transport: transport}
+	require.Equal(t, "HTTP/3.0", sendProtoRequest(t, client, "https://127.0.0.1:"+strconv.Itoa(port)), "could not send request over http/3")
+}
+
+func TestAltSvcTransport(t *testing.T) {
+	altSvc := ""
+	ts := httptest.NewTLSServer(protoHandler(func() string { return altSvc }))
+	defer ts.Close()
+	port := newHTTP3Server(t, ts.TLS.Clone())
+
+	client := ts.Client()
+	transport := newAltSvcTransport(client.Transport, &tls.Config{InsecureSkipVerify: true}, time.Second)
+	client.Transport = transport
+	origin := strings.TrimPrefix(ts.URL, "https://")
+
+	// the request advertising the alternative service is sent over http/1.1
+	altSvc = `h3=":` + strconv.Itoa(port) + `"; ma=60`
+	require.Equal(t, "HTTP/1.1", sendProtoRequest(t, client, ts.URL), "could not send request over fallback")
+	service, ok := transport.service(origin)
+	require.True(t, ok, "could not discover alternative service")
+	require.Equal(t, "127.0.0.1:"+strconv.Itoa(port), service.address, "could not use hostname of origin")
+	require.Equal(t, "HTTP/3.0", sendProtoRequest(t, client, ts.URL), "could not send request over alternative service")
+
+	// alternative services failing to connect are forgotten
+	altSvc = `h3="127.0.0.1:1"; ma=60`
+	transport.discover(origin, "127.0.0.1", altSvc)
+	transport.CloseIdleConnections()
+	altSvc = ""
+	require.Equal(t, "HTTP/1.1", sendProtoRequest(t, client, ts.URL), "could not fall back to http/1.1")
+	_, ok = transport.service(origin)
+	require.False(t, ok, "could not forget failing alternative service")
+
+	transport.discover("example.com:443", "example.com", `h3=":443"`)
+	transport.discover("example.com:443", "example.com", "clear")
+	require.Empty(t, transport.services, "could not clear alternative services")
+}
@@ -0,0 +1,34 @@
+package httpclientpool
+
+import (
+	"strings"
+
+	sliceutil "github.com/khulnasoft-lab/utils/slice"
+)
+
+const (
+	// HTTP3Protocol sends the requests over HTTP/3 (QUIC)
+	HTTP3Protocol = "h3"
+	// AltSvcProtocol sends the requests over HTTP/3 to the hosts advertising
+	// it with the Alt-Svc header and over HTTP/1.1 or HTTP/2 otherwise.
+	AltSvcProtocol = "alt-svc"
+	// H2CProtocol sends the requests over cleartext HTTP/2 upgrading the
+	// connection with the first request sent over HTTP/1.1.
+	H2CProtocol = "h2c"
+	// H2CPriorKnowledgeProtocol sends the requests over cleartext HTTP/2
+	// without upgrading the connection.
+	H2CPriorKnowledgeProtocol = "h2c-prior-knowledge"
+)
+
+// supportedProtocols are the http protocols which can be used to send requests
+var supportedProtocols = []string{HTTP3Protocol, AltSvcProtocol, H2CProtocol, H2CPriorKnowledgeProtocol}
+
+// SupportedProtocols returns the comma separated list of supported http protocols
+func SupportedProtocols() string {
+	return strings.Join(supportedProtocols, ", ")
+}
+
+// IsSupportedProtocol returns true if the http protocol is supported
+func IsSupportedProtocol(protocol string) bool {
+	return protocol == "" || sliceutil.Contains(supportedProtocols, protocol)
+}
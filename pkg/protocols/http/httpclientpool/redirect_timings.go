@@ -0,0 +1,66 @@
+package httpclientpool
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type redirectTimingsKey struct{}
+
+// RedirectTimings records the times at which the responses of the
+// hops of a redirect chain were received by the client.
+type RedirectTimings struct {
+	mu       sync.Mutex
+	received []time.Time
+}
+
+// WithRedirectTimings returns a context recording the redirect timings
+// of the requests sent with it.
+func WithRedirectTimings(ctx context.Context) (context.Context, *RedirectTimings) {
+	timings := &RedirectTimings{}
+	return context.WithValue(ctx, redirectTimingsKey{}, timings), timings
+}
+
+// recordRedirect records the response of a hop of the redirect
+// chain of the request being received.
+func recordRedirect(req *http.Request, hop int) {
+	timings, ok := req.Context().Value(redirectTimingsKey{}).(*RedirectTimings)
+	if !ok {
+		return
+	}
+	timings.mu.Lock()
+	defer timings.mu.Unlock()
+
+	// retried requests restart the chain so that the hop is overwritten
+	timings.received = append(timings.received[:min(hop, len(timings.received))], time.Now())
+}
+
+// Durations returns the durations of the hops of a redirect chain started
+// at start and whose final response was received at end. It can be called
+// on nil timings for requests sent without recording them.
+func (t *RedirectTimings) Durations(start, end time.Time, hops int) []time.Duration {
+	var recorded []time.Time
+	if t != nil {
+		t.mu.Lock()
+		recorded = t.received
+		t.mu.Unlock()
+	}
+
+	durations := make([]time.Duration, hops)
+	previous := start
+	for i := range durations {
+		received := end
+		if i < hops-1 {
+			// hops without recorded timings are reported as instant
+			received = previous
+			if i < len(recorded) {
+				received = recorded[i]
+			}
+		}
+		durations[i] = max(received.Sub(previous), 0)
+		previous = received
+	}
+	return durations
+}
@@ -0,0 +1,43 @@
+package httpclientpool
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedirectTimings(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			time.Sleep(50 * time.Millisecond)
+			http.Redirect(w, r, "/final", http.StatusFound)
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer ts.Close()
+
+	ctx, timings := WithRedirectTimings(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+	require.Nil(t, err, "could not create request")
+	client := &http.Client{CheckRedirect: makeCheckRedirectFunc(FollowAllRedirect, 0)}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	require.Nil(t, err, "could not send request")
+	resp.Body.Close()
+	end := time.Now()
+
+	durations := timings.Durations(start, end, 2)
+	require.Len(t, durations, 2, "could not get durations of hops")
+	require.GreaterOrEqual(t, durations[0], 50*time.Millisecond, "could not get duration of redirect")
+	require.GreaterOrEqual(t, durations[1], 100*time.Millisecond, "could not get duration of final response")
+	require.Equal(t, end.Sub(start), durations[0]+durations[1], "could not split duration between hops")
+
+	var missing *RedirectTimings
+	require.Equal(t, []time.Duration{0, time.Second}, missing.Durations(start, start.Add(time.Second), 2), "could not get durations without timings")
+}
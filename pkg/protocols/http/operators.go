@@ -42,6 +42,13 @@ func (request *Request) Match(data map[string]interface{}, matcher *matchers.Mat
 		return matcher.Result(matcher.MatchDSL(data)), []string{}
 	case matchers.XPathMatcher:
 		return matcher.Result(matcher.MatchXPath(item)), []string{}
+	case matchers.DiffMatcher:
+		// baseline is only available for fuzzed requests
+		baseline, ok := request.getMatchPart(matcher.Part, matchers.BaselineData(data))
+		if !ok {
+			return false, []string{}
+		}
+		return matcher.ResultWithMatchedSnippet(matcher.MatchDiff(item, baseline, data))
 	}
 	return false, []string{}
 }
@@ -106,7 +113,7 @@ func (request *Request) getMatchPart(part string, data output.InternalEvent) (st
 
 // responseToDSLMap converts an HTTP response to a map for use in DSL matching
 func (request *Request) responseToDSLMap(resp *http.Response, host, matched, rawReq, rawResp, body, headers string, duration time.Duration, extra map[string]interface{}) output.InternalEvent {
-	data := make(output.InternalEvent, 12+len(extra)+len(resp.Header)+len(resp.Cookies()))
+	data := make(output.InternalEvent, 13+len(extra)+len(resp.Header)+len(resp.Cookies()))
 	for k, v := range extra {
 		data[k] = v
 	}
@@ -123,6 +130,7 @@ func (request *Request) responseToDSLMap(resp *http.Response, host, matched, raw
 	data["request"] = rawReq
 	data["response"] = rawResp
 	data["status_code"] = resp.StatusCode
+	data["protocol_version"] = resp.Proto
 	data["body"] = body
 	data["all_headers"] = headers
 	data["header"] = headers
@@ -164,6 +172,10 @@ func (request *Request) MakeResultEventItem(wrapped *output.InternalWrappedEvent
 		Request:          types.ToString(wrapped.InternalEvent["request"]),
 		Response:         request.truncateResponse(wrapped.InternalEvent["response"]),
 		CURLCommand:      types.ToString(wrapped.InternalEvent["curl-command"]),
+		ProtocolVersion:  types.ToString(wrapped.InternalEvent["protocol_version"]),
+	}
+	if chain, ok := wrapped.InternalEvent["redirect-chain"].([]output.RedirectHop); ok {
+		data.RedirectChain = chain
 	}
 	return data
 }
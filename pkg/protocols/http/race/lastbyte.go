@@ -0,0 +1,109 @@
+package race
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// LastByte sends each request over its own HTTP/1.1 connection holding back
+// the last byte of the requests until all others are sent, then sends the
+// last bytes together so that the requests are completed at the same time.
+func LastByte(ctx context.Context, requests []*http.Request, options *Options) ([]*Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, options.Timeout)
+	defer cancel()
+
+	conns := make([]net.Conn, len(requests))
+	defer func() {
+		for _, conn := range conns {
+			if conn != nil {
+				conn.Close()
+			}
+		}
+	}()
+
+	lastBytes := make([]byte, len(requests))
+	for i, req := range requests {
+		data, err := serializeRequest(req)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := dial(ctx, req, options, "http/1.1")
+		if err != nil {
+			return nil, err
+		}
+		conns[i] = conn
+		_ = conn.SetDeadline(time.Now().Add(options.Timeout))
+
+		if _, err := conn.Write(data[:len(data)-1]); err != nil {
+			return nil, errors.Wrap(err, "could not write request to server")
+		}
+		lastBytes[i] = data[len(data)-1]
+	}
+
+	results := make([]*Result, len(requests))
+	for i, conn := range conns {
+		if _, err := conn.Write(lastBytes[i : i+1]); err != nil {
+			results[i] = &Result{Err: errors.Wrap(err, "could not write request to server")}
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i, conn := range conns {
+		if results[i] != nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, conn net.Conn) {
+			defer wg.Done()
+			results[i] = readResponse(conn, requests[i])
+		}(i, conn)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// serializeRequest returns the HTTP/1.1 wire format of the request
+func serializeRequest(req *http.Request) ([]byte, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	// the content length is set to avoid chunked transfer encoding
+	cloned := req.Clone(req.Context())
+	cloned.ContentLength = int64(len(body))
+	cloned.Body = io.NopCloser(bytes.NewReader(body))
+	if len(body) == 0 {
+		cloned.Body = nil
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := cloned.Write(buffer); err != nil {
+		return nil, errors.Wrap(err, "could not serialize request")
+	}
+	return buffer.Bytes(), nil
+}
+
+// readResponse reads the response of the request with its body
+func readResponse(conn net.Conn, req *http.Request) *Result {
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return &Result{Err: errors.Wrap(err, "could not read http response")}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &Result{Err: errors.Wrap(err, "could not read http body")}
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	return &Result{Response: resp}
+}
@@ -0,0 +1,112 @@
+package race
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Strategies of synchronising race condition requests
+const (
+	// SinglePacketStrategy sends the final frames of the requests over a single
+	// HTTP/2 connection in one packet, falling back to LastByteStrategy for
+	// hosts not supporting HTTP/2.
+	SinglePacketStrategy = "single-packet"
+	// LastByteStrategy sends the last byte of the requests sent over their
+	// own HTTP/1.1 connections at the same time.
+	LastByteStrategy = "last-byte"
+)
+
+// DialFunc dials a connection to the address
+type DialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// Options contains the configuration of synchronised race requests
+type Options struct {
+	// Dial dials the connections to the host
+	Dial DialFunc
+	// SNI is the server name of tls connections, the hostname if empty
+	SNI string
+	// Timeout is the timeout of sending the requests and reading the responses
+	Timeout time.Duration
+}
+
+// Result is the response or the error of a race request
+type Result struct {
+	Response *http.Response
+	Err      error
+}
+
+// Send sends the requests to the same host with the strategy
+func Send(ctx context.Context, strategy string, requests []*http.Request, options *Options) ([]*Result, error) {
+	if len(requests) == 0 {
+		return nil, nil
+	}
+	for _, req := range requests[1:] {
+		if req.URL.Scheme != requests[0].URL.Scheme || req.URL.Host != requests[0].URL.Host {
+			return nil, errors.New("race requests must be sent to the same host")
+		}
+	}
+	switch strategy {
+	case SinglePacketStrategy:
+		return SinglePacket(ctx, requests, options)
+	case LastByteStrategy:
+		return LastByte(ctx, requests, options)
+	default:
+		return nil, errors.Errorf("unknown race strategy %s", strategy)
+	}
+}
+
+// dial dials a connection to the host of the url negotiating
+// the protocols with alpn for https urls.
+func dial(ctx context.Context, req *http.Request, options *Options, protocols ...string) (net.Conn, error) {
+	address := req.URL.Host
+	if req.URL.Port() == "" {
+		port := "80"
+		if req.URL.Scheme == "https" {
+			port = "443"
+		}
+		address = net.JoinHostPort(req.URL.Hostname(), port)
+	}
+	conn, err := options.Dial(ctx, "tcp", address)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not connect to server")
+	}
+	if req.URL.Scheme != "https" {
+		return conn, nil
+	}
+	serverName := options.SNI
+	if serverName == "" {
+		serverName = req.URL.Hostname()
+	}
+	tlsConn := tls.Client(conn, &tls.Config{
+		InsecureSkipVerify: true,
+		ServerName:         serverName,
+		MinVersion:         tls.VersionTLS10,
+		NextProtos:         protocols,
+	})
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "could not complete tls handshake")
+	}
+	return tlsConn, nil
+}
+
+// readBody reads the body of the request to send it in frames or bytes
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read request body")
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
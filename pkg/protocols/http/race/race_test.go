@@ -0,0 +1,189 @@
+package race
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+func newRequests(t *testing.T, url string, count int) []*http.Request {
+	requests := make([]*http.Request, count)
+	for i := range requests {
+		req, err := http.NewRequest(http.MethodPost, url+"/race?id="+string(rune('a'+i)), strings.NewReader("data="+string(rune('a'+i))))
+		require.Nil(t, err, "could not create request")
+		req.Header.Set("X-Race", "true")
+		requests[i] = req
+	}
+	return requests
+}
+
+func raceHandler(requests *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Proto", r.Proto)
+		_, _ = w.Write([]byte(r.Header.Get("X-Race") + " " + r.URL.Query().Get("id") + " " + string(body)))
+	}
+}
+
+func testOptions() *Options {
+	dialer := &net.Dialer{}
+	return &Options{Dial: dialer.DialContext, Timeout: 5 * time.Second}
+}
+
+func requireResults(t *testing.T, results []*Result, proto string) {
+	require.Len(t, results, 3, "could not get results of requests")
+	for i, result := range results {
+		require.Nil(t, result.Err, "could not send race request")
+		require.Equal(t, http.StatusOK, result.Response.StatusCode)
+		require.Equal(t, proto, result.Response.Header.Get("X-Proto"))
+
+		body, err := io.ReadAll(result.Response.Body)
+		require.Nil(t, err, "could not read response body")
+		id := string(rune('a' + i))
+		require.Equal(t, "true "+id+" data="+id, string(body), "could not match response to request")
+	}
+}
+
+func TestLastByte(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(raceHandler(&requests))
+	defer ts.Close()
+
+	results, err := Send(context.Background(), LastByteStrategy, newRequests(t, ts.URL, 3), testOptions())
+	require.Nil(t, err, "could not send race requests")
+	requireResults(t, results, "HTTP/1.1")
+	require.Equal(t, int32(3), requests.Load())
+}
+
+func TestSinglePacket(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewUnstartedServer(raceHandler(&requests))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	results, err := Send(context.Background(), SinglePacketStrategy, newRequests(t, ts.URL, 3), testOptions())
+	require.Nil(t, err, "could not send race requests")
+	requireResults(t, results, "HTTP/2.0")
+	require.Equal(t, int32(3), requests.Load())
+
+	t.Run("fallback", func(t *testing.T) {
+		ts := httptest.NewTLSServer(raceHandler(&requests))
+		defer ts.Close()
+
+		results, err := Send(context.Background(), SinglePacketStrategy, newRequests(t, ts.URL, 3), testOptions())
+		require.Nil(t, err, "could not send race requests")
+		requireResults(t, results, "HTTP/1.1")
+	})
+
+	t.Run("connection-window", func(t *testing.T) {
+		// the connection window of the server is raised to 1MB
+		for size, proto := range map[int]string{100 << 10: "HTTP/2.0", 512 << 10: "HTTP/1.1"} {
+			requests := make([]*http.Request, 3)
+			for i := range requests {
+				req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(strings.Repeat("a", size)))
+				require.Nil(t, err, "could not create request")
+				requests[i] = req
+			}
+			results, err := Send(context.Background(), SinglePacketStrategy, requests, testOptions())
+			require.Nil(t, err, "could not send race requests")
+			for _, result := range results {
+				require.Nil(t, result.Err, "could not send race request")
+				require.Equal(t, proto, result.Response.Header.Get("X-Proto"), "could not send bodies of %d bytes", size)
+			}
+		}
+	})
+
+	_, err = Send(context.Background(), SinglePacketStrategy, append(newRequests(t, ts.URL, 1), newRequests(t, "https://example.com", 1)...), testOptions())
+	require.NotNil(t, err, "could not reject requests to different hosts")
+}
+
+// earlyDataServer is an http2 server which responds to the streams as soon
+// as their headers are received and sends their final data frames before
+// acknowledging the ping.
+func earlyDataServer(t *testing.T) string {
+	certificate := httptest.NewUnstartedServer(nil)
+	certificate.StartTLS()
+	t.Cleanup(certificate.Close)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: certificate.TLS.Certificates, NextProtos: []string{"h2"}})
+	require.Nil(t, err, "could not listen")
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if _, err := io.ReadFull(conn, make([]byte, len(http2.ClientPreface))); err != nil {
+			return
+		}
+		framer := http2.NewFramer(conn, conn)
+		framer.ReadMetaHeaders = hpack.NewDecoder(4096, nil)
+		if err := framer.WriteSettings(); err != nil {
+			return
+		}
+
+		headers := &bytes.Buffer{}
+		encoder := hpack.NewEncoder(headers)
+		var streams []uint32
+		for {
+			frame, err := framer.ReadFrame()
+			if err != nil {
+				return
+			}
+			switch frame := frame.(type) {
+			case *http2.SettingsFrame:
+				if !frame.IsAck() {
+					_ = framer.WriteSettingsAck()
+				}
+			case *http2.MetaHeadersFrame:
+				streams = append(streams, frame.StreamID)
+				headers.Reset()
+				_ = encoder.WriteField(hpack.HeaderField{Name: ":status", Value: "200"})
+				_ = encoder.WriteField(hpack.HeaderField{Name: "x-proto", Value: "HTTP/2.0"})
+				_ = framer.WriteHeaders(http2.HeadersFrameParam{StreamID: frame.StreamID, BlockFragment: headers.Bytes(), EndHeaders: true})
+				_ = framer.WriteData(frame.StreamID, false, []byte("early "))
+			case *http2.PingFrame:
+				if frame.IsAck() {
+					continue
+				}
+				for _, id := range streams {
+					_ = framer.WriteData(id, true, []byte("data"))
+				}
+				_ = framer.WritePing(true, frame.Data)
+			}
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestSinglePacketEarlyData(t *testing.T) {
+	address := earlyDataServer(t)
+
+	options := testOptions()
+	options.Timeout = 2 * time.Second
+	results, err := Send(context.Background(), SinglePacketStrategy, newRequests(t, "https://"+address, 3), options)
+	require.Nil(t, err, "could not send race requests")
+	require.Len(t, results, 3, "could not get results of requests")
+	for _, result := range results {
+		require.Nil(t, result.Err, "could not read response sent before ping acknowledgement")
+		body, err := io.ReadAll(result.Response.Body)
+		require.Nil(t, err, "could not read response body")
+		require.Equal(t, "early data", string(body), "could not get data sent before ping acknowledgement")
+	}
+}
@@ -0,0 +1,458 @@
+package race
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+const (
+	// maxFrameSize is the maximum size of frames sent to the server
+	maxFrameSize = 16384
+	// defaultWindowSize is the initial flow control window of streams
+	// and of the connection
+	defaultWindowSize = 65535
+	// windowIncrement is the flow control window granted to the server
+	windowIncrement = 1 << 30
+)
+
+// SinglePacket sends the requests over a single HTTP/2 connection. All
+// frames but the final frame of each request are sent first, then the
+// final frames are flushed together so that they arrive in a single
+// packet and the requests are completed at the same time regardless of
+// network jitter. Hosts not supporting HTTP/2, or whose connection flow
+// control window is smaller than the bodies of the requests, are sent
+// the requests with last-byte synchronisation.
+func SinglePacket(ctx context.Context, requests []*http.Request, options *Options) ([]*Result, error) {
+	if requests[0].URL.Scheme != "https" {
+		return LastByte(ctx, requests, options)
+	}
+	var bodySize int64
+	for _, req := range requests {
+		body, err := readBody(req)
+		if err != nil {
+			return nil, err
+		}
+		bodySize += int64(len(body))
+	}
+	ctx, cancel := context.WithTimeout(ctx, options.Timeout)
+	defer cancel()
+
+	conn, err := dial(ctx, requests[0], options, "h2", "http/1.1")
+	if err != nil {
+		return nil, err
+	}
+	if conn.(*tls.Conn).ConnectionState().NegotiatedProtocol != "h2" {
+		conn.Close()
+		return LastByte(ctx, requests, options)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(options.Timeout))
+
+	h2 := newH2Conn(conn)
+	if err := h2.handshake(len(requests)); err != nil {
+		return nil, err
+	}
+	// window updates of the connection sent after the settings are
+	// received before the ping is acknowledged
+	if bodySize > h2.connWindow {
+		if err := h2.ping(); err != nil {
+			return nil, err
+		}
+	}
+	if bodySize > h2.connWindow {
+		conn.Close()
+		return LastByte(ctx, requests, options)
+	}
+
+	final := &bytes.Buffer{}
+	for i, req := range requests {
+		frame, err := h2.writeRequest(streamID(i), req)
+		if err != nil {
+			return nil, err
+		}
+		final.Write(frame)
+	}
+	// the ping is acknowledged once the server received the frames sent
+	// before so that only the final frames are in flight together
+	if err := h2.ping(); err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(final.Bytes()); err != nil {
+		return nil, errors.Wrap(err, "could not write final frames to server")
+	}
+	return h2.readResponses(requests), nil
+}
+
+// streamID returns the id of the stream of the nth request
+func streamID(n int) uint32 {
+	return uint32(2*n + 1)
+}
+
+// h2Conn is a minimal HTTP/2 client connection
+type h2Conn struct {
+	conn     net.Conn
+	writer   *bufio.Writer
+	framer   *http2.Framer
+	encoder  *hpack.Encoder
+	headers  *bytes.Buffer
+	window   uint32
+	frameMax uint32
+	// connWindow is the flow control window of the connection
+	connWindow int64
+	// pending are the frames read while waiting for the ping acknowledgement
+	pending []http2.Frame
+}
+
+// newH2Conn creates a new HTTP/2 client connection
+func newH2Conn(conn net.Conn) *h2Conn {
+	h2 := &h2Conn{
+		conn:       conn,
+		writer:     bufio.NewWriter(conn),
+		headers:    &bytes.Buffer{},
+		window:     defaultWindowSize,
+		frameMax:   maxFrameSize,
+		connWindow: defaultWindowSize,
+	}
+	h2.framer = http2.NewFramer(h2.writer, conn)
+	h2.framer.ReadMetaHeaders = hpack.NewDecoder(4096, nil)
+	h2.encoder = hpack.NewEncoder(h2.headers)
+	return h2
+}
+
+// handshake exchanges the connection prefaces and settings
+func (h *h2Conn) handshake(streams int) error {
+	if _, err := h.writer.WriteString(http2.ClientPreface); err != nil {
+		return errors.Wrap(err, "could not write http2 preface")
+	}
+	if err := h.framer.WriteSettings(http2.Setting{ID: http2.SettingEnablePush, Val: 0}, http2.Setting{ID: http2.SettingInitialWindowSize, Val: windowIncrement}); err != nil {
+		return errors.Wrap(err, "could not write http2 settings")
+	}
+	if err := h.framer.WriteWindowUpdate(0, windowIncrement); err != nil {
+		return errors.Wrap(err, "could not write http2 window update")
+	}
+	if err := h.writer.Flush(); err != nil {
+		return errors.Wrap(err, "could not write http2 preface")
+	}
+
+	for {
+		frame, err := h.framer.ReadFrame()
+		if err != nil {
+			return errors.Wrap(err, "could not read http2 settings")
+		}
+		settings, ok := frame.(*http2.SettingsFrame)
+		if !ok {
+			return errors.Errorf("unexpected http2 %s frame before settings", frame.Header().Type)
+		}
+		if settings.IsAck() {
+			continue
+		}
+		if value, ok := settings.Value(http2.SettingInitialWindowSize); ok {
+			h.window = value
+		}
+		if value, ok := settings.Value(http2.SettingMaxFrameSize); ok {
+			h.frameMax = min(value, maxFrameSize)
+		}
+		if value, ok := settings.Value(http2.SettingMaxConcurrentStreams); ok && int(value) < streams {
+			return errors.Errorf("server allows %d concurrent streams but %d requests were given", value, streams)
+		}
+		if err := h.framer.WriteSettingsAck(); err != nil {
+			return errors.Wrap(err, "could not write http2 settings")
+		}
+		return nil
+	}
+}
+
+// writeRequest writes the headers and the body of the request but its
+// final frame which is returned to be sent later.
+func (h *h2Conn) writeRequest(id uint32, req *http.Request) ([]byte, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > int(h.window) {
+		return nil, errors.Errorf("request body of %d bytes exceeds http2 window of %d bytes", len(body), h.window)
+	}
+	block := h.encodeHeaders(req, len(body))
+	for first := true; first || len(block) > 0; first = false {
+		fragment := block[:min(len(block), int(h.frameMax))]
+		block = block[len(fragment):]
+		if first {
+			err = h.framer.WriteHeaders(http2.HeadersFrameParam{StreamID: id, BlockFragment: fragment, EndHeaders: len(block) == 0})
+		} else {
+			err = h.framer.WriteContinuation(id, len(block) == 0, fragment)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "could not write http2 headers")
+		}
+	}
+
+	// the last byte of the body is held back, or an empty data
+	// frame ending the stream for requests without body
+	var last []byte
+	if len(body) > 0 {
+		last = body[len(body)-1:]
+		body = body[:len(body)-1]
+	}
+	for len(body) > 0 {
+		chunk := body[:min(len(body), int(h.frameMax))]
+		body = body[len(chunk):]
+		if err := h.framer.WriteData(id, false, chunk); err != nil {
+			return nil, errors.Wrap(err, "could not write http2 data")
+		}
+	}
+
+	frame := &bytes.Buffer{}
+	if err := http2.NewFramer(frame, nil).WriteData(id, true, last); err != nil {
+		return nil, errors.Wrap(err, "could not write http2 data")
+	}
+	return frame.Bytes(), nil
+}
+
+// encodeHeaders returns the hpack encoded header block of the request
+func (h *h2Conn) encodeHeaders(req *http.Request, contentLength int) []byte {
+	h.headers.Reset()
+	authority := req.Host
+	if authority == "" {
+		authority = req.URL.Host
+	}
+	_ = h.encoder.WriteField(hpack.HeaderField{Name: ":method", Value: req.Method})
+	_ = h.encoder.WriteField(hpack.HeaderField{Name: ":scheme", Value: req.URL.Scheme})
+	_ = h.encoder.WriteField(hpack.HeaderField{Name: ":authority", Value: authority})
+	_ = h.encoder.WriteField(hpack.HeaderField{Name: ":path", Value: req.URL.RequestURI()})
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		switch name {
+		case "host", "connection", "keep-alive", "proxy-connection", "transfer-encoding", "upgrade", "content-length":
+			continue
+		}
+		for _, value := range values {
+			_ = h.encoder.WriteField(hpack.HeaderField{Name: name, Value: value})
+		}
+	}
+	if contentLength > 0 {
+		_ = h.encoder.WriteField(hpack.HeaderField{Name: "content-length", Value: strconv.Itoa(contentLength)})
+	}
+	return append([]byte(nil), h.headers.Bytes()...)
+}
+
+// ping sends a ping and waits for its acknowledgement
+func (h *h2Conn) ping() error {
+	data := [8]byte{'v', 'u', 'l', 'm', 'a', 'p'}
+	if err := h.framer.WritePing(false, data); err != nil {
+		return errors.Wrap(err, "could not write http2 ping")
+	}
+	if err := h.writer.Flush(); err != nil {
+		return errors.Wrap(err, "could not write http2 frames")
+	}
+	for {
+		frame, err := h.framer.ReadFrame()
+		if err != nil {
+			return errors.Wrap(err, "could not read http2 ping")
+		}
+		if ping, ok := frame.(*http2.PingFrame); ok && ping.IsAck() && ping.Data == data {
+			return nil
+		}
+		// responses of streams the server did not wait for are read later
+		if err := h.control(frame); err != nil {
+			return err
+		}
+		h.pending = append(h.pending, copyFrame(frame))
+	}
+}
+
+// control handles the connection level frames
+func (h *h2Conn) control(frame http2.Frame) error {
+	var err error
+	switch frame := frame.(type) {
+	case *http2.SettingsFrame:
+		if !frame.IsAck() {
+			err = h.framer.WriteSettingsAck()
+		}
+	case *http2.PingFrame:
+		if !frame.IsAck() {
+			err = h.framer.WritePing(true, frame.Data)
+		}
+	case *http2.WindowUpdateFrame:
+		if frame.StreamID == 0 {
+			h.connWindow += int64(frame.Increment)
+		}
+		return nil
+	default:
+		return nil
+	}
+	if err == nil {
+		err = h.writer.Flush()
+	}
+	return errors.Wrap(err, "could not write http2 frame")
+}
+
+// readResponses reads the responses of the streams of the requests
+func (h *h2Conn) readResponses(requests []*http.Request) []*Result {
+	streams := make(map[uint32]*h2Stream, len(requests))
+	for i, req := range requests {
+		streams[streamID(i)] = &h2Stream{request: req, body: &bytes.Buffer{}}
+	}
+	remaining := len(requests)
+
+	next := func() (http2.Frame, error) {
+		if len(h.pending) > 0 {
+			frame := h.pending[0]
+			h.pending = h.pending[1:]
+			return frame, nil
+		}
+		frame, err := h.framer.ReadFrame()
+		if err == nil {
+			err = h.control(frame)
+		}
+		return frame, err
+	}
+	for remaining > 0 {
+		frame, err := next()
+		if err != nil {
+			for _, stream := range streams {
+				stream.fail(errors.Wrap(err, "could not read http2 response"))
+			}
+			break
+		}
+		if goAway, ok := frame.(*http2.GoAwayFrame); ok {
+			for id, stream := range streams {
+				if id > goAway.LastStreamID && stream.fail(errors.Errorf("server closed connection with %s", goAway.ErrCode)) {
+					remaining--
+				}
+			}
+			continue
+		}
+		stream, ok := streams[frame.Header().StreamID]
+		if !ok || stream.done {
+			continue
+		}
+		if stream.handle(h, frame) {
+			remaining--
+		}
+	}
+
+	results := make([]*Result, len(requests))
+	for i := range requests {
+		results[i] = streams[streamID(i)].result()
+	}
+	return results
+}
+
+// h2Stream is the response of a stream being read
+type h2Stream struct {
+	request *http.Request
+	status  int
+	header  http.Header
+	body    *bytes.Buffer
+	err     error
+	done    bool
+}
+
+// handle handles a frame of the stream returning true once the stream ended
+func (s *h2Stream) handle(h *h2Conn, frame http2.Frame) bool {
+	switch frame := frame.(type) {
+	case *http2.MetaHeadersFrame:
+		status, err := strconv.Atoi(frame.PseudoValue("status"))
+		if err != nil {
+			return s.fail(errors.Wrap(err, "could not parse http2 status"))
+		}
+		// informational responses precede the final response
+		if status >= 100 && status < 200 && status != http.StatusSwitchingProtocols {
+			return false
+		}
+		if s.header == nil {
+			s.status = status
+			s.header = http.Header{}
+		}
+		for _, field := range frame.RegularFields() {
+			s.header.Add(http.CanonicalHeaderKey(field.Name), field.Value)
+		}
+		if frame.StreamEnded() {
+			s.done = true
+		}
+	case dataFrame:
+		s.body.Write(frame.Data())
+		if length := uint32(len(frame.Data())); length > 0 {
+			_ = h.framer.WriteWindowUpdate(frame.Header().StreamID, length)
+			_ = h.writer.Flush()
+		}
+		if frame.StreamEnded() {
+			s.done = true
+		}
+	case *http2.RSTStreamFrame:
+		return s.fail(errors.Errorf("server reset stream with %s", frame.ErrCode))
+	}
+	return s.done
+}
+
+// fail ends the stream with the error returning true if it was not ended
+func (s *h2Stream) fail(err error) bool {
+	if s.done {
+		return false
+	}
+	s.done = true
+	s.err = err
+	return true
+}
+
+// result returns the response of the stream
+func (s *h2Stream) result() *Result {
+	if s.err != nil {
+		return &Result{Err: s.err}
+	}
+	if s.header == nil {
+		return &Result{Err: errors.New("server sent no http2 response headers")}
+	}
+	return &Result{Response: &http.Response{
+		Status:        fmt.Sprintf("%d %s", s.status, http.StatusText(s.status)),
+		StatusCode:    s.status,
+		Proto:         "HTTP/2.0",
+		ProtoMajor:    2,
+		Header:        s.header,
+		Body:          io.NopCloser(bytes.NewReader(s.body.Bytes())),
+		ContentLength: int64(s.body.Len()),
+		Request:       s.request,
+	}}
+}
+
+// copyFrame returns a copy of the frame whose data stays
+// valid after the next frame is read.
+func copyFrame(frame http2.Frame) http2.Frame {
+	data, ok := frame.(*http2.DataFrame)
+	if !ok {
+		return frame
+	}
+	return &copiedDataFrame{DataFrame: data, data: append([]byte(nil), data.Data()...)}
+}
+
+// dataFrame is implemented by the data frames read from the connection
+// and by the copies of the ones read while waiting for the ping.
+type dataFrame interface {
+	http2.Frame
+	Data() []byte
+	StreamEnded() bool
+}
+
+// copiedDataFrame is a data frame with a copy of its data
+type copiedDataFrame struct {
+	*http2.DataFrame
+	data []byte
+}
+
+// Data returns the copy of the data of the frame
+func (c *copiedDataFrame) Data() []byte {
+	return c.data
+}
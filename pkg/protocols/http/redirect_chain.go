@@ -0,0 +1,68 @@
+package http
+
+import (
+	"fmt"
+	"net/http/httputil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/output"
+)
+
+// redirectChain returns the hops of the redirect chain of the dumped responses,
+// which are ordered from the final response to the first one, with the
+// durations of the hops ordered from the first request to the final response.
+func redirectChain(responses []redirectedResponse, durations []time.Duration) []output.RedirectHop {
+	chain := make([]output.RedirectHop, 0, len(responses))
+	for i := len(responses) - 1; i >= 0; i-- {
+		resp := responses[i].resp
+		if resp == nil {
+			continue
+		}
+		hop := output.RedirectHop{
+			StatusCode:      resp.StatusCode,
+			Location:        resp.Header.Get("Location"),
+			ResponseHeaders: string(responses[i].headers),
+		}
+		if resp.Request != nil {
+			hop.Method = resp.Request.Method
+			hop.URL = resp.Request.URL.String()
+			if dumped, err := httputil.DumpRequestOut(resp.Request, false); err == nil {
+				hop.Request = string(dumped)
+			}
+		}
+		if index := len(chain); index < len(durations) {
+			hop.Duration = durations[index].Seconds()
+		}
+		chain = append(chain, hop)
+	}
+	return chain
+}
+
+// redirectChainToDSLMap returns the matcher variables of the hops of a redirect chain
+func redirectChainToDSLMap(chain []output.RedirectHop) map[string]interface{} {
+	data := make(map[string]interface{}, 3+4*len(chain))
+	data["redirect_count"] = max(len(chain)-1, 0)
+
+	lines := make([]string, 0, len(chain))
+	for i, hop := range chain {
+		line := strconv.Itoa(hop.StatusCode) + " " + hop.URL
+		if hop.Location != "" {
+			line += " -> " + hop.Location
+		}
+		lines = append(lines, line)
+
+		data[fmt.Sprintf("redirect_url_%d", i+1)] = hop.URL
+		data[fmt.Sprintf("redirect_status_code_%d", i+1)] = hop.StatusCode
+		data[fmt.Sprintf("redirect_location_%d", i+1)] = hop.Location
+		data[fmt.Sprintf("redirect_duration_%d", i+1)] = hop.Duration
+	}
+	data["redirect_chain"] = strings.Join(lines, "\n")
+
+	// the hops are only reported in results if redirects were followed
+	if len(chain) > 1 {
+		data["redirect-chain"] = chain
+	}
+	return data
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedirectChain(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			http.Redirect(w, r, "/login", http.StatusMovedPermanently)
+		case "/login":
+			http.Redirect(w, r, "/home", http.StatusFound)
+		default:
+			_, _ = w.Write([]byte("home"))
+		}
+	}))
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL)
+	require.Nil(t, err, "could not send request")
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.Nil(t, err, "could not read response body")
+
+	responses, err := dumpResponseWithRedirectChain(resp, body)
+	require.Nil(t, err, "could not dump redirect chain")
+	chain := redirectChain(responses, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second})
+	require.Len(t, chain, 3, "could not get hops of redirect chain")
+
+	require.Equal(t, ts.URL, chain[0].URL, "could not get url of first hop")
+	require.Equal(t, http.MethodGet, chain[0].Method, "could not get method of first hop")
+	require.Equal(t, http.StatusMovedPermanently, chain[0].StatusCode, "could not get status code of first hop")
+	require.Equal(t, "/login", chain[0].Location, "could not get location of first hop")
+	require.Equal(t, float64(1), chain[0].Duration, "could not get duration of first hop")
+	require.Contains(t, chain[0].ResponseHeaders, "Location: /login", "could not get response headers of first hop")
+	require.Contains(t, chain[1].Request, "GET /login HTTP/1.1", "could not get request of second hop")
+	require.Equal(t, http.StatusOK, chain[2].StatusCode, "could not get status code of final hop")
+	require.Empty(t, chain[2].Location, "could not get location of final hop")
+
+	data := redirectChainToDSLMap(chain)
+	require.Equal(t, 2, data["redirect_count"], "could not get redirect count")
+	require.Equal(t, "/home", data["redirect_location_2"], "could not get location of second hop")
+	require.Equal(t, http.StatusFound, data["redirect_status_code_2"], "could not get status code of second hop")
+	require.Equal(t, "301 "+ts.URL+" -> /login\n302 "+ts.URL+"/login -> /home\n200 "+ts.URL+"/home", data["redirect_chain"], "could not get redirect chain")
+	require.Len(t, data["redirect-chain"], 3, "could not report redirect chain")
+
+	data = redirectChainToDSLMap(chain[2:])
+	require.Equal(t, 0, data["redirect_count"], "could not get redirect count without redirects")
+	require.NotContains(t, data, "redirect-chain", "could not skip reporting response without redirects")
+}
@@ -37,6 +37,7 @@ import (
 	templateTypes "github.com/khulnasoft-lab/vulmap/pkg/templates/types"
 	"github.com/khulnasoft-lab/vulmap/pkg/types"
 	"github.com/khulnasoft-lab/rawhttp"
+	"github.com/projectdiscovery/retryablehttp-go"
 	"github.com/khulnasoft-lab/utils/reader"
 	sliceutil "github.com/khulnasoft-lab/utils/slice"
 	stringsutil "github.com/khulnasoft-lab/utils/strings"
@@ -100,6 +101,19 @@ func (request *Request) executeRaceRequest(input *contextargs.Context, previous
 		generatedRequests = append(generatedRequests, generatedRequest)
 	}
 
+	// the requests of the race are sent together so they are rate limited
+	// once and hold the slots of the host until all of them completed
+	request.options.HostRateLimiter.Take(reqURL)
+	release := request.options.HostConcurrency.AcquireN(reqURL, len(generatedRequests))
+	defer release()
+
+	// Send the requests synchronised with the race strategy if specified
+	if request.RaceStrategy != "" {
+		if err := request.sendRaceRequests(input, generatedRequests); err != nil {
+			return err
+		}
+	}
+
 	wg := sync.WaitGroup{}
 	var requestErr error
 	mutex := &sync.Mutex{}
@@ -151,7 +165,10 @@ func (request *Request) executeParallelHTTP(input *contextargs.Context, dynamicV
 		go func(httpRequest *generatedRequest) {
 			defer swg.Done()
 
+			request.options.HostRateLimiter.Take(input.MetaInput.Input)
 			request.options.RateLimiter.Take()
+			release := request.options.HostConcurrency.Acquire(input.MetaInput.Input)
+			defer release()
 
 			previous := make(map[string]interface{})
 			err := request.executeRequest(input, httpRequest, previous, false, callback, 0)
@@ -217,6 +234,10 @@ func (request *Request) executeTurboHTTP(input *contextargs.Context, dynamicValu
 		go func(httpRequest *generatedRequest) {
 			defer swg.Done()
 
+			request.options.HostRateLimiter.Take(input.MetaInput.Input)
+			release := request.options.HostConcurrency.Acquire(input.MetaInput.Input)
+			defer release()
+
 			err := request.executeRequest(input, httpRequest, previous, false, callback, 0)
 			mutex.Lock()
 			if err != nil {
@@ -239,18 +260,50 @@ func (request *Request) executeFuzzingRule(input *contextargs.Context, previous
 			return errors.Wrap(err, "could not parse url")
 		}
 	}
+	// baseline is the response of the current base request for diff matchers
+	var baseline output.InternalEvent
+	fetchBaseline := func(baseRequest *retryablehttp.Request) {
+		baseline = nil
+		if !hasDiffMatchers(request.CompiledOperators) {
+			return
+		}
+		var err error
+		if baseline, err = request.fetchBaseline(baseRequest); err != nil {
+			gologger.Verbose().Msgf("[%s] Could not fetch baseline for %s: %s\n", request.options.TemplateID, input.MetaInput.Input, err)
+		}
+	}
+
 	fuzzRequestCallback := func(gr fuzz.GeneratedRequest) bool {
 		hasInteractMatchers := interactsh.HasMatchers(request.CompiledOperators)
 		hasInteractMarkers := len(gr.InteractURLs) > 0
 		if request.options.HostErrorsCache != nil && request.options.HostErrorsCache.Check(input.MetaInput.Input) {
 			return false
 		}
+		if len(gr.DiscoveredParameters) > 0 {
+			return request.reportDiscoveredParameters(input, gr, callback)
+		}
+		request.options.HostRateLimiter.Take(input.MetaInput.Input)
 		request.options.RateLimiter.Take()
+		release := request.options.HostConcurrency.Acquire(input.MetaInput.Input)
+		defer release()
 		req := &generatedRequest{
 			request:        gr.Request,
 			dynamicValues:  gr.DynamicValues,
 			interactshURLs: gr.InteractURLs,
 			original:       request,
+			baseline:       baseline,
+		}
+		// record the (decoded) paths of fuzzed values in the result metadata
+		if len(gr.InjectionPoints) > 0 {
+			req.meta = map[string]interface{}{"fuzz_injection_points": strings.Join(gr.InjectionPoints, ",")}
+		}
+		// expose the analysis of confirmed injection points to matchers and results
+		if gr.AnalyzerResult != nil {
+			req.meta = generators.MergeMaps(req.meta, map[string]interface{}{
+				"analyzer":         gr.AnalyzerResult.Matched,
+				"analyzer_name":    gr.AnalyzerResult.Name,
+				"analyzer_details": gr.AnalyzerResult.Details,
+			})
 		}
 		var gotMatches bool
 		requestErr := request.executeRequest(input, req, gr.DynamicValues, hasInteractMatchers, func(event *output.InternalWrappedEvent) {
@@ -292,6 +345,65 @@ func (request *Request) executeFuzzingRule(input *contextargs.Context, previous
 		return true
 	}
 
+	// sendRuleRequest sends the analysis and discovery requests of fuzzing rules
+	sendRuleRequest := func(req *retryablehttp.Request) (*fuzz.Response, error) {
+		request.setCustomHeaders(&generatedRequest{request: req})
+		request.options.HostRateLimiter.Take(req.URL.Host)
+		request.options.RateLimiter.Take()
+		release := request.options.HostConcurrency.Acquire(req.URL.Host)
+		defer release()
+
+		start := time.Now()
+		resp, err := request.httpClient.Do(req)
+		request.options.HostRateLimiter.Observe(req.URL.Host, resp, err)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		var bodyReader io.Reader = resp.Body
+		if request.options.Options.ResponseReadSize != 0 {
+			bodyReader = io.LimitReader(resp.Body, int64(request.options.Options.ResponseReadSize))
+		}
+		body, err := io.ReadAll(bodyReader)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read http body")
+		}
+		return &fuzz.Response{StatusCode: resp.StatusCode, Body: string(body), Duration: time.Since(start)}, nil
+	}
+
+	// If the input carries a complete request (openapi, etc), use it
+	// as the base request for fuzzing instead of generating one.
+	if input.MetaInput.ReqResp != nil {
+		baseRequest, err := input.MetaInput.ReqResp.BuildRequest()
+		if err != nil {
+			return errors.Wrap(err, "could not build base request")
+		}
+		fetchBaseline(baseRequest)
+		for _, rule := range request.Fuzzing {
+			ruleInput := &fuzz.ExecuteRuleInput{
+				Input:       input,
+				Callback:    fuzzRequestCallback,
+				Values:      generators.MergeMaps(previous),
+				BaseRequest: baseRequest,
+				SendRequest: sendRuleRequest,
+			}
+			err = rule.Execute(ruleInput)
+			// discovered parameters are fuzzed by the following rules
+			baseRequest = ruleInput.BaseRequest
+			if err == types.ErrNoMoreRequests {
+				return nil
+			}
+			if errors.Is(err, fuzz.ErrRuleNotApplicable) {
+				continue
+			}
+			if err != nil {
+				return errors.Wrap(err, "could not execute rule")
+			}
+		}
+		return nil
+	}
+
 	// Iterate through all requests for template and queue them for fuzzing
 	generator := request.newGenerator(true)
 	for {
@@ -303,16 +415,25 @@ func (request *Request) executeFuzzingRule(input *contextargs.Context, previous
 		if err != nil {
 			continue
 		}
+		fetchBaseline(generated.request)
+		baseRequest := generated.request
 		for _, rule := range request.Fuzzing {
-			err = rule.Execute(&fuzz.ExecuteRuleInput{
+			ruleInput := &fuzz.ExecuteRuleInput{
 				Input:       input,
 				Callback:    fuzzRequestCallback,
 				Values:      generated.dynamicValues,
-				BaseRequest: generated.request,
-			})
+				BaseRequest: baseRequest,
+				SendRequest: sendRuleRequest,
+			}
+			err = rule.Execute(ruleInput)
+			// discovered parameters are fuzzed by the following rules
+			baseRequest = ruleInput.BaseRequest
 			if err == types.ErrNoMoreRequests {
 				return nil
 			}
+			if errors.Is(err, fuzz.ErrRuleNotApplicable) {
+				continue
+			}
 			if err != nil {
 				return errors.Wrap(err, "could not execute rule")
 			}
@@ -357,7 +478,11 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 		executeFunc := func(data string, payloads, dynamicValue map[string]interface{}) (bool, error) {
 			hasInteractMatchers := interactsh.HasMatchers(request.CompiledOperators)
 
+			request.options.HostRateLimiter.Take(input.MetaInput.Input)
 			request.options.RateLimiter.Take()
+			// the slot is acquired before the timeout so that waiting for it does not time out requests
+			release := request.options.HostConcurrency.Acquire(input.MetaInput.Input)
+			defer release()
 
 			ctx := request.newContext(input)
 			ctxWithTimeout, cancel := context.WithTimeout(ctx, time.Duration(request.options.Options.Timeout)*time.Second)
@@ -466,7 +591,10 @@ var errStopExecution = errors.New("stop execution due to unresolved variables")
 
 // executeRequest executes the actual generated request and returns error if occurred
 func (request *Request) executeRequest(input *contextargs.Context, generatedRequest *generatedRequest, previousEvent output.InternalEvent, hasInteractMatchers bool, callback protocols.OutputEventCallback, requestCount int) error {
-	request.setCustomHeaders(generatedRequest)
+	// requests sent with a race strategy were decorated before being sent
+	if generatedRequest.raceResult == nil {
+		request.setCustomHeaders(generatedRequest)
+	}
 
 	// Try to evaluate any payloads before replacement
 	finalMap := generators.MergeMaps(generatedRequest.dynamicValues, generatedRequest.meta)
@@ -545,6 +673,7 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 	}
 	var formedURL string
 	var hostname string
+	var redirectTimings *httpclientpool.RedirectTimings
 	timeStart := time.Now()
 	if generatedRequest.original.Pipeline {
 		// if request is a pipeline request, use the pipelined client
@@ -600,7 +729,9 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 				fromCache = false
 			}
 		}
-		if resp == nil {
+		if resp == nil && generatedRequest.raceResult != nil {
+			resp, err = generatedRequest.raceResult.Response, generatedRequest.raceResult.Err
+		} else if resp == nil {
 			if errSignature := request.handleSignature(generatedRequest); errSignature != nil {
 				return errSignature
 			}
@@ -615,9 +746,18 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 				}
 				httpclient = client
 			}
+			var ctx context.Context
+			ctx, redirectTimings = httpclientpool.WithRedirectTimings(generatedRequest.request.Context())
+			generatedRequest.request.Request = generatedRequest.request.Request.WithContext(ctx)
 			resp, err = httpclient.Do(generatedRequest.request)
+			if err == nil {
+				resp, err = request.retryAuthChallenge(httpclient, generatedRequest, resp)
+			}
 		}
 	}
+	if !fromCache {
+		request.options.HostRateLimiter.Observe(input.MetaInput.Input, resp, err)
+	}
 	// use request url as matched url if empty
 	if formedURL == "" {
 		formedURL = input.MetaInput.Input
@@ -735,6 +875,7 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 	} else {
 		dumpedResponse = []redirectedResponse{{resp: resp, fullResponse: dumpedResponseHeaders, headers: dumpedResponseHeaders}}
 	}
+	redirectData := redirectChainToDSLMap(redirectChain(dumpedResponse, redirectTimings.Durations(timeStart, timeStart.Add(duration), len(dumpedResponse))))
 
 	// if vulmap-project is enabled store the response if not previously done
 	if request.options.ProjectFile != nil && !fromCache {
@@ -774,6 +915,12 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 			hostname = hostname[:i]
 		}
 		outputEvent["curl-command"] = curlCommand
+		for k, v := range redirectData {
+			outputEvent[k] = v
+		}
+		for k, v := range generatedRequest.baseline {
+			outputEvent[k] = v
+		}
 		if input.MetaInput.CustomIP != "" {
 			outputEvent["ip"] = input.MetaInput.CustomIP
 		} else {
@@ -825,29 +972,35 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 
 // handleSignature of the http request
 func (request *Request) handleSignature(generatedRequest *generatedRequest) error {
-	switch request.Signature.Value {
-	case AWSSignature:
-		var awsSigner signer.Signer
-		allvars := generators.MergeMaps(request.options.Options.Vars.AsMap(), generatedRequest.dynamicValues)
-		awsopts := signer.AWSOptions{
-			AwsID:          types.ToString(allvars["aws-id"]),
-			AwsSecretToken: types.ToString(allvars["aws-secret"]),
-		}
-		awsSigner, err := signerpool.Get(request.options.Options, &signerpool.Configuration{SignerArgs: &awsopts})
+	definition, ok := signer.Get(request.Signature.Value.String())
+	if !ok {
+		return nil
+	}
+	allvars := generators.MergeMaps(definition.DefaultVars, request.options.Options.Vars.AsMap(), generatedRequest.dynamicValues)
+	signerArgs, err := definition.Args(allvars)
+	if err != nil {
+		return err
+	}
+	requestSigner, err := signerpool.Get(request.options.Options, &signerpool.Configuration{SignerArgs: signerArgs})
+	if err != nil {
+		return err
+	}
+	// signers hashing the body read it from GetBody to keep the body reusable
+	if req := generatedRequest.request.Request; req.GetBody == nil && req.Body != nil {
+		body, err := generatedRequest.request.BodyBytes()
 		if err != nil {
 			return err
 		}
-		ctx := signer.GetCtxWithArgs(allvars, signer.AwsDefaultVars)
-		err = awsSigner.SignHTTP(ctx, generatedRequest.request.Request)
-		if err != nil {
-			return err
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
 		}
 	}
-
-	return nil
+	ctx := signer.GetCtxWithArgs(allvars, definition.DefaultVars)
+	return requestSigner.SignHTTP(ctx, generatedRequest.request.Request)
 }
 
 // setCustomHeaders sets the custom headers for generated request
+// along with the auth of the request target.
 func (request *Request) setCustomHeaders(req *generatedRequest) {
 	for k, v := range request.customHeaders {
 		if req.rawRequest != nil {
@@ -860,6 +1013,7 @@ func (request *Request) setCustomHeaders(req *generatedRequest) {
 			}
 		}
 	}
+	request.applyAuth(req)
 }
 
 const CRLF = "\r\n"
@@ -906,13 +1060,11 @@ func createResponseHexDump(event *output.InternalWrappedEvent, response string,
 }
 
 func (request *Request) pruneSignatureInternalValues(maps ...map[string]interface{}) {
-	var signatureFieldsToSkip map[string]interface{}
-	switch request.Signature.Value {
-	case AWSSignature:
-		signatureFieldsToSkip = signer.AwsInternalOnlyVars
-	default:
+	definition, ok := signer.Get(request.Signature.Value.String())
+	if !ok {
 		return
 	}
+	signatureFieldsToSkip := definition.InternalVars
 
 	for _, m := range maps {
 		for fieldName := range signatureFieldsToSkip {
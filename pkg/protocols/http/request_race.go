@@ -0,0 +1,54 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http/httpclientpool"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http/race"
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+)
+
+// sendRaceRequests sends the race condition requests synchronised with the
+// race strategy of the template storing the responses in the generated requests
+// to be handled by executeRequest. Requests without stored responses are sent
+// with the default race condition coordination.
+func (request *Request) sendRaceRequests(input *contextargs.Context, generatedRequests []*generatedRequest) error {
+	if types.ProxyURL != "" {
+		gologger.Warning().Msgf("[%s] Could not use race strategy %s with http proxy, using default race coordination", request.options.TemplateID, request.RaceStrategy)
+		return nil
+	}
+
+	dial := httpclientpool.Dialer.Dial
+	if types.ProxySocksURL != "" {
+		dialContext, err := httpclientpool.SocksDialContext(types.ProxySocksURL)
+		if err != nil {
+			return err
+		}
+		dial = dialContext
+	}
+
+	requests := make([]*http.Request, 0, len(generatedRequests))
+	for _, generatedRequest := range generatedRequests {
+		request.setCustomHeaders(generatedRequest)
+		if err := request.handleSignature(generatedRequest); err != nil {
+			return err
+		}
+		requests = append(requests, generatedRequest.request.Request)
+	}
+
+	results, err := race.Send(request.newContext(input), request.RaceStrategy, requests, &race.Options{
+		Dial:    dial,
+		SNI:     request.options.Options.SNI,
+		Timeout: time.Duration(request.options.Options.Timeout) * time.Second,
+	})
+	if err != nil {
+		return err
+	}
+	for i, result := range results {
+		generatedRequests[i].raceResult = result
+	}
+	return nil
+}
@@ -10,40 +10,36 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http/signer"
 )
 
-// SignatureType is the type of signature
-type SignatureType int
+// SignatureType is the name of a signer of the signer registry
+type SignatureType string
 
 // Supported values for the SignatureType
 const (
-	AWSSignature SignatureType = iota + 1
-	signatureLimit
+	AWSSignature            SignatureType = "AWS"
+	AzureSharedKeySignature SignatureType = "AZURE-SHARED-KEY"
+	GCPHMACSignature        SignatureType = "GCP-HMAC"
+	HMACSignature           SignatureType = "HMAC"
 )
 
-// signatureTypeMappings is a table for conversion of signature type from string.
-var signatureTypeMappings = map[SignatureType]string{
-	AWSSignature: "AWS",
-}
-
+// GetSupportedSignaturesTypes returns the signatures of the registered signers
 func GetSupportedSignaturesTypes() []SignatureType {
 	var result []SignatureType
-	for index := SignatureType(1); index < signatureLimit; index++ {
-		result = append(result, index)
+	for _, name := range signer.Names() {
+		result = append(result, SignatureType(name))
 	}
 	return result
 }
 
 func toSignatureType(valueToMap string) (SignatureType, error) {
-	normalizedValue := normalizeValue(valueToMap)
-	for key, currentValue := range signatureTypeMappings {
-		if normalizedValue == currentValue {
-			return key, nil
-		}
+	definition, ok := signer.Get(valueToMap)
+	if !ok {
+		return "", errors.New("invalid signature type: " + valueToMap)
 	}
-	return -1, errors.New("invalid signature type: " + valueToMap)
+	return SignatureType(definition.Name), nil
 }
 
 func (t SignatureType) String() string {
-	return signatureTypeMappings[t]
+	return string(t)
 }
 
 // SignatureTypeHolder is used to hold internal type of the signature
@@ -104,18 +100,16 @@ var ErrNoIgnoreList = errors.New("unknown signature types")
 
 // GetVariablesNamesSkipList depending on the signature type
 func GetVariablesNamesSkipList(signature SignatureType) map[string]interface{} {
-	switch signature {
-	case AWSSignature:
-		return signer.AwsSkipList
-	default:
-		return nil
+	if definition, ok := signer.Get(signature.String()); ok {
+		return definition.SkipVars
 	}
+	return nil
 }
 
 // GetDefaultSignerVars returns the default signer variables
 func GetDefaultSignerVars(signatureType SignatureType) map[string]interface{} {
-	if signatureType == AWSSignature {
-		return signer.AwsDefaultVars
+	if definition, ok := signer.Get(signatureType.String()); ok && definition.DefaultVars != nil {
+		return definition.DefaultVars
 	}
 	return map[string]interface{}{}
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/khulnasoft-lab/gologger"
 	errorutil "github.com/khulnasoft-lab/utils/errors"
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
 )
 
 const defaultEmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
@@ -39,6 +40,14 @@ func (a *AWSOptions) Validate() error {
 	return nil
 }
 
+// awsArgs returns the aws signer arguments from the variables of the request
+func awsArgs(vars map[string]interface{}) (SignerArgs, error) {
+	return &AWSOptions{
+		AwsID:          types.ToString(vars["aws-id"]),
+		AwsSecretToken: types.ToString(vars["aws-secret"]),
+	}, nil
+}
+
 // AWS v4 signer
 type AWSSigner struct {
 	creds   *aws.Credentials
@@ -0,0 +1,155 @@
+package signer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+)
+
+// AzureSharedKeyOptions contains the azure storage account and its key
+type AzureSharedKeyOptions struct {
+	Account string
+	Key     string
+	Version string
+}
+
+// Validate checks that the account is set and the key is base64 encoded
+func (a *AzureSharedKeyOptions) Validate() error {
+	if a.Account == "" {
+		return errors.New("azure account cannot be empty")
+	}
+	if _, err := base64.StdEncoding.DecodeString(a.Key); err != nil || a.Key == "" {
+		return errors.New("azure key must be a base64 encoded key")
+	}
+	return nil
+}
+
+// azureSharedKeyArgs returns the azure signer arguments from the variables of the request
+func azureSharedKeyArgs(vars map[string]interface{}) (SignerArgs, error) {
+	return &AzureSharedKeyOptions{
+		Account: types.ToString(vars["azure-account"]),
+		Key:     types.ToString(vars["azure-key"]),
+		Version: types.ToString(vars["azure-version"]),
+	}, nil
+}
+
+// AzureSharedKeySigner signs requests to azure storage services with the
+// Shared Key authorization scheme.
+type AzureSharedKeySigner struct {
+	key     []byte
+	options *AzureSharedKeyOptions
+}
+
+// NewAzureSharedKeySigner creates a new azure shared key signer
+func NewAzureSharedKeySigner(opts *AzureSharedKeyOptions) (*AzureSharedKeySigner, error) {
+	key, err := base64.StdEncoding.DecodeString(opts.Key)
+	if err != nil {
+		return nil, err
+	}
+	return &AzureSharedKeySigner{key: key, options: opts}, nil
+}
+
+// SignHTTP sets the SharedKey authorization header of the request, adding
+// the x-ms-date and x-ms-version headers it covers if they are missing.
+func (a *AzureSharedKeySigner) SignHTTP(ctx context.Context, request *http.Request) error {
+	if request.Header.Get("x-ms-date") == "" {
+		request.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	}
+	if request.Header.Get("x-ms-version") == "" && a.options.Version != "" {
+		request.Header.Set("x-ms-version", a.options.Version)
+	}
+
+	signature := base64.StdEncoding.EncodeToString(hmacSum(sha256.New, a.key, []byte(a.stringToSign(request))))
+	request.Header.Set("Authorization", "SharedKey "+a.options.Account+":"+signature)
+	return nil
+}
+
+// stringToSign returns the string to sign of the request
+func (a *AzureSharedKeySigner) stringToSign(request *http.Request) string {
+	contentLength := ""
+	if request.ContentLength > 0 {
+		contentLength = strconv.FormatInt(request.ContentLength, 10)
+	}
+	// the date header is empty since x-ms-date is always set
+	parts := []string{
+		request.Method,
+		request.Header.Get("Content-Encoding"),
+		request.Header.Get("Content-Language"),
+		contentLength,
+		request.Header.Get("Content-MD5"),
+		request.Header.Get("Content-Type"),
+		"",
+		request.Header.Get("If-Modified-Since"),
+		request.Header.Get("If-Match"),
+		request.Header.Get("If-None-Match"),
+		request.Header.Get("If-Unmodified-Since"),
+		request.Header.Get("Range"),
+	}
+	return strings.Join(parts, "\n") + "\n" + a.canonicalizedHeaders(request) + a.canonicalizedResource(request)
+}
+
+// canonicalizedHeaders returns the sorted x-ms- headers of the request
+func (a *AzureSharedKeySigner) canonicalizedHeaders(request *http.Request) string {
+	var names []string
+	for name := range request.Header {
+		if name = strings.ToLower(name); strings.HasPrefix(name, "x-ms-") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	builder := &strings.Builder{}
+	for _, name := range names {
+		builder.WriteString(name)
+		builder.WriteString(":")
+		builder.WriteString(strings.TrimSpace(strings.Join(request.Header.Values(name), ",")))
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// canonicalizedResource returns the account, path and sorted query parameters of the request
+func (a *AzureSharedKeySigner) canonicalizedResource(request *http.Request) string {
+	builder := &strings.Builder{}
+	builder.WriteString("/" + a.options.Account)
+	path := request.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	builder.WriteString(path)
+
+	query := request.URL.Query()
+	params := make(map[string][]string, len(query))
+	var names []string
+	for name, values := range query {
+		name = strings.ToLower(name)
+		if _, ok := params[name]; !ok {
+			names = append(names, name)
+		}
+		params[name] = append(params[name], values...)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		values := params[name]
+		sort.Strings(values)
+		builder.WriteString("\n" + name + ":" + strings.Join(values, ","))
+	}
+	return builder.String()
+}
+
+var AzureDefaultVars = map[string]interface{}{
+	"azure-version": "2021-08-06",
+}
+
+var AzureInternalOnlyVars = map[string]interface{}{
+	"azure-account": struct{}{},
+	"azure-key":     struct{}{},
+}
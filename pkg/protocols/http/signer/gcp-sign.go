@@ -0,0 +1,163 @@
+package signer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+)
+
+const gcpAlgorithm = "GOOG4-HMAC-SHA256"
+
+// GCPHMACOptions contains the HMAC key of a google cloud storage service account
+type GCPHMACOptions struct {
+	AccessID string
+	Secret   string
+	Region   string
+}
+
+// Validate checks that the access id and the secret of the hmac key are set
+func (g *GCPHMACOptions) Validate() error {
+	if g.AccessID == "" {
+		return errors.New("gcp access id cannot be empty")
+	}
+	if g.Secret == "" {
+		return errors.New("gcp secret cannot be empty")
+	}
+	return nil
+}
+
+// gcpHMACArgs returns the gcp signer arguments from the variables of the request
+func gcpHMACArgs(vars map[string]interface{}) (SignerArgs, error) {
+	return &GCPHMACOptions{
+		AccessID: types.ToString(vars["gcp-access-id"]),
+		Secret:   types.ToString(vars["gcp-secret"]),
+		Region:   types.ToString(vars["gcp-region"]),
+	}, nil
+}
+
+// GCPHMACSigner signs requests to the google cloud storage XML API with HMAC
+// keys using the V4 signing process of the interoperability mode.
+type GCPHMACSigner struct {
+	options *GCPHMACOptions
+}
+
+// NewGCPHMACSigner creates a new gcp hmac signer
+func NewGCPHMACSigner(opts *GCPHMACOptions) (*GCPHMACSigner, error) {
+	return &GCPHMACSigner{options: opts}, nil
+}
+
+// SignHTTP signs the request at the current time
+func (g *GCPHMACSigner) SignHTTP(ctx context.Context, request *http.Request) error {
+	return g.sign(request, time.Now().UTC())
+}
+
+// sign sets the x-goog-date, x-goog-content-sha256 and GOOG4-HMAC-SHA256
+// authorization headers of the request signed at the time.
+func (g *GCPHMACSigner) sign(request *http.Request, now time.Time) error {
+	body, err := requestBody(request)
+	if err != nil {
+		return err
+	}
+	payloadHash := sha256.Sum256(body)
+	datetime := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	region := g.options.Region
+	if region == "" {
+		region = "auto"
+	}
+	scope := date + "/" + region + "/storage/goog4_request"
+
+	request.Header.Set("x-goog-date", datetime)
+	request.Header.Set("x-goog-content-sha256", hex.EncodeToString(payloadHash[:]))
+	signedHeaders, canonicalHeaders := g.canonicalHeaders(request)
+
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		canonicalURI(request.URL),
+		canonicalQuery(request.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{gcpAlgorithm, datetime, scope, hex.EncodeToString(canonicalHash[:])}, "\n")
+
+	key := []byte("GOOG4" + g.options.Secret)
+	for _, part := range []string{date, region, "storage", "goog4_request"} {
+		key = hmacSum(sha256.New, key, []byte(part))
+	}
+	signature := hex.EncodeToString(hmacSum(sha256.New, key, []byte(stringToSign)))
+
+	request.Header.Set("Authorization", gcpAlgorithm+" Credential="+g.options.AccessID+"/"+scope+", SignedHeaders="+signedHeaders+", Signature="+signature)
+	return nil
+}
+
+// canonicalHeaders returns the signed headers and the canonical headers of the request
+// which contain the host, the content type and the x-goog- headers.
+func (g *GCPHMACSigner) canonicalHeaders(request *http.Request) (string, string) {
+	headers := map[string]string{"host": requestHost(request)}
+	for name, values := range request.Header {
+		name = strings.ToLower(name)
+		if name == "content-type" || strings.HasPrefix(name, "x-goog-") {
+			trimmed := make([]string, 0, len(values))
+			for _, value := range values {
+				trimmed = append(trimmed, strings.Join(strings.Fields(value), " "))
+			}
+			headers[name] = strings.Join(trimmed, ",")
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	builder := &strings.Builder{}
+	for _, name := range names {
+		builder.WriteString(name + ":" + headers[name] + "\n")
+	}
+	return strings.Join(names, ";"), builder.String()
+}
+
+// canonicalURI returns the escaped path of the url
+func canonicalURI(u *url.URL) string {
+	path := u.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// canonicalQuery returns the query parameters sorted by name and value
+func canonicalQuery(query url.Values) string {
+	params := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			params = append(params, escapeRFC3986(name)+"="+escapeRFC3986(value))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+// escapeRFC3986 escapes the value leaving only the unreserved characters
+func escapeRFC3986(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
+
+var GCPDefaultVars = map[string]interface{}{
+	"gcp-region": "auto",
+}
+
+var GCPInternalOnlyVars = map[string]interface{}{
+	"gcp-access-id": struct{}{},
+	"gcp-secret":    struct{}{},
+}
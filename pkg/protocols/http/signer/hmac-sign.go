@@ -0,0 +1,252 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+)
+
+// hmacAlgorithms are the hash algorithms supported by the hmac signer
+var hmacAlgorithms = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// HMACOptions configures the generic hmac signer
+type HMACOptions struct {
+	// Secret is the key of the hmac
+	Secret string
+	// SecretEncoding is the encoding of the secret (raw, hex or base64)
+	SecretEncoding string
+	// Algorithm is the hash algorithm of the hmac (md5, sha1, sha256 or sha512)
+	Algorithm string
+	// Template is the canonical string signed, with ${name} placeholders
+	// replaced by the values of the request.
+	Template string
+	// Encoding is the encoding of the signature (hex or base64)
+	Encoding string
+	// Header is the header the signature is set in
+	Header string
+	// Query is the query parameter the signature is set in instead of a header
+	Query string
+	// Prefix is prepended to the signature
+	Prefix string
+}
+
+// Validate checks the secret, the template, the algorithm and the encodings
+// and that the signature is sent in a header or a query parameter.
+func (h *HMACOptions) Validate() error {
+	if h.Secret == "" {
+		return errors.New("hmac secret cannot be empty")
+	}
+	if h.Template == "" {
+		return errors.New("hmac template cannot be empty")
+	}
+	if _, ok := hmacAlgorithms[strings.ToLower(h.Algorithm)]; !ok {
+		return errors.New("unsupported hmac algorithm: " + h.Algorithm)
+	}
+	if _, err := decodeHMACSecret(h.Secret, h.SecretEncoding); err != nil {
+		return err
+	}
+	switch strings.ToLower(h.Encoding) {
+	case "hex", "base64":
+	default:
+		return errors.New("unsupported hmac encoding: " + h.Encoding)
+	}
+	if h.Header == "" && h.Query == "" {
+		return errors.New("hmac header or query cannot be empty")
+	}
+	return nil
+}
+
+// hmacArgs returns the hmac signer arguments from the variables of the request
+func hmacArgs(vars map[string]interface{}) (SignerArgs, error) {
+	options := &HMACOptions{
+		Secret:         types.ToString(vars["hmac-secret"]),
+		SecretEncoding: types.ToString(vars["hmac-secret-encoding"]),
+		Algorithm:      types.ToString(vars["hmac-algorithm"]),
+		Template:       types.ToString(vars["hmac-template"]),
+		Encoding:       types.ToString(vars["hmac-encoding"]),
+		Header:         types.ToString(vars["hmac-header"]),
+		Query:          types.ToString(vars["hmac-query"]),
+		Prefix:         types.ToString(vars["hmac-prefix"]),
+	}
+	// the signature is set in the authorization header by default
+	if options.Header == "" && options.Query == "" {
+		options.Header = "Authorization"
+	}
+	return options, nil
+}
+
+// HMACSigner signs requests with the hmac of a canonical string built
+// from a template of the request values.
+type HMACSigner struct {
+	secret  []byte
+	hash    func() hash.Hash
+	options *HMACOptions
+}
+
+// NewHMACSigner creates a new generic hmac signer
+func NewHMACSigner(opts *HMACOptions) (*HMACSigner, error) {
+	secret, err := decodeHMACSecret(opts.Secret, opts.SecretEncoding)
+	if err != nil {
+		return nil, err
+	}
+	return &HMACSigner{
+		secret:  secret,
+		hash:    hmacAlgorithms[strings.ToLower(opts.Algorithm)],
+		options: opts,
+	}, nil
+}
+
+// SignHTTP sets the encoded hmac of the canonical string of the request
+// in the configured header or appends it as query parameter.
+func (h *HMACSigner) SignHTTP(ctx context.Context, request *http.Request) error {
+	canonical, err := h.canonicalString(request)
+	if err != nil {
+		return err
+	}
+	sum := hmacSum(h.hash, h.secret, []byte(canonical))
+
+	var signature string
+	if strings.EqualFold(h.options.Encoding, "hex") {
+		signature = hex.EncodeToString(sum)
+	} else {
+		signature = base64.StdEncoding.EncodeToString(sum)
+	}
+	signature = h.options.Prefix + signature
+
+	if h.options.Query != "" {
+		// the query is appended to as text as the signature covers the raw query
+		param := url.QueryEscape(h.options.Query) + "=" + url.QueryEscape(signature)
+		if request.URL.RawQuery != "" {
+			param = "&" + param
+		}
+		request.URL.RawQuery += param
+	}
+	if h.options.Header != "" {
+		request.Header.Set(h.options.Header, signature)
+	}
+	return nil
+}
+
+// canonicalString returns the template with its placeholders replaced by the
+// values of the request. Placeholders are method, host, path, query, body,
+// body_md5, body_sha256 and header.<name> for the value of a header.
+func (h *HMACSigner) canonicalString(request *http.Request) (string, error) {
+	body, err := requestBody(request)
+	if err != nil {
+		return "", err
+	}
+	return os.Expand(h.options.Template, func(name string) string {
+		switch name {
+		case "method":
+			return request.Method
+		case "host":
+			return requestHost(request)
+		case "path":
+			return canonicalURI(request.URL)
+		case "query":
+			return request.URL.RawQuery
+		case "body":
+			return string(body)
+		case "body_md5":
+			sum := md5.Sum(body)
+			return hex.EncodeToString(sum[:])
+		case "body_sha256":
+			sum := sha256.Sum256(body)
+			return hex.EncodeToString(sum[:])
+		}
+		if header, ok := strings.CutPrefix(name, "header."); ok {
+			if strings.EqualFold(header, "host") {
+				return requestHost(request)
+			}
+			return request.Header.Get(header)
+		}
+		return ""
+	}), nil
+}
+
+// decodeHMACSecret decodes the secret of the encoding
+func decodeHMACSecret(secret, encoding string) ([]byte, error) {
+	switch strings.ToLower(encoding) {
+	case "", "raw":
+		return []byte(secret), nil
+	case "hex":
+		decoded, err := hex.DecodeString(secret)
+		if err != nil {
+			return nil, errors.New("hmac secret is not hex encoded")
+		}
+		return decoded, nil
+	case "base64":
+		decoded, err := base64.StdEncoding.DecodeString(secret)
+		if err != nil {
+			return nil, errors.New("hmac secret is not base64 encoded")
+		}
+		return decoded, nil
+	}
+	return nil, errors.New("unsupported hmac secret encoding: " + encoding)
+}
+
+// hmacSum returns the hmac of the data with the key
+func hmacSum(hash func() hash.Hash, key, data []byte) []byte {
+	mac := hmac.New(hash, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// requestBody returns the body of the request leaving it readable
+func requestBody(request *http.Request) ([]byte, error) {
+	if request.Body == nil || request.Body == http.NoBody {
+		return nil, nil
+	}
+	if request.GetBody != nil {
+		body, err := request.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+	data, err := io.ReadAll(request.Body)
+	if err != nil {
+		return nil, err
+	}
+	request.Body.Close()
+	request.Body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// requestHost returns the host header of the request
+func requestHost(request *http.Request) string {
+	if request.Host != "" {
+		return request.Host
+	}
+	return request.URL.Host
+}
+
+var HMACDefaultVars = map[string]interface{}{
+	"hmac-algorithm": "sha256",
+	"hmac-encoding":  "base64",
+}
+
+var HMACInternalOnlyVars = map[string]interface{}{
+	"hmac-secret": struct{}{},
+}
@@ -0,0 +1,85 @@
+package signer
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Definition is a signer of the registry which can be selected
+// with the signature field of templates.
+type Definition struct {
+	// Name is the name of the signer in templates
+	Name string
+	// Args returns the arguments of the signer from the variables of the request
+	Args func(vars map[string]interface{}) (SignerArgs, error)
+	// DefaultVars are the default variables of the signer
+	DefaultVars map[string]interface{}
+	// SkipVars are the variables which can be left unresolved in requests
+	SkipVars map[string]interface{}
+	// InternalVars are the variables which are removed from the results
+	InternalVars map[string]interface{}
+}
+
+var (
+	registryMutex = &sync.RWMutex{}
+	registry      = map[string]Definition{}
+)
+
+func init() {
+	for _, definition := range []Definition{
+		{Name: "AWS", Args: awsArgs, DefaultVars: AwsDefaultVars, SkipVars: AwsSkipList, InternalVars: AwsInternalOnlyVars},
+		{Name: "AZURE-SHARED-KEY", Args: azureSharedKeyArgs, DefaultVars: AzureDefaultVars, InternalVars: AzureInternalOnlyVars},
+		{Name: "GCP-HMAC", Args: gcpHMACArgs, DefaultVars: GCPDefaultVars, InternalVars: GCPInternalOnlyVars},
+		{Name: "HMAC", Args: hmacArgs, DefaultVars: HMACDefaultVars, InternalVars: HMACInternalOnlyVars},
+	} {
+		if err := Register(definition); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Register registers a signer so that templates can select it by its name.
+// Custom signers have to return arguments implementing SignerFactory.
+func Register(definition Definition) error {
+	if definition.Name == "" || definition.Args == nil {
+		return errors.New("signer name and arguments cannot be empty")
+	}
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	name := normalizeName(definition.Name)
+	if _, ok := registry[name]; ok {
+		return errors.New("signer already registered: " + definition.Name)
+	}
+	definition.Name = name
+	registry[name] = definition
+	return nil
+}
+
+// Get returns the registered signer of the name
+func Get(name string) (Definition, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	definition, ok := registry[normalizeName(name)]
+	return definition, ok
+}
+
+// Names returns the sorted names of the registered signers
+func Names() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func normalizeName(name string) string {
+	return strings.TrimSpace(strings.ToUpper(name))
+}
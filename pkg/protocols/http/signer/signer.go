@@ -19,6 +19,13 @@ type SignerArgs interface {
 	Validate() error
 }
 
+// SignerFactory are signer arguments creating their own signer
+// which allows registering signers outside of the package.
+type SignerFactory interface {
+	SignerArgs
+	NewSigner() (Signer, error)
+}
+
 func NewSigner(args SignerArgs) (signer Signer, err error) {
 	switch signerArgs := args.(type) {
 	case *AWSOptions:
@@ -30,6 +37,26 @@ func NewSigner(args SignerArgs) (signer Signer, err error) {
 			}
 		}
 		return awsSigner, err
+	case *AzureSharedKeyOptions:
+		if err := signerArgs.Validate(); err != nil {
+			return nil, err
+		}
+		return NewAzureSharedKeySigner(signerArgs)
+	case *GCPHMACOptions:
+		if err := signerArgs.Validate(); err != nil {
+			return nil, err
+		}
+		return NewGCPHMACSigner(signerArgs)
+	case *HMACOptions:
+		if err := signerArgs.Validate(); err != nil {
+			return nil, err
+		}
+		return NewHMACSigner(signerArgs)
+	case SignerFactory:
+		if err := signerArgs.Validate(); err != nil {
+			return nil, err
+		}
+		return signerArgs.NewSigner()
 	default:
 		return nil, errors.New("unknown signature arguments type")
 	}
@@ -0,0 +1,172 @@
+package signer
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	require.Equal(t, []string{"AWS", "AZURE-SHARED-KEY", "GCP-HMAC", "HMAC"}, Names(), "could not get builtin signers")
+
+	definition, ok := Get("azure-shared-key")
+	require.True(t, ok, "could not get signer case insensitively")
+	require.Equal(t, "AZURE-SHARED-KEY", definition.Name, "could not get normalized signer name")
+
+	err := Register(Definition{Name: "hmac", Args: hmacArgs})
+	require.NotNil(t, err, "could register signer twice")
+
+	custom := Definition{
+		Name: "static-token",
+		Args: func(vars map[string]interface{}) (SignerArgs, error) {
+			return &staticTokenArgs{token: vars["token"].(string)}, nil
+		},
+	}
+	require.Nil(t, Register(custom), "could not register custom signer")
+	t.Cleanup(func() {
+		registryMutex.Lock()
+		delete(registry, "STATIC-TOKEN")
+		registryMutex.Unlock()
+	})
+
+	definition, ok = Get("static-token")
+	require.True(t, ok, "could not get custom signer")
+	args, err := definition.Args(map[string]interface{}{"token": "secret"})
+	require.Nil(t, err, "could not get custom signer args")
+	signer, err := NewSigner(args)
+	require.Nil(t, err, "could not create custom signer")
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	require.Nil(t, signer.SignHTTP(context.Background(), req), "could not sign request")
+	require.Equal(t, "Token secret", req.Header.Get("Authorization"), "could not sign request with custom signer")
+}
+
+type staticTokenArgs struct {
+	token string
+}
+
+func (s *staticTokenArgs) Validate() error { return nil }
+
+func (s *staticTokenArgs) NewSigner() (Signer, error) { return s, nil }
+
+func (s *staticTokenArgs) SignHTTP(ctx context.Context, request *http.Request) error {
+	request.Header.Set("Authorization", "Token "+s.token)
+	return nil
+}
+
+func TestHMACSigner(t *testing.T) {
+	args, err := hmacArgs(map[string]interface{}{
+		"hmac-secret":    "secret",
+		"hmac-algorithm": "sha256",
+		"hmac-encoding":  "hex",
+		"hmac-template":  "${method}\n${path}\n${query}\n${header.x-timestamp}\n${body_sha256}",
+		"hmac-prefix":    "HMAC ",
+	})
+	require.Nil(t, err, "could not get hmac args")
+	signer, err := NewSigner(args)
+	require.Nil(t, err, "could not create hmac signer")
+
+	req, _ := http.NewRequest(http.MethodPost, "https://example.com/api/items?b=2&a=1", strings.NewReader(`{"name":"test"}`))
+	req.Header.Set("X-Timestamp", "1700000000")
+	require.Nil(t, signer.SignHTTP(context.Background(), req), "could not sign request")
+
+	bodyHash := sha256.Sum256([]byte(`{"name":"test"}`))
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("POST\n/api/items\nb=2&a=1\n1700000000\n" + hex.EncodeToString(bodyHash[:])))
+	require.Equal(t, "HMAC "+hex.EncodeToString(mac.Sum(nil)), req.Header.Get("Authorization"), "could not sign canonical string")
+
+	body, err := io.ReadAll(req.Body)
+	require.Nil(t, err, "could not read body")
+	require.Equal(t, `{"name":"test"}`, string(body), "could not keep request body")
+
+	args, _ = hmacArgs(map[string]interface{}{
+		"hmac-secret":          base64.StdEncoding.EncodeToString([]byte("secret")),
+		"hmac-algorithm":       "sha1",
+		"hmac-encoding":        "base64",
+		"hmac-template":        "${method} ${host}",
+		"hmac-query":           "signature",
+		"hmac-secret-encoding": "base64",
+	})
+	signer, err = NewSigner(args)
+	require.Nil(t, err, "could not create hmac signer")
+	req, _ = http.NewRequest(http.MethodGet, "https://example.com/?a=1", nil)
+	require.Nil(t, signer.SignHTTP(context.Background(), req), "could not sign request")
+	require.Empty(t, req.Header.Get("Authorization"), "could not place signature in query only")
+	require.NotEmpty(t, req.URL.Query().Get("signature"), "could not place signature in query")
+	require.Equal(t, "1", req.URL.Query().Get("a"), "could not keep query parameters")
+
+	t.Run("query", func(t *testing.T) {
+		args, _ := hmacArgs(map[string]interface{}{
+			"hmac-secret":    "secret",
+			"hmac-algorithm": "sha256",
+			"hmac-encoding":  "base64",
+			"hmac-template":  "${method}\n${query}",
+			"hmac-query":     "sig",
+		})
+		signer, err := NewSigner(args)
+		require.Nil(t, err, "could not create hmac signer")
+		req, _ := http.NewRequest(http.MethodGet, "https://example.com/?z=%27+OR+1=1--&a=<x>&b", nil)
+		require.Nil(t, signer.SignHTTP(context.Background(), req), "could not sign request")
+
+		// the server verifies the signature over the query sent without it
+		sent, encoded, ok := strings.Cut(req.URL.RawQuery, "&sig=")
+		require.True(t, ok, "could not append signature to query")
+		require.Equal(t, "z=%27+OR+1=1--&a=<x>&b", sent, "could not keep raw query")
+		signature, err := url.QueryUnescape(encoded)
+		require.Nil(t, err, "could not unescape signature")
+
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte("GET\n" + sent))
+		require.Equal(t, base64.StdEncoding.EncodeToString(mac.Sum(nil)), signature, "could not sign sent query")
+	})
+
+	_, err = NewSigner(&HMACOptions{Secret: "secret", Template: "${method}", Algorithm: "sha3", Encoding: "hex", Header: "Authorization"})
+	require.NotNil(t, err, "could create hmac signer with unsupported algorithm")
+}
+
+func TestAzureSharedKeySigner(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString([]byte("azure-key"))
+	signer, err := NewSigner(&AzureSharedKeyOptions{Account: "account", Key: key, Version: "2021-08-06"})
+	require.Nil(t, err, "could not create azure signer")
+
+	req, _ := http.NewRequest(http.MethodGet, "https://account.blob.core.windows.net/container?restype=container&comp=list", nil)
+	req.Header.Set("x-ms-date", "Mon, 02 Jan 2006 15:04:05 GMT")
+	require.Nil(t, signer.SignHTTP(context.Background(), req), "could not sign request")
+
+	stringToSign := "GET\n\n\n\n\n\n\n\n\n\n\n\nx-ms-date:Mon, 02 Jan 2006 15:04:05 GMT\nx-ms-version:2021-08-06\n/account/container\ncomp:list\nrestype:container"
+	mac := hmac.New(sha256.New, []byte("azure-key"))
+	mac.Write([]byte(stringToSign))
+	require.Equal(t, "SharedKey account:"+base64.StdEncoding.EncodeToString(mac.Sum(nil)), req.Header.Get("Authorization"), "could not sign string to sign")
+
+	_, err = NewSigner(&AzureSharedKeyOptions{Account: "account", Key: "not base64!"})
+	require.NotNil(t, err, "could create azure signer with invalid key")
+}
+
+func TestGCPHMACSigner(t *testing.T) {
+	signer, err := NewGCPHMACSigner(&GCPHMACOptions{AccessID: "GOOG1EXAMPLE", Secret: "secret", Region: "auto"})
+	require.Nil(t, err, "could not create gcp signer")
+
+	req, _ := http.NewRequest(http.MethodGet, "https://storage.googleapis.com/bucket/object?b=2&a=1", nil)
+	require.Nil(t, signer.sign(req, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)), "could not sign request")
+
+	require.Equal(t, "20240102T030405Z", req.Header.Get("x-goog-date"), "could not set date header")
+	emptyHash := sha256.Sum256(nil)
+	require.Equal(t, hex.EncodeToString(emptyHash[:]), req.Header.Get("x-goog-content-sha256"), "could not set payload hash header")
+
+	authorization := req.Header.Get("Authorization")
+	require.True(t, strings.HasPrefix(authorization, "GOOG4-HMAC-SHA256 Credential=GOOG1EXAMPLE/20240102/auto/storage/goog4_request, SignedHeaders=host;x-goog-content-sha256;x-goog-date, Signature="), "could not set authorization header")
+	require.Len(t, strings.TrimPrefix(authorization[strings.Index(authorization, "Signature="):], "Signature="), 64, "could not sign request")
+
+	require.Equal(t, "a=1&b=2", canonicalQuery(req.URL.Query()), "could not sort query")
+	require.Equal(t, "q=a%20b~", canonicalQuery(map[string][]string{"q": {"a b~"}}), "could not escape query")
+}
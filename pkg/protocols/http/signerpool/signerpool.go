@@ -30,7 +30,8 @@ type Configuration struct {
 // Hash returns the hash of the configuration to allow client pooling
 func (c *Configuration) Hash() string {
 	builder := &strings.Builder{}
-	builder.WriteString(fmt.Sprintf("%v", c.SignerArgs))
+	// the type is part of the hash as signers can have the same fields
+	builder.WriteString(fmt.Sprintf("%T%v", c.SignerArgs, c.SignerArgs))
 	hash := builder.String()
 	return hash
 }
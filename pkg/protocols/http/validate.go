@@ -1,15 +1,52 @@
 package http
 
-import "github.com/pkg/errors"
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http/httpclientpool"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http/race"
+)
 
 func (request *Request) validate() error {
 	if request.Race && request.NeedsRequestCondition() {
 		return errors.New("'race' and 'req-condition' can't be used together")
 	}
 
+	if request.RaceStrategy != "" {
+		switch {
+		case request.RaceStrategy != race.SinglePacketStrategy && request.RaceStrategy != race.LastByteStrategy:
+			return errors.Errorf("unknown race strategy '%s'", request.RaceStrategy)
+		case !request.Race:
+			return errors.New("'race-strategy' requires 'race' to be enabled")
+		case request.Unsafe || request.Pipeline:
+			return errors.New("'race-strategy' can't be used with 'unsafe' or 'pipeline'")
+		}
+	}
+
+	if request.Protocol != "" {
+		switch {
+		case !httpclientpool.IsSupportedProtocol(request.Protocol):
+			return errors.Errorf("unsupported protocol '%s' (supported: %s)", request.Protocol, httpclientpool.SupportedProtocols())
+		case request.Unsafe || request.Pipeline || request.RaceStrategy != "":
+			return errors.New("'protocol' can't be used with 'unsafe', 'pipeline' or 'race-strategy'")
+		}
+	}
+
 	if request.Redirects && request.HostRedirects {
 		return errors.New("'redirects' and 'host-redirects' can't be used together")
 	}
 
+	if request.NTLMAuthUsername != "" && request.NegotiateAuthUsername != "" {
+		return errors.New("'ntlm-username' and 'negotiate-username' can't be used together")
+	}
+	if request.NegotiateAuthUsername != "" && !strings.Contains(request.NegotiateAuthUsername, "@") {
+		return errors.New("'negotiate-username' must be in user@REALM format")
+	}
+	if (request.NTLMAuthUsername != "" || request.NegotiateAuthUsername != "") && (request.Unsafe || request.Pipeline) {
+		return errors.New("'ntlm-username' and 'negotiate-username' can't be used with 'unsafe' or 'pipeline'")
+	}
+
 	return nil
 }
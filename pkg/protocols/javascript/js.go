@@ -264,6 +264,15 @@ func (request *Request) ExecuteWithResults(target *contextargs.Context, dynamicV
 	templateCtx := request.options.GetTemplateCtx(input.MetaInput)
 
 	payloadValues := generators.BuildPayloadFromOptions(request.options.Options)
+	// credentials of the target are exposed as variables as
+	// javascript requests are not decorated with auth
+	if request.options.AuthProvider != nil {
+		for _, strategy := range request.options.AuthProvider.LookupAddr(hostPort) {
+			for k, v := range strategy.Variables() {
+				payloadValues[k] = v
+			}
+		}
+	}
 	for k, v := range dynamicValues {
 		payloadValues[k] = v
 	}
@@ -425,9 +434,11 @@ func (request *Request) executeRequestWithPayloads(hostPort string, input *conte
 		requestData = []byte(transformedData)
 	}
 
+	release := request.options.HostConcurrency.Acquire(hostPort)
 	results, err := request.options.JsCompiler.ExecuteWithOptions(string(requestData), argsCopy, &compiler.ExecuteOptions{
 		Pool: false,
 	})
+	release()
 	if err != nil {
 		// shouldn't fail even if it returned error instead create a failure event
 		results = compiler.ExecuteResult{"success": false, "error": err.Error()}
@@ -1,8 +1,11 @@
 package network
 
 import (
+	"regexp"
 	"strings"
+	"time"
 
+	"github.com/Knetic/govaluate"
 	"github.com/pkg/errors"
 
 	"github.com/projectdiscovery/fastdialer/fastdialer"
@@ -97,6 +100,10 @@ var RequestPartDefinitions = map[string]string{
 	"request":       "Network request made from the client",
 	"body,all,data": "Network response received from server (default)",
 	"raw":           "Full Network protocol data",
+
+	"tls_version,cipher,tls_connection,sni":                       "TLS metadata of the connection for tls:// addresses and starttls",
+	"subject_dn,subject_cn,subject_an,issuer_dn,issuer_cn,serial": "Fields of the TLS certificate of the server",
+	"not_before,not_after,expired,self_signed,mismatched":         "Validity of the TLS certificate of the server",
 }
 
 type addressKV struct {
@@ -138,6 +145,64 @@ type Input struct {
 	// examples:
 	//   - value: "\"prefix\""
 	Name string `yaml:"name,omitempty" json:"name,omitempty" jsonschema:"title=optional name for data read,description=Optional name of the data read to provide matching on"`
+	// description: |
+	//   StartTLS performs the STARTTLS exchange of the protocol and upgrades the connection to TLS.
+	//
+	//   Inputs before it are sent in plaintext and inputs after it over TLS. The
+	//   greeting of the server is not read again if earlier inputs read from the
+	//   connection. The data exchanged during the negotiation can be matched with
+	//   `name` attribute, and the TLS metadata of the connection is available to
+	//   matchers like in the ssl protocol.
+	// values:
+	//   - "smtp"
+	//   - "imap"
+	//   - "pop3"
+	//   - "ftp"
+	//   - "ldap"
+	//   - "xmpp"
+	//   - "postgres"
+	StartTLS string `yaml:"starttls,omitempty" json:"starttls,omitempty" jsonschema:"title=starttls protocol,description=StartTLS performs the STARTTLS exchange of the protocol and upgrades the connection to TLS,enum=smtp,enum=imap,enum=pop3,enum=ftp,enum=ldap,enum=xmpp,enum=postgres"`
+	// description: |
+	//   ReadUntil reads from socket until the delimiter is received.
+	//
+	//   It supports DSL Helper Functions as well as normal expressions. When `read`
+	//   is specified it is the maximum number of bytes to read, otherwise 65536.
+	// examples:
+	//   - value: "\"\\r\\n\""
+	ReadUntil string `yaml:"read-until,omitempty" json:"read-until,omitempty" jsonschema:"title=delimiter to read until,description=Read from socket until the delimiter is received"`
+	// description: |
+	//   ReadRegex reads from socket until the data read matches the regex.
+	// examples:
+	//   - value: "\"(?m)^220 .*\\r\\n\""
+	ReadRegex string `yaml:"read-regex,omitempty" json:"read-regex,omitempty" jsonschema:"title=regex to read until,description=Read from socket until the data read matches the regex"`
+	// description: |
+	//   ReadFrame reads a length-prefixed frame from socket.
+	ReadFrame *ReadFrame `yaml:"read-frame,omitempty" json:"read-frame,omitempty" jsonschema:"title=length-prefixed frame to read,description=Read a length-prefixed frame from socket"`
+	// description: |
+	//   ReadIdle reads from socket until no data is received for the duration.
+	// examples:
+	//   - value: "\"500ms\""
+	ReadIdle string `yaml:"read-idle,omitempty" json:"read-idle,omitempty" jsonschema:"title=idle timeout to read until,description=Read from socket until no data is received for the duration"`
+	// description: |
+	//   If is a DSL expression evaluated before the input, which is skipped when it is false.
+	//
+	//   Data read by previous inputs is available with their `name`.
+	// examples:
+	//   - value: "\"starts_with(greeting, '+OK')\""
+	If string `yaml:"if,omitempty" json:"if,omitempty" jsonschema:"title=condition to send the input,description=DSL expression evaluated before the input which is skipped when it is false"`
+	// description: |
+	//   Expect is a DSL expression evaluated after the input, which stops the
+	//   conversation without results when it is false.
+	//
+	//   Data read by the input is available as `data`.
+	// examples:
+	//   - value: "\"starts_with(hex_encode(data), '0300')\""
+	Expect string `yaml:"expect,omitempty" json:"expect,omitempty" jsonschema:"title=expectation after the input,description=DSL expression evaluated after the input which stops the conversation when it is false"`
+
+	readRegex        *regexp.Regexp
+	readIdle         time.Duration
+	ifExpression     *govaluate.EvaluableExpression
+	expectExpression *govaluate.EvaluableExpression
 }
 
 // GetID returns the unique ID of the request if any.
@@ -159,6 +224,28 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 		}
 		request.addresses = append(request.addresses, addressKV{address: address, tls: shouldUseTLS})
 	}
+	var starttls bool
+	for _, input := range request.Inputs {
+		if input.StartTLS == "" {
+			continue
+		}
+		input.StartTLS = normalizeValue(input.StartTLS)
+		if _, ok := starttlsProtocols[input.StartTLS]; !ok {
+			return errors.Errorf("unsupported starttls protocol %s", input.StartTLS)
+		}
+		if starttls {
+			return errors.New("starttls can only be used once per request")
+		}
+		starttls = true
+		if shouldUseTLS {
+			return errors.New("starttls cannot be used with tls:// addresses")
+		}
+	}
+	for _, input := range request.Inputs {
+		if err := input.compile(); err != nil {
+			return errors.Wrap(err, "could not compile input")
+		}
+	}
 	// Pre-compile any input dsl functions before executing the request.
 	for _, input := range request.Inputs {
 		if input.Type.String() != "" {
@@ -0,0 +1,282 @@
+package network
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/Knetic/govaluate"
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/operators/common/dsl"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/expressions"
+)
+
+// defaultMaxReadSize is the maximum number of bytes read by a read strategy
+// when read is not specified for the input.
+const defaultMaxReadSize = 64 * 1024
+
+// ReadFrame describes the length prefix of a frame to read from socket.
+//
+// The frame is made of `offset` bytes, the length field and the number of
+// bytes given by the length field plus `adjust`.
+type ReadFrame struct {
+	// description: |
+	//   Size is the size of the length field in bytes.
+	// values:
+	//   - 1
+	//   - 2
+	//   - 4
+	Size int `yaml:"size,omitempty" json:"size,omitempty" jsonschema:"title=size of the length field,description=Size of the length field in bytes (1/2/4)"`
+	// description: |
+	//   Endian is the byte order of the length field.
+	//
+	//   Default value is big.
+	// values:
+	//   - "big"
+	//   - "little"
+	Endian string `yaml:"endian,omitempty" json:"endian,omitempty" jsonschema:"title=byte order of the length field,description=Byte order of the length field,enum=big,enum=little"`
+	// description: |
+	//   Offset is the number of bytes before the length field.
+	// examples:
+	//   - value: 2
+	Offset int `yaml:"offset,omitempty" json:"offset,omitempty" jsonschema:"title=offset of the length field,description=Number of bytes before the length field"`
+	// description: |
+	//   Adjust is added to the length to get the number of bytes following
+	//   the length field.
+	//
+	//   For example, it is -4 for a 4 bytes length counting itself.
+	// examples:
+	//   - value: -4
+	Adjust int `yaml:"adjust,omitempty" json:"adjust,omitempty" jsonschema:"title=adjustment of the length,description=Value added to the length to get the number of bytes following the length field"`
+}
+
+// compile validates the read strategy and conditions of the input
+func (input *Input) compile() error {
+	var strategies int
+	if input.ReadUntil != "" {
+		strategies++
+	}
+	if input.ReadRegex != "" {
+		regex, err := regexp.Compile(input.ReadRegex)
+		if err != nil {
+			return errors.Wrap(err, "could not compile read-regex")
+		}
+		input.readRegex = regex
+		strategies++
+	}
+	if input.ReadFrame != nil {
+		if err := input.ReadFrame.validate(); err != nil {
+			return err
+		}
+		strategies++
+	}
+	if input.ReadIdle != "" {
+		idle, err := time.ParseDuration(input.ReadIdle)
+		if err != nil || idle <= 0 {
+			return errors.Errorf("invalid read-idle duration %s", input.ReadIdle)
+		}
+		input.readIdle = idle
+		strategies++
+	}
+	if strategies > 1 {
+		return errors.New("only one of read-until, read-regex, read-frame and read-idle can be used")
+	}
+	if input.StartTLS != "" && (strategies > 0 || input.Expect != "") {
+		return errors.New("read strategies and expect cannot be used with starttls")
+	}
+
+	var err error
+	if input.If != "" {
+		if input.ifExpression, err = govaluate.NewEvaluableExpressionWithFunctions(input.If, dsl.HelperFunctions); err != nil {
+			return errors.Wrap(err, "could not compile if expression")
+		}
+	}
+	if input.Expect != "" {
+		if input.expectExpression, err = govaluate.NewEvaluableExpressionWithFunctions(input.Expect, dsl.HelperFunctions); err != nil {
+			return errors.Wrap(err, "could not compile expect expression")
+		}
+	}
+	return nil
+}
+
+func (frame *ReadFrame) validate() error {
+	if frame.Size != 1 && frame.Size != 2 && frame.Size != 4 {
+		return errors.Errorf("invalid read-frame size %d", frame.Size)
+	}
+	frame.Endian = normalizeValue(frame.Endian)
+	if frame.Endian != "" && frame.Endian != "big" && frame.Endian != "little" {
+		return errors.Errorf("invalid read-frame endian %s", frame.Endian)
+	}
+	if frame.Offset < 0 {
+		return errors.Errorf("invalid read-frame offset %d", frame.Offset)
+	}
+	return nil
+}
+
+// hasReadStrategy returns true if the input reads with a read strategy
+func (input *Input) hasReadStrategy() bool {
+	return input.ReadUntil != "" || input.readRegex != nil || input.ReadFrame != nil || input.readIdle > 0
+}
+
+// readResponse reads the response of the input from the connection using
+// its read strategy. Read deadlines of the connection are modified.
+func (input *Input) readResponse(conn net.Conn, values map[string]interface{}) ([]byte, error) {
+	maxSize := defaultMaxReadSize
+	if input.Read > 0 {
+		maxSize = input.Read
+	}
+
+	switch {
+	case input.ReadUntil != "":
+		delimiter, err := expressions.Evaluate(input.ReadUntil, values)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not evaluate read-until")
+		}
+		return readUntilMatch(conn, maxSize, func(data []byte) int {
+			if index := bytes.Index(data, []byte(delimiter)); index != -1 {
+				return index + len(delimiter)
+			}
+			return -1
+		})
+	case input.readRegex != nil:
+		return readUntilMatch(conn, maxSize, func(data []byte) int {
+			if location := input.readRegex.FindIndex(data); location != nil {
+				return location[1]
+			}
+			return -1
+		})
+	case input.ReadFrame != nil:
+		return input.ReadFrame.read(conn, maxSize)
+	case input.readIdle > 0:
+		return readUntilIdle(conn, maxSize, input.readIdle)
+	}
+	return nil, nil
+}
+
+// bufferedConn is a connection returning the data read past the match of
+// a read strategy before reading from the underlying connection again.
+type bufferedConn struct {
+	net.Conn
+	pending []byte
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	if len(c.pending) > 0 {
+		n := copy(b, c.pending)
+		c.pending = c.pending[n:]
+		return n, nil
+	}
+	return c.Conn.Read(b)
+}
+
+// unwrapConn returns the underlying connection of a buffered connection
+func unwrapConn(conn net.Conn) net.Conn {
+	if buffered, ok := conn.(*bufferedConn); ok {
+		return buffered.Conn
+	}
+	return conn
+}
+
+// readUntilMatch reads from the connection in chunks until the data read
+// matches, match returning the end of the match or -1. The data read past
+// the match is kept for the next reads of buffered connections.
+func readUntilMatch(conn net.Conn, maxSize int, match func(data []byte) int) ([]byte, error) {
+	_ = conn.SetReadDeadline(time.Now().Add(DefaultReadTimeout))
+
+	var data []byte
+	buffer := make([]byte, 4096)
+	for len(data) < maxSize {
+		chunk := buffer
+		if remaining := maxSize - len(data); remaining < len(chunk) {
+			chunk = chunk[:remaining]
+		}
+		n, err := conn.Read(chunk)
+		data = append(data, chunk[:n]...)
+		if end := match(data); end != -1 {
+			if buffered, ok := conn.(*bufferedConn); ok && end < len(data) {
+				buffered.pending = append(append([]byte{}, data[end:]...), buffered.pending...)
+			}
+			return data[:end], nil
+		}
+		if err != nil {
+			return data, errors.Wrap(err, "could not read from connection")
+		}
+	}
+	return data, errors.Errorf("could not find match in %d bytes", maxSize)
+}
+
+// read reads a length-prefixed frame from the connection
+func (frame *ReadFrame) read(conn net.Conn, maxSize int) ([]byte, error) {
+	_ = conn.SetReadDeadline(time.Now().Add(DefaultReadTimeout))
+
+	header := make([]byte, frame.Offset+frame.Size)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return nil, errors.Wrap(err, "could not read frame header")
+	}
+	field := header[frame.Offset:]
+	var length int64
+	for i := range field {
+		b := field[i]
+		if frame.Endian == "little" {
+			b = field[len(field)-1-i]
+		}
+		length = length<<8 | int64(b)
+	}
+	size := length + int64(frame.Adjust)
+	if size < 0 || int64(len(header))+size > int64(maxSize) {
+		return header, errors.Errorf("invalid frame length %d", length)
+	}
+
+	data := make([]byte, len(header)+int(size))
+	copy(data, header)
+	if _, err := io.ReadFull(conn, data[len(header):]); err != nil {
+		return data, errors.Wrap(err, "could not read frame")
+	}
+	return data, nil
+}
+
+// readUntilIdle reads from the connection until no data is received for
+// the idle duration, the connection is closed or the read timeout expires.
+func readUntilIdle(conn net.Conn, maxSize int, idle time.Duration) ([]byte, error) {
+	end := time.Now().Add(DefaultReadTimeout)
+
+	var data []byte
+	buffer := make([]byte, 4096)
+	for len(data) < maxSize {
+		deadline := time.Now().Add(idle)
+		if deadline.After(end) {
+			deadline = end
+		}
+		_ = conn.SetReadDeadline(deadline)
+
+		chunk := buffer
+		if remaining := maxSize - len(data); remaining < len(chunk) {
+			chunk = chunk[:remaining]
+		}
+		n, err := conn.Read(chunk)
+		data = append(data, chunk[:n]...)
+		if err != nil {
+			if errors.Is(err, os.ErrDeadlineExceeded) || errors.Is(err, io.EOF) {
+				return data, nil
+			}
+			return data, errors.Wrap(err, "could not read from connection")
+		}
+	}
+	return data, nil
+}
+
+// evaluateCondition evaluates the expression of an if or expect field.
+// Expressions which cannot be evaluated, like ones using the name of a
+// skipped input, are false.
+func evaluateCondition(expression *govaluate.EvaluableExpression, values map[string]interface{}) bool {
+	result, err := expression.Evaluate(values)
+	if err != nil {
+		return false
+	}
+	matched, ok := result.(bool)
+	return ok && matched
+}
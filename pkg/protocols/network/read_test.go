@@ -0,0 +1,161 @@
+package network
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// serveData writes the chunks to the server side of a pipe with a pause
+// between them and returns the client side.
+func serveData(t *testing.T, pause time.Duration, chunks ...string) net.Conn {
+	server, client := net.Pipe()
+	t.Cleanup(func() {
+		_ = server.Close()
+		_ = client.Close()
+	})
+	go func() {
+		for _, chunk := range chunks {
+			if _, err := server.Write([]byte(chunk)); err != nil {
+				return
+			}
+			time.Sleep(pause)
+		}
+	}()
+	return client
+}
+
+func TestReadStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    *Input
+		chunks   []string
+		expected string
+	}{
+		{
+			name:     "read-until",
+			input:    &Input{ReadUntil: "\r\n"},
+			chunks:   []string{"+OK ready", "\r\n-ERR next"},
+			expected: "+OK ready\r\n",
+		},
+		{
+			name:     "read-until-expression",
+			input:    &Input{ReadUntil: "{{hex_decode('0d0a')}}"},
+			chunks:   []string{"220 ready\r\n250 next"},
+			expected: "220 ready\r\n",
+		},
+		{
+			name:     "read-regex",
+			input:    &Input{ReadRegex: `(?m)^220 .*\r\n`},
+			chunks:   []string{"220-first\r\n", "220 last\r\nQUIT"},
+			expected: "220-first\r\n220 last\r\n",
+		},
+		{
+			name:     "read-frame-big-endian",
+			input:    &Input{ReadFrame: &ReadFrame{Size: 2}},
+			chunks:   []string{"\x00\x03abc", "def"},
+			expected: "\x00\x03abc",
+		},
+		{
+			name:     "read-frame-little-endian-adjust",
+			input:    &Input{ReadFrame: &ReadFrame{Size: 4, Endian: "little", Adjust: -4}},
+			chunks:   []string{"\x07\x00\x00\x00abcdef"},
+			expected: "\x07\x00\x00\x00abc",
+		},
+		{
+			name:     "read-frame-offset",
+			input:    &Input{ReadFrame: &ReadFrame{Size: 2, Offset: 2, Adjust: -4}},
+			chunks:   []string{"\x03\x00\x00\x06ab", "cd"},
+			expected: "\x03\x00\x00\x06ab",
+		},
+		{
+			name:     "read-idle",
+			input:    &Input{ReadIdle: "200ms"},
+			chunks:   []string{"first ", "second"},
+			expected: "first second",
+		},
+		{
+			name:     "read-idle-max-size",
+			input:    &Input{ReadIdle: "200ms", Read: 4},
+			chunks:   []string{"first second"},
+			expected: "firs",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Nil(t, test.input.compile(), "could not compile input")
+			conn := &bufferedConn{Conn: serveData(t, 50*time.Millisecond, test.chunks...)}
+
+			data, err := test.input.readResponse(conn, map[string]interface{}{})
+			require.Nil(t, err, "could not read response")
+			require.Equal(t, test.expected, string(data), "could not get correct response")
+		})
+	}
+
+	t.Run("read-regex-pending", func(t *testing.T) {
+		input := &Input{ReadRegex: `(?m)^220 .*\r\n`}
+		require.Nil(t, input.compile(), "could not compile input")
+		conn := &bufferedConn{Conn: serveData(t, 0, "220-first\r\n220 last\r\nQUIT\r\n")}
+
+		data, err := input.readResponse(conn, map[string]interface{}{})
+		require.Nil(t, err, "could not read response")
+		require.Equal(t, "220-first\r\n220 last\r\n", string(data), "could not get correct response")
+
+		next := make([]byte, 16)
+		n, err := conn.Read(next)
+		require.Nil(t, err, "could not read pending data")
+		require.Equal(t, "QUIT\r\n", string(next[:n]), "could not get data read past the match")
+	})
+	t.Run("read-until-max-size", func(t *testing.T) {
+		input := &Input{ReadUntil: "\r\n", Read: 4}
+		require.Nil(t, input.compile(), "could not compile input")
+		conn := serveData(t, 0, "+OK ready\r\n")
+
+		_, err := input.readResponse(conn, map[string]interface{}{})
+		require.NotNil(t, err, "could read past the maximum size")
+	})
+	t.Run("read-frame-invalid-length", func(t *testing.T) {
+		input := &Input{ReadFrame: &ReadFrame{Size: 1, Adjust: -4}}
+		require.Nil(t, input.compile(), "could not compile input")
+		conn := serveData(t, 0, "\x02ab")
+
+		_, err := input.readResponse(conn, map[string]interface{}{})
+		require.NotNil(t, err, "could read frame with negative length")
+	})
+}
+
+func TestInputCompile(t *testing.T) {
+	invalid := map[string]*Input{
+		"multiple-strategies": {ReadUntil: "\n", ReadIdle: "1s"},
+		"invalid-regex":       {ReadRegex: "("},
+		"invalid-frame-size":  {ReadFrame: &ReadFrame{Size: 3}},
+		"invalid-endian":      {ReadFrame: &ReadFrame{Size: 2, Endian: "middle"}},
+		"invalid-idle":        {ReadIdle: "soon"},
+		"starttls-strategy":   {StartTLS: "smtp", ReadUntil: "\n"},
+		"starttls-expect":     {StartTLS: "smtp", Expect: "true"},
+		"invalid-if":          {If: "len(greeting"},
+		"invalid-expect":      {Expect: "data =="},
+	}
+	for name, input := range invalid {
+		require.NotNil(t, input.compile(), "could compile invalid input %s", name)
+	}
+
+	input := &Input{Data: "PING\r\n", ReadFrame: &ReadFrame{Size: 2, Endian: " Little "}, If: "contains(greeting, '+OK')", Expect: "len(data) > 2"}
+	require.Nil(t, input.compile(), "could not compile input")
+	require.Equal(t, "little", input.ReadFrame.Endian, "could not normalize endian")
+	require.True(t, input.hasReadStrategy(), "could not get read strategy")
+	require.False(t, (&Input{Read: 1024}).hasReadStrategy(), "could get read strategy for fixed read")
+}
+
+func TestEvaluateCondition(t *testing.T) {
+	input := &Input{If: "starts_with(greeting, '+OK')", Expect: "hex_encode(data) == '0300'"}
+	require.Nil(t, input.compile(), "could not compile input")
+
+	require.True(t, evaluateCondition(input.ifExpression, map[string]interface{}{"greeting": "+OK ready"}), "could not evaluate true condition")
+	require.False(t, evaluateCondition(input.ifExpression, map[string]interface{}{"greeting": "-ERR"}), "could evaluate false condition")
+	require.False(t, evaluateCondition(input.ifExpression, map[string]interface{}{}), "could evaluate condition with missing name")
+
+	require.True(t, evaluateCondition(input.expectExpression, map[string]interface{}{"data": "\x03\x00"}), "could not evaluate binary expectation")
+}
@@ -137,6 +137,9 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 		hostname = host
 	}
 
+	release := request.options.HostConcurrency.Acquire(actualAddress)
+	defer release()
+
 	if shouldUseTLS {
 		conn, err = request.dialer.DialTLS(context.Background(), "tcp", actualAddress)
 	} else {
@@ -147,8 +150,14 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 		request.options.Progress.IncrementFailedRequestsBy(1)
 		return errors.Wrap(err, "could not connect to server")
 	}
-	defer conn.Close()
-	_ = conn.SetDeadline(time.Now().Add(time.Duration(request.options.Options.Timeout) * time.Second))
+	// data read past the matches of read strategies is kept for the next reads
+	conn = &bufferedConn{Conn: conn}
+	defer func() {
+		// conn may be replaced by the tls connection of a starttls input
+		_ = conn.Close()
+	}()
+	deadline := time.Now().Add(time.Duration(request.options.Options.Timeout) * time.Second)
+	_ = conn.SetDeadline(deadline)
 
 	var interactshURLs []string
 
@@ -162,7 +171,29 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 
 	inputEvents := make(map[string]interface{})
 
+	// started and greeted are true once inputs were sent and read
+	// on the connection, which is used by starttls inputs
+	var started, greeted bool
 	for _, input := range request.Inputs {
+		if input.ifExpression != nil && !evaluateCondition(input.ifExpression, interimValues) {
+			continue
+		}
+		if input.StartTLS != "" {
+			tlsConn, recorder, err := request.startTLS(conn, input.StartTLS, actualAddress, hostname, started, greeted)
+			reqBuilder.Write(recorder.sent.Bytes())
+			responseBuilder.Write(recorder.received.Bytes())
+			if err != nil {
+				request.options.Output.Request(request.options.TemplatePath, address, request.Type().String(), err)
+				request.options.Progress.IncrementFailedRequestsBy(1)
+				return err
+			}
+			conn = &bufferedConn{Conn: tlsConn}
+			if input.Name != "" {
+				inputEvents[input.Name] = recorder.received.String()
+				interimValues[input.Name] = recorder.received.String()
+			}
+			continue
+		}
 		data := []byte(input.Data)
 
 		if request.options.Interactsh != nil {
@@ -199,16 +230,25 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 			request.options.Progress.IncrementFailedRequestsBy(1)
 			return errors.Wrap(err, "could not write request to server")
 		}
-
-		if input.Read > 0 {
-			buffer, err := reader.ConnReadNWithTimeout(conn, int64(input.Read), DefaultReadTimeout)
+		started = true
+
+		var bufferStr string
+		if input.Read > 0 || input.hasReadStrategy() {
+			var buffer []byte
+			if input.hasReadStrategy() {
+				buffer, err = input.readResponse(conn, interimValues)
+				_ = conn.SetReadDeadline(deadline)
+			} else {
+				buffer, err = reader.ConnReadNWithTimeout(conn, int64(input.Read), DefaultReadTimeout)
+			}
 			if err != nil {
 				return errorutil.NewWithErr(err).Msgf("could not read response from connection")
 			}
+			greeted = true
 
 			responseBuilder.Write(buffer)
 
-			bufferStr := string(buffer)
+			bufferStr = string(buffer)
 			if input.Name != "" {
 				inputEvents[input.Name] = bufferStr
 				interimValues[input.Name] = bufferStr
@@ -222,6 +262,12 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 				}
 			}
 		}
+
+		if input.expectExpression != nil && !evaluateCondition(input.expectExpression, generators.MergeMaps(interimValues, map[string]interface{}{"data": bufferStr})) {
+			gologger.Verbose().Msgf("[%s] Stopped network conversation with %s: expectation %q was not met\n", request.options.TemplateID, actualAddress, input.Expect)
+			request.options.Progress.IncrementRequests()
+			return nil
+		}
 	}
 
 	request.options.Progress.IncrementRequests()
@@ -260,6 +306,9 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 
 	response := responseBuilder.String()
 	outputEvent := request.responseToDSLMap(reqBuilder.String(), string(final), response, input.MetaInput.Input, actualAddress)
+	for k, v := range tlsMetadata(conn, hostname) {
+		outputEvent[k] = v
+	}
 	// add response fields to template context and merge templatectx variables to output event
 	request.options.AddTemplateVars(input.MetaInput, request.Type(), request.ID, outputEvent)
 	outputEvent = generators.MergeMaps(outputEvent, request.options.GetTemplateCtx(input.MetaInput).GetAll())
@@ -0,0 +1,314 @@
+package network
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/asn1"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// starttlsFunc performs the protocol specific STARTTLS exchange on a
+// plaintext connection. The greeting of the server is not read if it
+// was greeted, i.e. earlier inputs already read from the connection.
+// Once it returns without error the connection is ready for the TLS handshake.
+type starttlsFunc func(conn net.Conn, hostname string, greeted bool) error
+
+// starttlsProtocols contains the STARTTLS exchanges supported by the network protocol.
+var starttlsProtocols = map[string]starttlsFunc{
+	"smtp":     smtpStartTLS,
+	"imap":     imapStartTLS,
+	"pop3":     pop3StartTLS,
+	"ftp":      ftpStartTLS,
+	"ldap":     ldapStartTLS,
+	"xmpp":     xmppStartTLS,
+	"postgres": postgresStartTLS,
+}
+
+// lineReader reads CRLF terminated lines from a connection. It makes sure no
+// data sent by the server after the STARTTLS response is left in its buffer.
+type lineReader struct {
+	reader *bufio.Reader
+}
+
+func newLineReader(conn net.Conn) *lineReader {
+	return &lineReader{reader: bufio.NewReader(conn)}
+}
+
+func (r *lineReader) readLine() (string, error) {
+	line, err := r.reader.ReadString('\n')
+	if err != nil {
+		return "", errors.Wrap(err, "could not read line")
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// readReply reads a multiline reply of the format used by SMTP and FTP
+// where continuation lines use a "-" after the status code.
+func (r *lineReader) readReply(code string) (string, error) {
+	var lines []string
+	for {
+		line, err := r.readLine()
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, line)
+		if len(line) < 4 || line[3] != '-' {
+			break
+		}
+	}
+	reply := strings.Join(lines, "\n")
+	if !strings.HasPrefix(lines[len(lines)-1], code) {
+		return reply, errors.Errorf("unexpected reply %q, expected %s", reply, code)
+	}
+	return reply, nil
+}
+
+// done checks that the server did not send anything after the STARTTLS
+// response, which would otherwise be lost or injected into the TLS session.
+func (r *lineReader) done() error {
+	if r.reader.Buffered() > 0 {
+		return errors.New("server sent unexpected data after starttls response")
+	}
+	return nil
+}
+
+func writeCommand(conn net.Conn, command string) error {
+	if _, err := conn.Write([]byte(command + "\r\n")); err != nil {
+		return errors.Wrap(err, "could not write command")
+	}
+	return nil
+}
+
+// smtpStartTLS implements STARTTLS for SMTP (RFC 3207)
+func smtpStartTLS(conn net.Conn, hostname string, greeted bool) error {
+	reader := newLineReader(conn)
+	if !greeted {
+		if _, err := reader.readReply("220"); err != nil {
+			return errors.Wrap(err, "could not read smtp greeting")
+		}
+	}
+	if err := writeCommand(conn, "EHLO vulmap"); err != nil {
+		return err
+	}
+	reply, err := reader.readReply("250")
+	if err != nil {
+		return errors.Wrap(err, "could not read smtp ehlo reply")
+	}
+	if !strings.Contains(strings.ToUpper(reply), "STARTTLS") {
+		return errors.New("smtp server does not support starttls")
+	}
+	if err := writeCommand(conn, "STARTTLS"); err != nil {
+		return err
+	}
+	if _, err := reader.readReply("220"); err != nil {
+		return errors.Wrap(err, "could not start smtp tls")
+	}
+	return reader.done()
+}
+
+// imapStartTLS implements STARTTLS for IMAP (RFC 2595)
+func imapStartTLS(conn net.Conn, hostname string, greeted bool) error {
+	reader := newLineReader(conn)
+	if !greeted {
+		greeting, err := reader.readLine()
+		if err != nil {
+			return errors.Wrap(err, "could not read imap greeting")
+		}
+		if !strings.HasPrefix(greeting, "* OK") {
+			return errors.Errorf("unexpected imap greeting %q", greeting)
+		}
+	}
+	if err := writeCommand(conn, "a001 STARTTLS"); err != nil {
+		return err
+	}
+	for {
+		line, err := reader.readLine()
+		if err != nil {
+			return errors.Wrap(err, "could not read imap starttls reply")
+		}
+		if !strings.HasPrefix(line, "a001 ") {
+			continue
+		}
+		if !strings.HasPrefix(line, "a001 OK") {
+			return errors.Errorf("could not start imap tls: %s", line)
+		}
+		break
+	}
+	return reader.done()
+}
+
+// pop3StartTLS implements STLS for POP3 (RFC 2595)
+func pop3StartTLS(conn net.Conn, hostname string, greeted bool) error {
+	reader := newLineReader(conn)
+	if !greeted {
+		greeting, err := reader.readLine()
+		if err != nil {
+			return errors.Wrap(err, "could not read pop3 greeting")
+		}
+		if !strings.HasPrefix(greeting, "+OK") {
+			return errors.Errorf("unexpected pop3 greeting %q", greeting)
+		}
+	}
+	if err := writeCommand(conn, "STLS"); err != nil {
+		return err
+	}
+	reply, err := reader.readLine()
+	if err != nil {
+		return errors.Wrap(err, "could not read pop3 stls reply")
+	}
+	if !strings.HasPrefix(reply, "+OK") {
+		return errors.Errorf("could not start pop3 tls: %s", reply)
+	}
+	return reader.done()
+}
+
+// ftpStartTLS implements AUTH TLS for FTP (RFC 4217)
+func ftpStartTLS(conn net.Conn, hostname string, greeted bool) error {
+	reader := newLineReader(conn)
+	if !greeted {
+		if _, err := reader.readReply("220"); err != nil {
+			return errors.Wrap(err, "could not read ftp greeting")
+		}
+	}
+	if err := writeCommand(conn, "AUTH TLS"); err != nil {
+		return err
+	}
+	if _, err := reader.readReply("234"); err != nil {
+		return errors.Wrap(err, "could not start ftp tls")
+	}
+	return reader.done()
+}
+
+// ldapStartTLSRequest is the LDAP StartTLS extended request (RFC 4511 4.14)
+// with message id 1.
+var ldapStartTLSRequest = append([]byte{0x30, 0x1d, 0x02, 0x01, 0x01, 0x77, 0x18, 0x80, 0x16}, "1.3.6.1.4.1.1466.20037"...)
+
+// ldapStartTLS implements the StartTLS extended operation for LDAP
+func ldapStartTLS(conn net.Conn, hostname string, greeted bool) error {
+	if _, err := conn.Write(ldapStartTLSRequest); err != nil {
+		return errors.Wrap(err, "could not write ldap starttls request")
+	}
+
+	var message asn1.RawValue
+	if err := readBERElement(conn, &message); err != nil {
+		return errors.Wrap(err, "could not read ldap starttls response")
+	}
+	var messageID int
+	rest, err := asn1.Unmarshal(message.Bytes, &messageID)
+	if err != nil {
+		return errors.Wrap(err, "could not parse ldap message id")
+	}
+	var response asn1.RawValue
+	if _, err := asn1.Unmarshal(rest, &response); err != nil {
+		return errors.Wrap(err, "could not parse ldap extended response")
+	}
+	// ExtendedResponse is [APPLICATION 24]
+	if response.Class != asn1.ClassApplication || response.Tag != 24 {
+		return errors.Errorf("unexpected ldap response with tag %d", response.Tag)
+	}
+	var resultCode asn1.Enumerated
+	if _, err := asn1.Unmarshal(response.Bytes, &resultCode); err != nil {
+		return errors.Wrap(err, "could not parse ldap result code")
+	}
+	if resultCode != 0 {
+		return errors.Errorf("could not start ldap tls: result code %d", resultCode)
+	}
+	return nil
+}
+
+// readBERElement reads exactly one BER element with a definite length
+// from the connection and parses it into value.
+func readBERElement(conn net.Conn, value *asn1.RawValue) error {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return err
+	}
+	length := int(header[1])
+	if length&0x80 != 0 {
+		count := length & 0x7f
+		if count == 0 || count > 4 {
+			return errors.New("unsupported ber length")
+		}
+		lengthBytes := make([]byte, count)
+		if _, err := io.ReadFull(conn, lengthBytes); err != nil {
+			return err
+		}
+		header = append(header, lengthBytes...)
+		length = 0
+		for _, b := range lengthBytes {
+			length = length<<8 | int(b)
+		}
+	}
+	element := make([]byte, len(header)+length)
+	copy(element, header)
+	if _, err := io.ReadFull(conn, element[len(header):]); err != nil {
+		return err
+	}
+	_, err := asn1.Unmarshal(element, value)
+	return err
+}
+
+// xmppStartTLS implements STARTTLS for XMPP client streams (RFC 6120 5.4)
+func xmppStartTLS(conn net.Conn, hostname string, greeted bool) error {
+	header := "<?xml version='1.0'?><stream:stream to='" + hostname + "' xmlns='jabber:client' xmlns:stream='http://etherx.jabber.org/streams' version='1.0'>"
+	if _, err := conn.Write([]byte(header)); err != nil {
+		return errors.Wrap(err, "could not write xmpp stream header")
+	}
+	features, err := readUntil(conn, "</stream:features>")
+	if err != nil {
+		return errors.Wrap(err, "could not read xmpp stream features")
+	}
+	if !strings.Contains(features, "<starttls") {
+		return errors.New("xmpp server does not support starttls")
+	}
+	if _, err := conn.Write([]byte("<starttls xmlns='urn:ietf:params:xml:ns:xmpp-tls'/>")); err != nil {
+		return errors.Wrap(err, "could not write xmpp starttls")
+	}
+	reply, err := readUntil(conn, "/>")
+	if err != nil {
+		return errors.Wrap(err, "could not read xmpp starttls reply")
+	}
+	if !strings.Contains(reply, "<proceed") {
+		return errors.Errorf("could not start xmpp tls: %s", reply)
+	}
+	return nil
+}
+
+// readUntil reads from the connection until the data read contains the marker
+func readUntil(conn net.Conn, marker string) (string, error) {
+	var data []byte
+	buffer := make([]byte, 1024)
+	for !bytes.Contains(data, []byte(marker)) {
+		n, err := conn.Read(buffer)
+		if err != nil {
+			return string(data), err
+		}
+		data = append(data, buffer[:n]...)
+		if len(data) > 64*1024 {
+			return string(data), errors.New("response too large")
+		}
+	}
+	return string(data), nil
+}
+
+// postgresSSLRequest is the SSLRequest message of the PostgreSQL protocol
+var postgresSSLRequest = []byte{0x00, 0x00, 0x00, 0x08, 0x04, 0xd2, 0x16, 0x2f}
+
+// postgresStartTLS implements the SSLRequest negotiation of PostgreSQL
+func postgresStartTLS(conn net.Conn, hostname string, greeted bool) error {
+	if _, err := conn.Write(postgresSSLRequest); err != nil {
+		return errors.Wrap(err, "could not write postgres ssl request")
+	}
+	reply := make([]byte, 1)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return errors.Wrap(err, "could not read postgres ssl reply")
+	}
+	if reply[0] != 'S' {
+		return errors.Errorf("postgres server does not support ssl, got %q", reply)
+	}
+	return nil
+}
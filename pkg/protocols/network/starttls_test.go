@@ -0,0 +1,228 @@
+package network
+
+import (
+	"bufio"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// startTLSServer starts a server performing the server side of a STARTTLS
+// exchange before upgrading the connection and answering "pong" over TLS.
+func startTLSServer(t *testing.T, exchange func(conn net.Conn, reader *bufio.Reader) bool) string {
+	certificate := httptest.NewTLSServer(nil)
+	t.Cleanup(certificate.Close)
+	config := &tls.Config{Certificates: certificate.TLS.Certificates}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen")
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				if !exchange(conn, bufio.NewReader(conn)) {
+					return
+				}
+				tlsConn := tls.Server(conn, config)
+				if _, err := tlsConn.Read(make([]byte, 4)); err != nil {
+					return
+				}
+				_, _ = tlsConn.Write([]byte("pong"))
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func expectLine(reader *bufio.Reader, expected string) bool {
+	line, err := reader.ReadString('\n')
+	return err == nil && strings.TrimRight(line, "\r\n") == expected
+}
+
+func TestStartTLS(t *testing.T) {
+	servers := map[string]func(conn net.Conn, reader *bufio.Reader) bool{
+		"smtp": func(conn net.Conn, reader *bufio.Reader) bool {
+			_, _ = conn.Write([]byte("220-mail.example.com ESMTP\r\n220 ready\r\n"))
+			if !expectLine(reader, "EHLO vulmap") {
+				return false
+			}
+			_, _ = conn.Write([]byte("250-mail.example.com\r\n250-PIPELINING\r\n250 STARTTLS\r\n"))
+			if !expectLine(reader, "STARTTLS") {
+				return false
+			}
+			_, _ = conn.Write([]byte("220 Go ahead\r\n"))
+			return true
+		},
+		"imap": func(conn net.Conn, reader *bufio.Reader) bool {
+			_, _ = conn.Write([]byte("* OK IMAP4rev1 ready\r\n"))
+			if !expectLine(reader, "a001 STARTTLS") {
+				return false
+			}
+			_, _ = conn.Write([]byte("* CAPABILITY IMAP4rev1\r\na001 OK Begin TLS negotiation now\r\n"))
+			return true
+		},
+		"pop3": func(conn net.Conn, reader *bufio.Reader) bool {
+			_, _ = conn.Write([]byte("+OK POP3 ready\r\n"))
+			if !expectLine(reader, "STLS") {
+				return false
+			}
+			_, _ = conn.Write([]byte("+OK Begin TLS\r\n"))
+			return true
+		},
+		"ftp": func(conn net.Conn, reader *bufio.Reader) bool {
+			_, _ = conn.Write([]byte("220 FTP ready\r\n"))
+			if !expectLine(reader, "AUTH TLS") {
+				return false
+			}
+			_, _ = conn.Write([]byte("234 AUTH TLS successful\r\n"))
+			return true
+		},
+		"ldap": func(conn net.Conn, reader *bufio.Reader) bool {
+			request := make([]byte, len(ldapStartTLSRequest))
+			if _, err := io.ReadFull(reader, request); err != nil || string(request) != string(ldapStartTLSRequest) {
+				return false
+			}
+			// ExtendedResponse with resultCode success, empty matchedDN and diagnosticMessage
+			_, _ = conn.Write([]byte{0x30, 0x0c, 0x02, 0x01, 0x01, 0x78, 0x07, 0x0a, 0x01, 0x00, 0x04, 0x00, 0x04, 0x00})
+			return true
+		},
+		"xmpp": func(conn net.Conn, reader *bufio.Reader) bool {
+			if _, err := readUntil(conn, "version='1.0'>"); err != nil {
+				return false
+			}
+			_, _ = conn.Write([]byte("<stream:stream from='example.com' version='1.0'><stream:features><starttls xmlns='urn:ietf:params:xml:ns:xmpp-tls'><required/></starttls></stream:features>"))
+			if _, err := readUntil(conn, "/>"); err != nil {
+				return false
+			}
+			_, _ = conn.Write([]byte("<proceed xmlns='urn:ietf:params:xml:ns:xmpp-tls'/>"))
+			return true
+		},
+		"postgres": func(conn net.Conn, reader *bufio.Reader) bool {
+			request := make([]byte, len(postgresSSLRequest))
+			if _, err := io.ReadFull(reader, request); err != nil || string(request) != string(postgresSSLRequest) {
+				return false
+			}
+			_, _ = conn.Write([]byte("S"))
+			return true
+		},
+	}
+	require.Len(t, servers, len(starttlsProtocols), "not all starttls protocols are tested")
+
+	for protocol, server := range servers {
+		t.Run(protocol, func(t *testing.T) {
+			address := startTLSServer(t, server)
+			conn, err := net.Dial("tcp", address)
+			require.Nil(t, err, "could not connect to server")
+			defer conn.Close()
+			_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+			err = starttlsProtocols[protocol](conn, "example.com", false)
+			require.Nil(t, err, "could not negotiate starttls")
+
+			tlsConn := tls.Client(conn, &tls.Config{InsecureSkipVerify: true, ServerName: "example.com"})
+			_, err = tlsConn.Write([]byte("ping"))
+			require.Nil(t, err, "could not write over tls")
+			response := make([]byte, 4)
+			_, err = io.ReadFull(tlsConn, response)
+			require.Nil(t, err, "could not read over tls")
+			require.Equal(t, "pong", string(response), "could not get response over tls")
+
+			metadata := tlsMetadata(tlsConn, "example.com")
+			require.Equal(t, "tls13", metadata["tls_version"], "could not get tls version")
+			require.Equal(t, "ctls", metadata["tls_connection"], "could not get tls connection")
+			require.Contains(t, metadata["subject_an"], "example.com", "could not get certificate names")
+			require.Equal(t, false, metadata["mismatched"], "could not get mismatched")
+			require.Equal(t, false, metadata["expired"], "could not get expired")
+		})
+	}
+}
+
+func TestStartTLSGreeted(t *testing.T) {
+	address := startTLSServer(t, func(conn net.Conn, reader *bufio.Reader) bool {
+		_, _ = conn.Write([]byte("220 ready\r\n"))
+		if !expectLine(reader, "NOOP") {
+			return false
+		}
+		_, _ = conn.Write([]byte("250 OK\r\n"))
+		if !expectLine(reader, "EHLO vulmap") {
+			return false
+		}
+		_, _ = conn.Write([]byte("250 STARTTLS\r\n"))
+		if !expectLine(reader, "STARTTLS") {
+			return false
+		}
+		_, _ = conn.Write([]byte("220 Go ahead\r\n"))
+		return true
+	})
+	conn, err := net.Dial("tcp", address)
+	require.Nil(t, err, "could not connect to server")
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	// the greeting and the reply to an earlier input are read before starttls
+	input := &Input{ReadUntil: "250 OK\r\n"}
+	require.Nil(t, input.compile(), "could not compile input")
+	_, err = conn.Write([]byte("NOOP\r\n"))
+	require.Nil(t, err, "could not write earlier input")
+	_, err = input.readResponse(conn, map[string]interface{}{})
+	require.Nil(t, err, "could not read earlier input")
+
+	err = smtpStartTLS(conn, "example.com", true)
+	require.Nil(t, err, "could not negotiate starttls after earlier inputs")
+}
+
+func TestStartTLSRefused(t *testing.T) {
+	address := startTLSServer(t, func(conn net.Conn, reader *bufio.Reader) bool {
+		_, _ = conn.Write([]byte("220 ready\r\n"))
+		if !expectLine(reader, "EHLO vulmap") {
+			return false
+		}
+		_, _ = conn.Write([]byte("250-mail.example.com\r\n250 PIPELINING\r\n"))
+		return false
+	})
+	conn, err := net.Dial("tcp", address)
+	require.Nil(t, err, "could not connect to server")
+	defer conn.Close()
+
+	err = smtpStartTLS(conn, "example.com", false)
+	require.NotNil(t, err, "could negotiate starttls with server not supporting it")
+
+	require.Nil(t, tlsMetadata(conn, "example.com"), "could get tls metadata of plaintext connection")
+}
+
+func TestHandshakeTLS(t *testing.T) {
+	address := startTLSServer(t, func(conn net.Conn, reader *bufio.Reader) bool {
+		return true
+	})
+	config := &tls.Config{InsecureSkipVerify: true, ServerName: "example.com", MinVersion: tls.VersionTLS10}
+
+	for connection, useZTLS := range map[string]bool{"ctls": false, "ztls": true} {
+		conn, err := net.Dial("tcp", address)
+		require.Nil(t, err, "could not connect to server")
+		_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+		tlsConn, err := handshakeTLS(conn, config, useZTLS)
+		require.Nil(t, err, "could not perform %s handshake", connection)
+		_, err = tlsConn.Write([]byte("ping"))
+		require.Nil(t, err, "could not write over %s", connection)
+		data, err := io.ReadAll(tlsConn)
+		require.Nil(t, err, "could not read over %s", connection)
+		require.Equal(t, "pong", string(data))
+
+		metadata := tlsMetadata(tlsConn, "example.com")
+		require.Equal(t, connection, metadata["tls_connection"], "could not get tls connection")
+		_ = conn.Close()
+	}
+}
@@ -0,0 +1,232 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	ztls "github.com/zmap/zcrypto/tls"
+
+	protocolutils "github.com/khulnasoft-lab/vulmap/pkg/protocols/utils"
+)
+
+// tlsVersions contains the names of the tls versions as used by the ssl protocol
+var tlsVersions = map[uint16]string{
+	0x0300:           "ssl30",
+	tls.VersionTLS10: "tls10",
+	tls.VersionTLS11: "tls11",
+	tls.VersionTLS12: "tls12",
+	tls.VersionTLS13: "tls13",
+}
+
+// recordingConn records the data exchanged on a connection
+type recordingConn struct {
+	net.Conn
+	sent     bytes.Buffer
+	received bytes.Buffer
+}
+
+func (c *recordingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.received.Write(b[:n])
+	return n, err
+}
+
+func (c *recordingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.sent.Write(b[:n])
+	return n, err
+}
+
+// startTLS performs the STARTTLS exchange of the protocol on the connection and
+// upgrades it to TLS. The data exchanged before the handshake is returned in the
+// recorder. started is true if earlier inputs were sent on the connection and
+// greeted if they read from it, in which case the greeting is not read again.
+//
+// Like the tls dials of the engine, the handshake uses the sni and client
+// certificates of the options and starts with ztls if -ztls is set. When no
+// earlier inputs were sent, a failed handshake is retried with the other library
+// on a new connection on which the STARTTLS exchange is performed and recorded
+// again. The conversation of earlier inputs cannot be replayed otherwise.
+func (request *Request) startTLS(conn net.Conn, protocol, address, hostname string, started, greeted bool) (net.Conn, *recordingConn, error) {
+	exchange := starttlsProtocols[protocol]
+	recorder := &recordingConn{Conn: conn}
+	if err := exchange(recorder, hostname, greeted); err != nil {
+		return nil, recorder, errors.Wrapf(err, "could not negotiate %s starttls", protocol)
+	}
+
+	config, err := request.tlsConfig(hostname)
+	if err != nil {
+		_ = conn.Close()
+		return nil, recorder, err
+	}
+	useZTLS := request.options.Options.ZTLS //nolint:staticcheck
+	tlsConn, err := handshakeTLS(conn, config, useZTLS)
+	if err == nil {
+		return tlsConn, recorder, nil
+	}
+	_ = conn.Close()
+	if started {
+		return nil, recorder, errors.Wrap(err, "could not perform tls handshake")
+	}
+
+	conn, dialErr := request.dialer.Dial(context.Background(), "tcp", address)
+	if dialErr != nil {
+		return nil, recorder, errors.Wrap(err, "could not perform tls handshake")
+	}
+	_ = conn.SetDeadline(time.Now().Add(time.Duration(request.options.Options.Timeout) * time.Second))
+	recorder = &recordingConn{Conn: conn}
+	if exchangeErr := exchange(recorder, hostname, false); exchangeErr != nil {
+		_ = conn.Close()
+		return nil, recorder, errors.Wrap(err, "could not perform tls handshake")
+	}
+	tlsConn, fallbackErr := handshakeTLS(conn, config, !useZTLS)
+	if fallbackErr != nil {
+		_ = conn.Close()
+		return nil, recorder, errors.Wrapf(err, "could not perform tls handshake (fallback: %s)", fallbackErr)
+	}
+	return tlsConn, recorder, nil
+}
+
+// tlsConfig returns the tls configuration of the STARTTLS upgrades
+// built like the one of the tls dials of the engine.
+func (request *Request) tlsConfig(hostname string) (*tls.Config, error) {
+	config := &tls.Config{
+		Renegotiation:      tls.RenegotiateOnceAsClient,
+		InsecureSkipVerify: true,
+		ServerName:         hostname,
+		MinVersion:         tls.VersionTLS10,
+	}
+	if request.options.Options.SNI != "" {
+		config.ServerName = request.options.Options.SNI
+	}
+	config, err := protocolutils.AddConfiguredClientCertToRequest(config, request.options.Options)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create client certificate")
+	}
+	return config, nil
+}
+
+// handshakeTLS performs the tls handshake on the connection with
+// the standard library or with ztls, which also supports ssl30.
+func handshakeTLS(conn net.Conn, config *tls.Config, useZTLS bool) (net.Conn, error) {
+	if !useZTLS {
+		tlsConn := tls.Client(conn, config.Clone())
+		if err := tlsConn.Handshake(); err != nil {
+			return nil, err
+		}
+		return tlsConn, nil
+	}
+	ztlsConfig := &ztls.Config{
+		InsecureSkipVerify: config.InsecureSkipVerify,
+		ServerName:         config.ServerName,
+		MinVersion:         ztls.VersionSSL30,
+	}
+	for _, certificate := range config.Certificates {
+		ztlsConfig.Certificates = append(ztlsConfig.Certificates, ztls.Certificate{
+			Certificate: certificate.Certificate,
+			PrivateKey:  certificate.PrivateKey,
+		})
+	}
+	ztlsConn := ztls.Client(conn, ztlsConfig)
+	if err := ztlsConn.Handshake(); err != nil {
+		return nil, err
+	}
+	return ztlsConn, nil
+}
+
+// tlsMetadata returns the metadata of the tls connection using the field
+// names of the ssl protocol. Nil is returned for plaintext connections.
+func tlsMetadata(conn net.Conn, hostname string) map[string]interface{} {
+	var (
+		version, cipherSuite uint16
+		certificates         [][]byte
+		tlsConnection        string
+		serverName           string
+	)
+	switch tlsConn := unwrapConn(conn).(type) {
+	case *tls.Conn:
+		state := tlsConn.ConnectionState()
+		version, cipherSuite, serverName = state.Version, state.CipherSuite, state.ServerName
+		for _, certificate := range state.PeerCertificates {
+			certificates = append(certificates, certificate.Raw)
+		}
+		tlsConnection = "ctls"
+	case *ztls.Conn:
+		state := tlsConn.ConnectionState()
+		version, cipherSuite, serverName = state.Version, state.CipherSuite, state.ServerName
+		for _, certificate := range state.PeerCertificates {
+			certificates = append(certificates, certificate.Raw)
+		}
+		tlsConnection = "ztls"
+	default:
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"tls_version":    tlsVersions[version],
+		"cipher":         tls.CipherSuiteName(cipherSuite),
+		"tls_connection": tlsConnection,
+		"sni":            serverName,
+	}
+	if len(certificates) == 0 {
+		return data
+	}
+	// ztls accepts certificates the standard library cannot parse, in which
+	// case only the connection metadata is available.
+	certificate, err := x509.ParseCertificate(certificates[0])
+	if err != nil {
+		return data
+	}
+	for k, v := range certificateMetadata(certificate, hostname) {
+		data[k] = v
+	}
+	return data
+}
+
+// certificateMetadata returns the fields of the leaf certificate
+func certificateMetadata(certificate *x509.Certificate, hostname string) map[string]interface{} {
+	md5Hash := md5.Sum(certificate.Raw)
+	sha1Hash := sha1.Sum(certificate.Raw)
+	sha256Hash := sha256.Sum256(certificate.Raw)
+
+	selfSigned := bytes.Equal(certificate.RawSubject, certificate.RawIssuer) && certificate.CheckSignatureFrom(certificate) == nil
+	return map[string]interface{}{
+		"subject_dn":  certificate.Subject.String(),
+		"subject_cn":  certificate.Subject.CommonName,
+		"subject_org": certificate.Subject.Organization,
+		"subject_an":  certificate.DNSNames,
+		"issuer_dn":   certificate.Issuer.String(),
+		"issuer_cn":   certificate.Issuer.CommonName,
+		"issuer_org":  certificate.Issuer.Organization,
+		"serial":      formatSerial(certificate.SerialNumber.Bytes()),
+		"not_before":  certificate.NotBefore,
+		"not_after":   certificate.NotAfter,
+		"expired":     time.Now().After(certificate.NotAfter),
+		"self_signed": selfSigned,
+		"mismatched":  certificate.VerifyHostname(hostname) != nil,
+		"fingerprint_hash": map[string]interface{}{
+			"md5":    hex.EncodeToString(md5Hash[:]),
+			"sha1":   hex.EncodeToString(sha1Hash[:]),
+			"sha256": hex.EncodeToString(sha256Hash[:]),
+		},
+	}
+}
+
+// formatSerial formats the serial number as colon separated hex bytes
+func formatSerial(serial []byte) string {
+	parts := make([]string, 0, len(serial))
+	for _, b := range serial {
+		parts = append(parts, strings.ToUpper(hex.EncodeToString([]byte{b})))
+	}
+	return strings.Join(parts, ":")
+}
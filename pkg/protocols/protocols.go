@@ -9,6 +9,7 @@ import (
 
 	"github.com/logrusorgru/aurora"
 
+	"github.com/khulnasoft-lab/vulmap/pkg/authprovider"
 	"github.com/khulnasoft-lab/vulmap/pkg/catalog"
 	"github.com/khulnasoft-lab/vulmap/pkg/input"
 	"github.com/khulnasoft-lab/vulmap/pkg/js/compiler"
@@ -20,7 +21,9 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/progress"
 	"github.com/khulnasoft-lab/vulmap/pkg/projectfile"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/hostconcurrency"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/hosterrorscache"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/hostratelimit"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/interactsh"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/utils/excludematchers"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/variables"
@@ -60,6 +63,10 @@ type ExecutorOptions struct {
 	Progress progress.Progress
 	// RateLimiter is a rate-limiter for limiting sent number of requests.
 	RateLimiter *ratelimit.Limiter
+	// HostRateLimiter is an optional adaptive per-host rate-limiter applied under the RateLimiter.
+	HostRateLimiter *hostratelimit.Limiter
+	// HostConcurrency is an optional cap of requests in flight per host shared across templates and protocols.
+	HostConcurrency *hostconcurrency.Limiter
 	// Catalog is a template catalog implementation for vulmap
 	Catalog catalog.Catalog
 	// ProjectFile is the project file for vulmap
@@ -101,6 +108,8 @@ type ExecutorOptions struct {
 	// JsCompiler is abstracted javascript compiler which adds node modules and provides execution
 	// environment for javascript templates
 	JsCompiler *compiler.Compiler
+	// AuthProvider is an optional provider of secrets for authenticated scans
+	AuthProvider authprovider.AuthProvider
 }
 
 // CreateTemplateCtxStore creates template context store (which contains templateCtx for every scan)
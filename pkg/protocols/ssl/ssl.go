@@ -215,7 +215,9 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 		hostIp = host
 	}
 
+	release := request.options.HostConcurrency.Acquire(hostPort)
 	response, err := request.tlsx.Connect(host, hostIp, port)
+	release()
 	if err != nil {
 		requestOptions.Output.Request(requestOptions.TemplateID, input.MetaInput.Input, request.Type().String(), err)
 		requestOptions.Progress.IncrementFailedRequestsBy(1)
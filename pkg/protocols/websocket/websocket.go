@@ -226,8 +226,18 @@ func (request *Request) executeRequestWithPayloads(target *contextargs.Context,
 		return errors.Wrap(err, parseUrlErrorMessage)
 	}
 	parsedAddress.Path = path.Join(parsedAddress.Path, parsed.Path)
+	if requestOptions.AuthProvider != nil {
+		// the handshake headers are shared with the dialer
+		authRequest := &http.Request{Method: http.MethodGet, URL: parsedAddress, Header: header}
+		for _, strategy := range requestOptions.AuthProvider.LookupURL(parsedAddress) {
+			strategy.Apply(authRequest)
+		}
+	}
 	addressToDial = parsedAddress.String()
 
+	release := requestOptions.HostConcurrency.Acquire(addressToDial)
+	defer release()
+
 	conn, readBuffer, _, err := websocketDialer.Dial(context.Background(), addressToDial)
 	if err != nil {
 		requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), err)
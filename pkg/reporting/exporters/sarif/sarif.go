@@ -5,6 +5,7 @@ import (
 	"math"
 	"os"
 	"path"
+	"strings"
 	"sync"
 
 	"github.com/pkg/errors"
@@ -158,7 +159,7 @@ func (exporter *Exporter) Export(event *output.ResultEvent) error {
 		Level:     resultLevel,
 		Kind:      sarif.Open,
 		Message: &sarif.Message{
-			Text: resultHeader,
+			Text: resultHeader + redirectChainText(event.RedirectChain),
 		},
 		Locations: []sarif.Location{location},
 		Rule: sarif.ReportingDescriptorReference{
@@ -172,6 +173,22 @@ func (exporter *Exporter) Export(event *output.ResultEvent) error {
 
 }
 
+// redirectChainText returns the hops of the redirect chain of the result
+func redirectChainText(chain []output.RedirectHop) string {
+	if len(chain) == 0 {
+		return ""
+	}
+	builder := &strings.Builder{}
+	builder.WriteString("\n\nRedirect chain:")
+	for i, hop := range chain {
+		builder.WriteString(fmt.Sprintf("\n%d. %s %s (%d)", i+1, hop.Method, hop.URL, hop.StatusCode))
+		if hop.Location != "" {
+			builder.WriteString(" -> " + hop.Location)
+		}
+	}
+	return builder.String()
+}
+
 // Close Writes data and closes the exporter after operation
 func (exporter *Exporter) Close() error {
 	exporter.mutex.Lock()
@@ -66,6 +66,12 @@ func CreateReportDescription(event *output.ResultEvent, formatter ResultFormatte
 		}
 		builder.WriteString(formatter.CreateCodeBlock("Response", responseString, "http"))
 	}
+	if len(event.RedirectChain) > 0 {
+		builder.WriteString("\n")
+		builder.WriteString(formatter.MakeBold("Redirect Chain"))
+		builder.WriteString("\n\n")
+		builder.WriteString(CreateRedirectChainTable(event.RedirectChain, formatter))
+	}
 
 	if len(event.ExtractedResults) > 0 || len(event.Metadata) > 0 {
 		builder.WriteString("\n")
@@ -179,6 +185,24 @@ func CreateTemplateInfoTable(templateInfo *model.Info, formatter ResultFormatter
 	return table
 }
 
+// CreateRedirectChainTable returns a table of the hops of a redirect chain
+func CreateRedirectChainTable(chain []output.RedirectHop, formatter ResultFormatter) string {
+	rows := make([][]string, 0, len(chain))
+	for i, hop := range chain {
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1),
+			hop.Method,
+			hop.URL,
+			strconv.Itoa(hop.StatusCode),
+			hop.Location,
+			strconv.FormatFloat(hop.Duration, 'f', 3, 64) + "s",
+		})
+	}
+	table, _ := formatter.CreateTable([]string{"Hop", "Method", "URL", "Status", "Location", "Duration"}, rows)
+
+	return table
+}
+
 func generateCVSSMetricsFromClassification(classification *model.Classification) string {
 	var cvssLinkPrefix string
 	if strings.Contains(classification.CVSSMetrics, "CVSS:3.0") {
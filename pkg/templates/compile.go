@@ -127,7 +127,8 @@ func (template *Template) Requests() int {
 		len(template.RequestsWebsocket) +
 		len(template.RequestsWHOIS) +
 		len(template.RequestsCode) +
-		len(template.RequestsJavascript)
+		len(template.RequestsJavascript) +
+		len(template.RequestsGRPC)
 }
 
 // compileProtocolRequests compiles all the protocol requests for the template
@@ -182,6 +183,9 @@ func (template *Template) compileProtocolRequests(options protocols.ExecutorOpti
 		if len(template.RequestsJavascript) > 0 {
 			requests = append(requests, template.convertRequestToProtocolsRequest(template.RequestsJavascript)...)
 		}
+		if len(template.RequestsGRPC) > 0 {
+			requests = append(requests, template.convertRequestToProtocolsRequest(template.RequestsGRPC)...)
+		}
 	}
 	template.Executer = tmplexec.NewTemplateExecuter(requests, &options)
 	return nil
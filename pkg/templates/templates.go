@@ -15,6 +15,7 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/variables"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/dns"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/file"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/grpc"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/headless"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/javascript"
@@ -117,6 +118,9 @@ type Template struct {
 	// description: |
 	//   Javascript contains the javascript request to make in the template.
 	RequestsJavascript []*javascript.Request `yaml:"javascript,omitempty" json:"javascript,omitempty" jsonschema:"title=javascript requests to make,description=Javascript requests to make for the template"`
+	// description: |
+	//   GRPC contains the gRPC request to make in the template.
+	RequestsGRPC []*grpc.Request `yaml:"grpc,omitempty" json:"grpc,omitempty" jsonschema:"title=grpc requests to make,description=gRPC requests to make for the template"`
 
 	// description: |
 	//   Workflows is a yaml based workflow declaration code.
@@ -134,7 +138,10 @@ type Template struct {
 	//   Signature is the request signature method
 	// values:
 	//   - "AWS"
-	Signature http.SignatureTypeHolder `yaml:"signature,omitempty" json:"signature,omitempty" jsonschema:"title=signature is the http request signature method,description=Signature is the HTTP Request signature Method,enum=AWS"`
+	//   - "AZURE-SHARED-KEY"
+	//   - "GCP-HMAC"
+	//   - "HMAC"
+	Signature http.SignatureTypeHolder `yaml:"signature,omitempty" json:"signature,omitempty" jsonschema:"title=signature is the http request signature method,description=Signature is the HTTP Request signature Method,enum=AWS,enum=AZURE-SHARED-KEY,enum=GCP-HMAC,enum=HMAC"`
 
 	// description: |
 	//   Variables contains any variables for the current request.
@@ -186,6 +193,8 @@ func (template *Template) Type() types.ProtocolType {
 		return types.CodeProtocol
 	case len(template.RequestsJavascript) > 0:
 		return types.JavascriptProtocol
+	case len(template.RequestsGRPC) > 0:
+		return types.GRPCProtocol
 	default:
 		return types.InvalidProtocol
 	}
@@ -274,6 +283,13 @@ func (template *Template) validateAllRequestIDs() {
 			}
 		}
 	}
+	if len(template.RequestsGRPC) > 1 {
+		for i, req := range template.RequestsGRPC {
+			if req.ID == "" {
+				req.ID = req.Type().String() + "_" + strconv.Itoa(i+1)
+			}
+		}
+	}
 }
 
 // MarshalYAML forces recursive struct validation during marshal operation
@@ -432,6 +448,8 @@ func (template *Template) addRequestsToQueue(keys ...string) {
 			template.RequestsQueue = append(template.RequestsQueue, template.convertRequestToProtocolsRequest(template.RequestsCode)...)
 		case types.JavascriptProtocol.String():
 			template.RequestsQueue = append(template.RequestsQueue, template.convertRequestToProtocolsRequest(template.RequestsJavascript)...)
+		case types.GRPCProtocol.String():
+			template.RequestsQueue = append(template.RequestsQueue, template.convertRequestToProtocolsRequest(template.RequestsGRPC)...)
 			// for deprecated protocols
 		case "requests":
 			template.RequestsQueue = append(template.RequestsQueue, template.convertRequestToProtocolsRequest(template.RequestsHTTP)...)
@@ -448,7 +466,8 @@ func (template *Template) hasMultipleRequests() bool {
 		len(template.RequestsHTTP) + len(template.RequestsHeadless) +
 		len(template.RequestsNetwork) + len(template.RequestsSSL) +
 		len(template.RequestsWebsocket) + len(template.RequestsWHOIS) +
-		len(template.RequestsCode) + len(template.RequestsJavascript)
+		len(template.RequestsCode) + len(template.RequestsJavascript) +
+		len(template.RequestsGRPC)
 	return counter > 1
 }
 
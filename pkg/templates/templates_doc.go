@@ -26,6 +26,7 @@ var (
 	NETWORKRequestDoc             encoder.Doc
 	NETWORKInputDoc               encoder.Doc
 	NetworkInputTypeHolderDoc     encoder.Doc
+	NETWORKReadFrameDoc           encoder.Doc
 	HEADLESSRequestDoc            encoder.Doc
 	ENGINEActionDoc               encoder.Doc
 	ActionTypeHolderDoc           encoder.Doc
@@ -36,6 +37,7 @@ var (
 	WHOISRequestDoc               encoder.Doc
 	CODERequestDoc                encoder.Doc
 	JAVASCRIPTRequestDoc          encoder.Doc
+	GRPCRequestDoc                encoder.Doc
 	HTTPSignatureTypeHolderDoc    encoder.Doc
 	VARIABLESVariableDoc          encoder.Doc
 )
@@ -44,7 +46,7 @@ func init() {
 	TemplateDoc.Type = "Template"
 	TemplateDoc.Comments[encoder.LineComment] = " Template is a YAML input file which defines all the requests and"
 	TemplateDoc.Description = "Template is a YAML input file which defines all the requests and\n other metadata for a template."
-	TemplateDoc.Fields = make([]encoder.Doc, 20)
+	TemplateDoc.Fields = make([]encoder.Doc, 21)
 	TemplateDoc.Fields[0].Name = "id"
 	TemplateDoc.Fields[0].Type = "string"
 	TemplateDoc.Fields[0].Note = ""
@@ -132,34 +134,42 @@ func init() {
 	TemplateDoc.Fields[14].Note = ""
 	TemplateDoc.Fields[14].Description = "Javascript contains the javascript request to make in the template."
 	TemplateDoc.Fields[14].Comments[encoder.LineComment] = "Javascript contains the javascript request to make in the template."
-	TemplateDoc.Fields[15].Name = "self-contained"
-	TemplateDoc.Fields[15].Type = "bool"
+	TemplateDoc.Fields[15].Name = "grpc"
+	TemplateDoc.Fields[15].Type = "[]grpc.Request"
 	TemplateDoc.Fields[15].Note = ""
-	TemplateDoc.Fields[15].Description = "Self Contained marks Requests for the template as self-contained"
-	TemplateDoc.Fields[15].Comments[encoder.LineComment] = "Self Contained marks Requests for the template as self-contained"
-	TemplateDoc.Fields[16].Name = "stop-at-first-match"
+	TemplateDoc.Fields[15].Description = "GRPC contains the gRPC request to make in the template."
+	TemplateDoc.Fields[15].Comments[encoder.LineComment] = "GRPC contains the gRPC request to make in the template."
+	TemplateDoc.Fields[16].Name = "self-contained"
 	TemplateDoc.Fields[16].Type = "bool"
 	TemplateDoc.Fields[16].Note = ""
-	TemplateDoc.Fields[16].Description = "Stop execution once first match is found"
-	TemplateDoc.Fields[16].Comments[encoder.LineComment] = "Stop execution once first match is found"
-	TemplateDoc.Fields[17].Name = "signature"
-	TemplateDoc.Fields[17].Type = "http.SignatureTypeHolder"
+	TemplateDoc.Fields[16].Description = "Self Contained marks Requests for the template as self-contained"
+	TemplateDoc.Fields[16].Comments[encoder.LineComment] = "Self Contained marks Requests for the template as self-contained"
+	TemplateDoc.Fields[17].Name = "stop-at-first-match"
+	TemplateDoc.Fields[17].Type = "bool"
 	TemplateDoc.Fields[17].Note = ""
-	TemplateDoc.Fields[17].Description = "Signature is the request signature method"
-	TemplateDoc.Fields[17].Comments[encoder.LineComment] = "Signature is the request signature method"
-	TemplateDoc.Fields[17].Values = []string{
+	TemplateDoc.Fields[17].Description = "Stop execution once first match is found"
+	TemplateDoc.Fields[17].Comments[encoder.LineComment] = "Stop execution once first match is found"
+	TemplateDoc.Fields[18].Name = "signature"
+	TemplateDoc.Fields[18].Type = "http.SignatureTypeHolder"
+	TemplateDoc.Fields[18].Note = ""
+	TemplateDoc.Fields[18].Description = "Signature is the request signature method"
+	TemplateDoc.Fields[18].Comments[encoder.LineComment] = "Signature is the request signature method"
+	TemplateDoc.Fields[18].Values = []string{
 		"AWS",
+		"AZURE-SHARED-KEY",
+		"GCP-HMAC",
+		"HMAC",
 	}
-	TemplateDoc.Fields[18].Name = "variables"
-	TemplateDoc.Fields[18].Type = "variables.Variable"
-	TemplateDoc.Fields[18].Note = ""
-	TemplateDoc.Fields[18].Description = "Variables contains any variables for the current request."
-	TemplateDoc.Fields[18].Comments[encoder.LineComment] = "Variables contains any variables for the current request."
-	TemplateDoc.Fields[19].Name = "constants"
-	TemplateDoc.Fields[19].Type = "map[string]interface{}"
+	TemplateDoc.Fields[19].Name = "variables"
+	TemplateDoc.Fields[19].Type = "variables.Variable"
 	TemplateDoc.Fields[19].Note = ""
-	TemplateDoc.Fields[19].Description = "Constants contains any scalar constant for the current template"
-	TemplateDoc.Fields[19].Comments[encoder.LineComment] = "Constants contains any scalar constant for the current template"
+	TemplateDoc.Fields[19].Description = "Variables contains any variables for the current request."
+	TemplateDoc.Fields[19].Comments[encoder.LineComment] = "Variables contains any variables for the current request."
+	TemplateDoc.Fields[20].Name = "constants"
+	TemplateDoc.Fields[20].Type = "map[string]interface{}"
+	TemplateDoc.Fields[20].Note = ""
+	TemplateDoc.Fields[20].Description = "Constants contains any scalar constant for the current template"
+	TemplateDoc.Fields[20].Comments[encoder.LineComment] = "Constants contains any scalar constant for the current template"
 
 	MODELInfoDoc.Type = "model.Info"
 	MODELInfoDoc.Comments[encoder.LineComment] = " Info contains metadata information about a template"
@@ -443,6 +453,14 @@ func init() {
 			Key:   "duration",
 			Value: "HTTP request time duration",
 		},
+		{
+			Key:   "redirect_chain",
+			Value: "HTTP redirect chain hops in status url -> location format",
+		},
+		{
+			Key:   "redirect_count",
+			Value: "HTTP redirects followed for the response",
+		},
 		{
 			Key:   "all",
 			Value: "HTTP response body + headers",
@@ -456,7 +474,7 @@ func init() {
 			Value: "HTTP response headers in name:value format",
 		},
 	}
-	HTTPRequestDoc.Fields = make([]encoder.Doc, 31)
+	HTTPRequestDoc.Fields = make([]encoder.Doc, 38)
 	HTTPRequestDoc.Fields[0].Name = "path"
 	HTTPRequestDoc.Fields[0].Type = "[]string"
 	HTTPRequestDoc.Fields[0].Note = ""
@@ -569,6 +587,9 @@ func init() {
 	HTTPRequestDoc.Fields[16].Comments[encoder.LineComment] = "Signature is the request signature method"
 	HTTPRequestDoc.Fields[16].Values = []string{
 		"AWS",
+		"AZURE-SHARED-KEY",
+		"GCP-HMAC",
+		"HMAC",
 	}
 	HTTPRequestDoc.Fields[17].Name = "cookie-reuse"
 	HTTPRequestDoc.Fields[17].Type = "bool"
@@ -590,56 +611,101 @@ func init() {
 	HTTPRequestDoc.Fields[20].Note = ""
 	HTTPRequestDoc.Fields[20].Description = "Redirects specifies whether only redirects to the same host should be followed by the HTTP Client.\n\nThis can be used in conjunction with `max-redirects` to control the HTTP request redirects."
 	HTTPRequestDoc.Fields[20].Comments[encoder.LineComment] = "Redirects specifies whether only redirects to the same host should be followed by the HTTP Client."
-	HTTPRequestDoc.Fields[21].Name = "pipeline"
-	HTTPRequestDoc.Fields[21].Type = "bool"
+	HTTPRequestDoc.Fields[21].Name = "protocol"
+	HTTPRequestDoc.Fields[21].Type = "string"
 	HTTPRequestDoc.Fields[21].Note = ""
-	HTTPRequestDoc.Fields[21].Description = "Pipeline defines if the attack should be performed with HTTP 1.1 Pipelining\n\nAll requests must be idempotent (GET/POST). This can be used for race conditions/billions requests."
-	HTTPRequestDoc.Fields[21].Comments[encoder.LineComment] = "Pipeline defines if the attack should be performed with HTTP 1.1 Pipelining"
-	HTTPRequestDoc.Fields[22].Name = "unsafe"
+	HTTPRequestDoc.Fields[21].Description = "Protocol is the HTTP protocol used to send the requests.\n\nh3 sends the requests over HTTP/3 (QUIC), alt-svc sends the requests over HTTP/3\nto the hosts advertising it with the Alt-Svc header. h2c upgrades the connection to\ncleartext HTTP/2 with the first request and sends the next requests over the upgraded\nconnection, h2c-prior-knowledge sends the requests over cleartext HTTP/2 without upgrade.\nHTTP/1.1 or HTTP/2 is used by default."
+	HTTPRequestDoc.Fields[21].Comments[encoder.LineComment] = "Protocol is the HTTP protocol used to send the requests."
+	HTTPRequestDoc.Fields[21].Values = []string{
+		"h3",
+		"alt-svc",
+		"h2c",
+		"h2c-prior-knowledge",
+	}
+	HTTPRequestDoc.Fields[22].Name = "pipeline"
 	HTTPRequestDoc.Fields[22].Type = "bool"
 	HTTPRequestDoc.Fields[22].Note = ""
-	HTTPRequestDoc.Fields[22].Description = "Unsafe specifies whether to use rawhttp engine for sending Non RFC-Compliant requests.\n\nThis uses the [rawhttp](https://github.com/khulnasoft-lab/rawhttp) engine to achieve complete\ncontrol over the request, with no normalization performed by the client."
-	HTTPRequestDoc.Fields[22].Comments[encoder.LineComment] = "Unsafe specifies whether to use rawhttp engine for sending Non RFC-Compliant requests."
-	HTTPRequestDoc.Fields[23].Name = "race"
+	HTTPRequestDoc.Fields[22].Description = "Pipeline defines if the attack should be performed with HTTP 1.1 Pipelining\n\nAll requests must be idempotent (GET/POST). This can be used for race conditions/billions requests."
+	HTTPRequestDoc.Fields[22].Comments[encoder.LineComment] = "Pipeline defines if the attack should be performed with HTTP 1.1 Pipelining"
+	HTTPRequestDoc.Fields[23].Name = "unsafe"
 	HTTPRequestDoc.Fields[23].Type = "bool"
 	HTTPRequestDoc.Fields[23].Note = ""
-	HTTPRequestDoc.Fields[23].Description = "Race determines if all the request have to be attempted at the same time (Race Condition)\n\nThe actual number of requests that will be sent is determined by the `race_count`  field."
-	HTTPRequestDoc.Fields[23].Comments[encoder.LineComment] = "Race determines if all the request have to be attempted at the same time (Race Condition)"
-	HTTPRequestDoc.Fields[24].Name = "req-condition"
+	HTTPRequestDoc.Fields[23].Description = "Unsafe specifies whether to use rawhttp engine for sending Non RFC-Compliant requests.\n\nThis uses the [rawhttp](https://github.com/khulnasoft-lab/rawhttp) engine to achieve complete\ncontrol over the request, with no normalization performed by the client."
+	HTTPRequestDoc.Fields[23].Comments[encoder.LineComment] = "Unsafe specifies whether to use rawhttp engine for sending Non RFC-Compliant requests."
+	HTTPRequestDoc.Fields[24].Name = "race"
 	HTTPRequestDoc.Fields[24].Type = "bool"
 	HTTPRequestDoc.Fields[24].Note = ""
-	HTTPRequestDoc.Fields[24].Description = "ReqCondition automatically assigns numbers to requests and preserves their history.\n\nThis allows matching on them later for multi-request conditions."
-	HTTPRequestDoc.Fields[24].Comments[encoder.LineComment] = "ReqCondition automatically assigns numbers to requests and preserves their history."
-	HTTPRequestDoc.Fields[25].Name = "stop-at-first-match"
-	HTTPRequestDoc.Fields[25].Type = "bool"
+	HTTPRequestDoc.Fields[24].Description = "Race determines if all the request have to be attempted at the same time (Race Condition)\n\nThe actual number of requests that will be sent is determined by the `race_count`  field."
+	HTTPRequestDoc.Fields[24].Comments[encoder.LineComment] = "Race determines if all the request have to be attempted at the same time (Race Condition)"
+	HTTPRequestDoc.Fields[25].Name = "race-strategy"
+	HTTPRequestDoc.Fields[25].Type = "string"
 	HTTPRequestDoc.Fields[25].Note = ""
-	HTTPRequestDoc.Fields[25].Description = "StopAtFirstMatch stops the execution of the requests and template as soon as a match is found."
-	HTTPRequestDoc.Fields[25].Comments[encoder.LineComment] = "StopAtFirstMatch stops the execution of the requests and template as soon as a match is found."
-	HTTPRequestDoc.Fields[26].Name = "skip-variables-check"
+	HTTPRequestDoc.Fields[25].Description = "RaceStrategy is the strategy used to synchronise the race condition requests.\n\nsingle-packet holds back the final DATA frame of the requests sent over a single HTTP/2\nconnection and flushes them together, falling back to last-byte for hosts not supporting HTTP/2.\nlast-byte holds back the last byte of the requests sent over their own HTTP/1.1 connections\nand sends them together."
+	HTTPRequestDoc.Fields[25].Comments[encoder.LineComment] = "RaceStrategy is the strategy used to synchronise the race condition requests."
+	HTTPRequestDoc.Fields[25].Values = []string{
+		"single-packet",
+		"last-byte",
+	}
+	HTTPRequestDoc.Fields[26].Name = "req-condition"
 	HTTPRequestDoc.Fields[26].Type = "bool"
 	HTTPRequestDoc.Fields[26].Note = ""
-	HTTPRequestDoc.Fields[26].Description = "SkipVariablesCheck skips the check for unresolved variables in request"
-	HTTPRequestDoc.Fields[26].Comments[encoder.LineComment] = "SkipVariablesCheck skips the check for unresolved variables in request"
-	HTTPRequestDoc.Fields[27].Name = "iterate-all"
+	HTTPRequestDoc.Fields[26].Description = "ReqCondition automatically assigns numbers to requests and preserves their history.\n\nThis allows matching on them later for multi-request conditions."
+	HTTPRequestDoc.Fields[26].Comments[encoder.LineComment] = "ReqCondition automatically assigns numbers to requests and preserves their history."
+	HTTPRequestDoc.Fields[27].Name = "stop-at-first-match"
 	HTTPRequestDoc.Fields[27].Type = "bool"
 	HTTPRequestDoc.Fields[27].Note = ""
-	HTTPRequestDoc.Fields[27].Description = "IterateAll iterates all the values extracted from internal extractors"
-	HTTPRequestDoc.Fields[27].Comments[encoder.LineComment] = "IterateAll iterates all the values extracted from internal extractors"
-	HTTPRequestDoc.Fields[28].Name = "digest-username"
-	HTTPRequestDoc.Fields[28].Type = "string"
+	HTTPRequestDoc.Fields[27].Description = "StopAtFirstMatch stops the execution of the requests and template as soon as a match is found."
+	HTTPRequestDoc.Fields[27].Comments[encoder.LineComment] = "StopAtFirstMatch stops the execution of the requests and template as soon as a match is found."
+	HTTPRequestDoc.Fields[28].Name = "skip-variables-check"
+	HTTPRequestDoc.Fields[28].Type = "bool"
 	HTTPRequestDoc.Fields[28].Note = ""
-	HTTPRequestDoc.Fields[28].Description = "DigestAuthUsername specifies the username for digest authentication"
-	HTTPRequestDoc.Fields[28].Comments[encoder.LineComment] = "DigestAuthUsername specifies the username for digest authentication"
-	HTTPRequestDoc.Fields[29].Name = "digest-password"
-	HTTPRequestDoc.Fields[29].Type = "string"
+	HTTPRequestDoc.Fields[28].Description = "SkipVariablesCheck skips the check for unresolved variables in request"
+	HTTPRequestDoc.Fields[28].Comments[encoder.LineComment] = "SkipVariablesCheck skips the check for unresolved variables in request"
+	HTTPRequestDoc.Fields[29].Name = "iterate-all"
+	HTTPRequestDoc.Fields[29].Type = "bool"
 	HTTPRequestDoc.Fields[29].Note = ""
-	HTTPRequestDoc.Fields[29].Description = "DigestAuthPassword specifies the password for digest authentication"
-	HTTPRequestDoc.Fields[29].Comments[encoder.LineComment] = "DigestAuthPassword specifies the password for digest authentication"
-	HTTPRequestDoc.Fields[30].Name = "disable-path-automerge"
-	HTTPRequestDoc.Fields[30].Type = "bool"
+	HTTPRequestDoc.Fields[29].Description = "IterateAll iterates all the values extracted from internal extractors"
+	HTTPRequestDoc.Fields[29].Comments[encoder.LineComment] = "IterateAll iterates all the values extracted from internal extractors"
+	HTTPRequestDoc.Fields[30].Name = "digest-username"
+	HTTPRequestDoc.Fields[30].Type = "string"
 	HTTPRequestDoc.Fields[30].Note = ""
-	HTTPRequestDoc.Fields[30].Description = "DisablePathAutomerge disables merging target url path with raw request path"
-	HTTPRequestDoc.Fields[30].Comments[encoder.LineComment] = "DisablePathAutomerge disables merging target url path with raw request path"
+	HTTPRequestDoc.Fields[30].Description = "DigestAuthUsername specifies the username for digest authentication"
+	HTTPRequestDoc.Fields[30].Comments[encoder.LineComment] = "DigestAuthUsername specifies the username for digest authentication"
+	HTTPRequestDoc.Fields[31].Name = "digest-password"
+	HTTPRequestDoc.Fields[31].Type = "string"
+	HTTPRequestDoc.Fields[31].Note = ""
+	HTTPRequestDoc.Fields[31].Description = "DigestAuthPassword specifies the password for digest authentication"
+	HTTPRequestDoc.Fields[31].Comments[encoder.LineComment] = "DigestAuthPassword specifies the password for digest authentication"
+	HTTPRequestDoc.Fields[32].Name = "disable-path-automerge"
+	HTTPRequestDoc.Fields[32].Type = "bool"
+	HTTPRequestDoc.Fields[32].Note = ""
+	HTTPRequestDoc.Fields[32].Description = "DisablePathAutomerge disables merging target url path with raw request path"
+	HTTPRequestDoc.Fields[32].Comments[encoder.LineComment] = "DisablePathAutomerge disables merging target url path with raw request path"
+	HTTPRequestDoc.Fields[33].Name = "ntlm-username"
+	HTTPRequestDoc.Fields[33].Type = "string"
+	HTTPRequestDoc.Fields[33].Note = ""
+	HTTPRequestDoc.Fields[33].Description = "NTLMAuthUsername specifies the username for NTLM authentication.\n\nThe domain can be specified as DOMAIN\\user."
+	HTTPRequestDoc.Fields[33].Comments[encoder.LineComment] = "NTLMAuthUsername specifies the username for NTLM authentication."
+	HTTPRequestDoc.Fields[34].Name = "ntlm-password"
+	HTTPRequestDoc.Fields[34].Type = "string"
+	HTTPRequestDoc.Fields[34].Note = ""
+	HTTPRequestDoc.Fields[34].Description = "NTLMAuthPassword specifies the password for NTLM authentication"
+	HTTPRequestDoc.Fields[34].Comments[encoder.LineComment] = "NTLMAuthPassword specifies the password for NTLM authentication"
+	HTTPRequestDoc.Fields[35].Name = "negotiate-username"
+	HTTPRequestDoc.Fields[35].Type = "string"
+	HTTPRequestDoc.Fields[35].Note = ""
+	HTTPRequestDoc.Fields[35].Description = "NegotiateAuthUsername specifies the user@REALM principal for Negotiate (Kerberos) authentication"
+	HTTPRequestDoc.Fields[35].Comments[encoder.LineComment] = "NegotiateAuthUsername specifies the user@REALM principal for Negotiate (Kerberos) authentication"
+	HTTPRequestDoc.Fields[36].Name = "negotiate-password"
+	HTTPRequestDoc.Fields[36].Type = "string"
+	HTTPRequestDoc.Fields[36].Note = ""
+	HTTPRequestDoc.Fields[36].Description = "NegotiateAuthPassword specifies the password for Negotiate (Kerberos) authentication"
+	HTTPRequestDoc.Fields[36].Comments[encoder.LineComment] = "NegotiateAuthPassword specifies the password for Negotiate (Kerberos) authentication"
+	HTTPRequestDoc.Fields[37].Name = "negotiate-kdc"
+	HTTPRequestDoc.Fields[37].Type = "string"
+	HTTPRequestDoc.Fields[37].Note = ""
+	HTTPRequestDoc.Fields[37].Description = "NegotiateAuthKDC specifies the KDC for Negotiate (Kerberos) authentication.\n\nThe KDC of the realm is looked up with DNS by default."
+	HTTPRequestDoc.Fields[37].Comments[encoder.LineComment] = "NegotiateAuthKDC specifies the KDC for Negotiate (Kerberos) authentication."
 
 	GENERATORSAttackTypeHolderDoc.Type = "generators.AttackTypeHolder"
 	GENERATORSAttackTypeHolderDoc.Comments[encoder.LineComment] = " AttackTypeHolder is used to hold internal type of the protocol"
@@ -669,6 +735,10 @@ func init() {
 			TypeName:  "javascript.Request",
 			FieldName: "attack",
 		},
+		{
+			TypeName:  "grpc.Request",
+			FieldName: "attack",
+		},
 	}
 	GENERATORSAttackTypeHolderDoc.Fields = make([]encoder.Doc, 1)
 	GENERATORSAttackTypeHolderDoc.Fields[0].Name = ""
@@ -724,7 +794,7 @@ func init() {
 			FieldName: "fuzzing",
 		},
 	}
-	FUZZRuleDoc.Fields = make([]encoder.Doc, 7)
+	FUZZRuleDoc.Fields = make([]encoder.Doc, 9)
 	FUZZRuleDoc.Fields[0].Name = "type"
 	FUZZRuleDoc.Fields[0].Type = "string"
 	FUZZRuleDoc.Fields[0].Note = ""
@@ -739,10 +809,14 @@ func init() {
 	FUZZRuleDoc.Fields[1].Name = "part"
 	FUZZRuleDoc.Fields[1].Type = "string"
 	FUZZRuleDoc.Fields[1].Note = ""
-	FUZZRuleDoc.Fields[1].Description = "Part is the part of request to fuzz.\n\nquery fuzzes the query part of url. More parts will be added later."
+	FUZZRuleDoc.Fields[1].Description = "Part is the part of request to fuzz.\n\nquery fuzzes the query part of url, headers fuzzes the header values,\nbody fuzzes the values of json, xml, form-urlencoded and multipart bodies,\npath fuzzes each segment of url path and cookie fuzzes each cookie value."
 	FUZZRuleDoc.Fields[1].Comments[encoder.LineComment] = "Part is the part of request to fuzz."
 	FUZZRuleDoc.Fields[1].Values = []string{
 		"query",
+		"headers",
+		"body",
+		"path",
+		"cookie",
 	}
 	FUZZRuleDoc.Fields[2].Name = "mode"
 	FUZZRuleDoc.Fields[2].Type = "string"
@@ -781,6 +855,16 @@ func init() {
 	FUZZRuleDoc.Fields[6].Comments[encoder.LineComment] = "Fuzz is the list of payloads to perform substitutions with."
 
 	FUZZRuleDoc.Fields[6].AddExample("Examples of fuzz", []string{"{{ssrf}}", "{{interactsh-url}}", "example-value"})
+	FUZZRuleDoc.Fields[7].Name = "analyzer"
+	FUZZRuleDoc.Fields[7].Type = "analyzers.AnalyzerTemplate"
+	FUZZRuleDoc.Fields[7].Note = ""
+	FUZZRuleDoc.Fields[7].Description = "Analyzer is the optional analyzer to confirm injection points with.\n\nPayloads containing the placeholder of the analyzer (eg. [SLEEPTIME] for time_delay)\nare sent with variants of the placeholder and only confirmed injection points are reported."
+	FUZZRuleDoc.Fields[7].Comments[encoder.LineComment] = "Analyzer is the optional analyzer to confirm injection points with."
+	FUZZRuleDoc.Fields[8].Name = "discovery"
+	FUZZRuleDoc.Fields[8].Type = "DiscoveryOptions"
+	FUZZRuleDoc.Fields[8].Note = ""
+	FUZZRuleDoc.Fields[8].Description = "Discovery is the optional hidden parameter discovery to perform on the part.\n\nParameters discovered from the wordlist are added to the base request so that\nthey are fuzzed by this and the following rules, and are reported as info findings."
+	FUZZRuleDoc.Fields[8].Comments[encoder.LineComment] = "Discovery is the optional hidden parameter discovery to perform on the part."
 
 	SignatureTypeHolderDoc.Type = "SignatureTypeHolder"
 	SignatureTypeHolderDoc.Comments[encoder.LineComment] = " SignatureTypeHolder is used to hold internal type of the signature"
@@ -1099,6 +1183,18 @@ func init() {
 			Key:   "raw",
 			Value: "Full Network protocol data",
 		},
+		{
+			Key:   "tls_version,cipher,tls_connection,sni",
+			Value: "TLS metadata of the connection for tls:// addresses and starttls",
+		},
+		{
+			Key:   "subject_dn,subject_cn,subject_an,issuer_dn,issuer_cn,serial",
+			Value: "Fields of the TLS certificate of the server",
+		},
+		{
+			Key:   "not_before,not_after,expired,self_signed,mismatched",
+			Value: "Validity of the TLS certificate of the server",
+		},
 	}
 	NETWORKRequestDoc.Fields = make([]encoder.Doc, 9)
 	NETWORKRequestDoc.Fields[0].Name = "id"
@@ -1162,7 +1258,7 @@ func init() {
 			FieldName: "inputs",
 		},
 	}
-	NETWORKInputDoc.Fields = make([]encoder.Doc, 4)
+	NETWORKInputDoc.Fields = make([]encoder.Doc, 11)
 	NETWORKInputDoc.Fields[0].Name = "data"
 	NETWORKInputDoc.Fields[0].Type = "string"
 	NETWORKInputDoc.Fields[0].Note = ""
@@ -1195,6 +1291,61 @@ func init() {
 	NETWORKInputDoc.Fields[3].Comments[encoder.LineComment] = "Name is the optional name of the data read to provide matching on."
 
 	NETWORKInputDoc.Fields[3].AddExample("", "prefix")
+	NETWORKInputDoc.Fields[4].Name = "starttls"
+	NETWORKInputDoc.Fields[4].Type = "string"
+	NETWORKInputDoc.Fields[4].Note = ""
+	NETWORKInputDoc.Fields[4].Description = "StartTLS performs the STARTTLS exchange of the protocol and upgrades the connection to TLS.\n\nInputs before it are sent in plaintext and inputs after it over TLS. The\ngreeting of the server is not read again if earlier inputs read from the\nconnection. The data exchanged during the negotiation can be matched with\n`name` attribute, and the TLS metadata of the connection is available to\nmatchers like in the ssl protocol."
+	NETWORKInputDoc.Fields[4].Comments[encoder.LineComment] = "StartTLS performs the STARTTLS exchange of the protocol and upgrades the connection to TLS."
+	NETWORKInputDoc.Fields[4].Values = []string{
+		"smtp",
+		"imap",
+		"pop3",
+		"ftp",
+		"ldap",
+		"xmpp",
+		"postgres",
+	}
+
+	NETWORKInputDoc.Fields[5].Name = "read-until"
+	NETWORKInputDoc.Fields[5].Type = "string"
+	NETWORKInputDoc.Fields[5].Note = ""
+	NETWORKInputDoc.Fields[5].Description = "ReadUntil reads from socket until the delimiter is received.\n\nIt supports DSL Helper Functions as well as normal expressions. When `read`\nis specified it is the maximum number of bytes to read, otherwise 65536."
+	NETWORKInputDoc.Fields[5].Comments[encoder.LineComment] = "ReadUntil reads from socket until the delimiter is received."
+
+	NETWORKInputDoc.Fields[5].AddExample("", "\r\n")
+	NETWORKInputDoc.Fields[6].Name = "read-regex"
+	NETWORKInputDoc.Fields[6].Type = "string"
+	NETWORKInputDoc.Fields[6].Note = ""
+	NETWORKInputDoc.Fields[6].Description = "ReadRegex reads from socket until the data read matches the regex."
+	NETWORKInputDoc.Fields[6].Comments[encoder.LineComment] = "ReadRegex reads from socket until the data read matches the regex."
+
+	NETWORKInputDoc.Fields[6].AddExample("", "(?m)^220 .*\r\n")
+	NETWORKInputDoc.Fields[7].Name = "read-frame"
+	NETWORKInputDoc.Fields[7].Type = "network.ReadFrame"
+	NETWORKInputDoc.Fields[7].Note = ""
+	NETWORKInputDoc.Fields[7].Description = "ReadFrame reads a length-prefixed frame from socket."
+	NETWORKInputDoc.Fields[7].Comments[encoder.LineComment] = "ReadFrame reads a length-prefixed frame from socket."
+	NETWORKInputDoc.Fields[8].Name = "read-idle"
+	NETWORKInputDoc.Fields[8].Type = "string"
+	NETWORKInputDoc.Fields[8].Note = ""
+	NETWORKInputDoc.Fields[8].Description = "ReadIdle reads from socket until no data is received for the duration."
+	NETWORKInputDoc.Fields[8].Comments[encoder.LineComment] = "ReadIdle reads from socket until no data is received for the duration."
+
+	NETWORKInputDoc.Fields[8].AddExample("", "500ms")
+	NETWORKInputDoc.Fields[9].Name = "if"
+	NETWORKInputDoc.Fields[9].Type = "string"
+	NETWORKInputDoc.Fields[9].Note = ""
+	NETWORKInputDoc.Fields[9].Description = "If is a DSL expression evaluated before the input, which is skipped when it is false.\n\nData read by previous inputs is available with their `name`."
+	NETWORKInputDoc.Fields[9].Comments[encoder.LineComment] = "If is a DSL expression evaluated before the input, which is skipped when it is false."
+
+	NETWORKInputDoc.Fields[9].AddExample("", "starts_with(greeting, '+OK')")
+	NETWORKInputDoc.Fields[10].Name = "expect"
+	NETWORKInputDoc.Fields[10].Type = "string"
+	NETWORKInputDoc.Fields[10].Note = ""
+	NETWORKInputDoc.Fields[10].Description = "Expect is a DSL expression evaluated after the input, which stops the\nconversation without results when it is false.\n\nData read by the input is available as `data`."
+	NETWORKInputDoc.Fields[10].Comments[encoder.LineComment] = "Expect is a DSL expression evaluated after the input, which stops the"
+
+	NETWORKInputDoc.Fields[10].AddExample("", "starts_with(hex_encode(data), '0300')")
 
 	NetworkInputTypeHolderDoc.Type = "NetworkInputTypeHolder"
 	NetworkInputTypeHolderDoc.Comments[encoder.LineComment] = " NetworkInputTypeHolder is used to hold internal type of the Network type"
@@ -1216,6 +1367,50 @@ func init() {
 		"text",
 	}
 
+	NETWORKReadFrameDoc.Type = "network.ReadFrame"
+	NETWORKReadFrameDoc.Comments[encoder.LineComment] = " ReadFrame describes the length prefix of a frame to read from socket."
+	NETWORKReadFrameDoc.Description = "ReadFrame describes the length prefix of a frame to read from socket.\n\n The frame is made of `offset` bytes, the length field and the number of\n bytes given by the length field plus `adjust`."
+	NETWORKReadFrameDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "network.Input",
+			FieldName: "read-frame",
+		},
+	}
+	NETWORKReadFrameDoc.Fields = make([]encoder.Doc, 4)
+	NETWORKReadFrameDoc.Fields[0].Name = "size"
+	NETWORKReadFrameDoc.Fields[0].Type = "int"
+	NETWORKReadFrameDoc.Fields[0].Note = ""
+	NETWORKReadFrameDoc.Fields[0].Description = "Size is the size of the length field in bytes."
+	NETWORKReadFrameDoc.Fields[0].Comments[encoder.LineComment] = "Size is the size of the length field in bytes."
+	NETWORKReadFrameDoc.Fields[0].Values = []string{
+		"1",
+		"2",
+		"4",
+	}
+	NETWORKReadFrameDoc.Fields[1].Name = "endian"
+	NETWORKReadFrameDoc.Fields[1].Type = "string"
+	NETWORKReadFrameDoc.Fields[1].Note = ""
+	NETWORKReadFrameDoc.Fields[1].Description = "Endian is the byte order of the length field.\n\nDefault value is big."
+	NETWORKReadFrameDoc.Fields[1].Comments[encoder.LineComment] = "Endian is the byte order of the length field."
+	NETWORKReadFrameDoc.Fields[1].Values = []string{
+		"big",
+		"little",
+	}
+	NETWORKReadFrameDoc.Fields[2].Name = "offset"
+	NETWORKReadFrameDoc.Fields[2].Type = "int"
+	NETWORKReadFrameDoc.Fields[2].Note = ""
+	NETWORKReadFrameDoc.Fields[2].Description = "Offset is the number of bytes before the length field."
+	NETWORKReadFrameDoc.Fields[2].Comments[encoder.LineComment] = "Offset is the number of bytes before the length field."
+
+	NETWORKReadFrameDoc.Fields[2].AddExample("", 2)
+	NETWORKReadFrameDoc.Fields[3].Name = "adjust"
+	NETWORKReadFrameDoc.Fields[3].Type = "int"
+	NETWORKReadFrameDoc.Fields[3].Note = ""
+	NETWORKReadFrameDoc.Fields[3].Description = "Adjust is added to the length to get the number of bytes following\nthe length field.\n\nFor example, it is -4 for a 4 bytes length counting itself."
+	NETWORKReadFrameDoc.Fields[3].Comments[encoder.LineComment] = "Adjust is added to the length to get the number of bytes following"
+
+	NETWORKReadFrameDoc.Fields[3].AddExample("", -4)
+
 	HEADLESSRequestDoc.Type = "headless.Request"
 	HEADLESSRequestDoc.Comments[encoder.LineComment] = " Request contains a Headless protocol request to be made from a template"
 	HEADLESSRequestDoc.Description = "Request contains a Headless protocol request to be made from a template"
@@ -1722,6 +1917,121 @@ func init() {
 	JAVASCRIPTRequestDoc.Fields[8].Description = "Payloads contains any payloads for the current request.\n\nPayloads support both key-values combinations where a list\nof payloads is provided, or optionally a single file can also\nbe provided as payload which will be read on run-time."
 	JAVASCRIPTRequestDoc.Fields[8].Comments[encoder.LineComment] = "Payloads contains any payloads for the current request."
 
+	GRPCRequestDoc.Type = "grpc.Request"
+	GRPCRequestDoc.Comments[encoder.LineComment] = " Request is a request for the gRPC protocol"
+	GRPCRequestDoc.Description = "Request is a request for the gRPC protocol"
+	GRPCRequestDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "Template",
+			FieldName: "grpc",
+		},
+	}
+	GRPCRequestDoc.PartDefinitions = []encoder.KeyValue{
+		{
+			Key:   "type",
+			Value: "Type is the type of request made",
+		},
+		{
+			Key:   "host",
+			Value: "Host is the input to the template",
+		},
+		{
+			Key:   "matched",
+			Value: "Matched is the address which was matched upon",
+		},
+		{
+			Key:   "method",
+			Value: "Method is the fully qualified method called",
+		},
+		{
+			Key:   "request",
+			Value: "gRPC request made to the server",
+		},
+		{
+			Key:   "response",
+			Value: "JSON response received from the server (a JSON array for server streaming calls)",
+		},
+		{
+			Key:   "header",
+			Value: "Metadata headers received from the server",
+		},
+		{
+			Key:   "trailer",
+			Value: "Metadata trailers received from the server",
+		},
+		{
+			Key:   "status_code",
+			Value: "Numeric gRPC status code of the call",
+		},
+		{
+			Key:   "status",
+			Value: "Name of the gRPC status code of the call",
+		},
+		{
+			Key:   "status_message",
+			Value: "Message of the gRPC status of the call",
+		},
+	}
+	GRPCRequestDoc.Fields = make([]encoder.Doc, 10)
+	GRPCRequestDoc.Fields[0].Name = "id"
+	GRPCRequestDoc.Fields[0].Type = "string"
+	GRPCRequestDoc.Fields[0].Note = ""
+	GRPCRequestDoc.Fields[0].Description = "ID is the optional id of the request"
+	GRPCRequestDoc.Fields[0].Comments[encoder.LineComment] = " ID is the optional id of the request"
+	GRPCRequestDoc.Fields[1].Name = "address"
+	GRPCRequestDoc.Fields[1].Type = "string"
+	GRPCRequestDoc.Fields[1].Note = ""
+	GRPCRequestDoc.Fields[1].Description = "Address is the host:port of the gRPC server.\n\nDefault value is `{{Hostname}}`. Port 443 is used for addresses without a port."
+	GRPCRequestDoc.Fields[1].Comments[encoder.LineComment] = "Address is the host:port of the gRPC server."
+
+	GRPCRequestDoc.Fields[1].AddExample("", "{{Host}}:50051")
+	GRPCRequestDoc.Fields[2].Name = "tls"
+	GRPCRequestDoc.Fields[2].Type = "bool"
+	GRPCRequestDoc.Fields[2].Note = ""
+	GRPCRequestDoc.Fields[2].Description = "TLS enables TLS for the connection to the server. Plaintext is used by default."
+	GRPCRequestDoc.Fields[2].Comments[encoder.LineComment] = "TLS enables TLS for the connection to the server. Plaintext is used by default."
+	GRPCRequestDoc.Fields[3].Name = "method"
+	GRPCRequestDoc.Fields[3].Type = "string"
+	GRPCRequestDoc.Fields[3].Note = ""
+	GRPCRequestDoc.Fields[3].Description = "Method is the fully qualified method to call.\n\nBoth the package.Service/Method and package.Service.Method formats are supported.\nUnary and server streaming methods can be called."
+	GRPCRequestDoc.Fields[3].Comments[encoder.LineComment] = "Method is the fully qualified method to call."
+
+	GRPCRequestDoc.Fields[3].AddExample("", "grpc.health.v1.Health/Check")
+	GRPCRequestDoc.Fields[4].Name = "message"
+	GRPCRequestDoc.Fields[4].Type = "string"
+	GRPCRequestDoc.Fields[4].Note = ""
+	GRPCRequestDoc.Fields[4].Description = "Message is the request message as a JSON or YAML document.\n\nIt supports DSL Helper Functions as well as normal expressions."
+	GRPCRequestDoc.Fields[4].Comments[encoder.LineComment] = "Message is the request message as a JSON or YAML document."
+
+	GRPCRequestDoc.Fields[4].AddExample("", "{\"service\": \"\"}")
+	GRPCRequestDoc.Fields[5].Name = "metadata"
+	GRPCRequestDoc.Fields[5].Type = "map[string]string"
+	GRPCRequestDoc.Fields[5].Note = ""
+	GRPCRequestDoc.Fields[5].Description = "Metadata contains the metadata headers sent with the call."
+	GRPCRequestDoc.Fields[5].Comments[encoder.LineComment] = "Metadata contains the metadata headers sent with the call."
+	GRPCRequestDoc.Fields[6].Name = "proto-files"
+	GRPCRequestDoc.Fields[6].Type = "[]string"
+	GRPCRequestDoc.Fields[6].Note = ""
+	GRPCRequestDoc.Fields[6].Description = "ProtoFiles contains the .proto files describing the service.\n\nRelative paths are resolved from the template directory. The service is\nresolved through server reflection when no proto definition is provided."
+	GRPCRequestDoc.Fields[6].Comments[encoder.LineComment] = "ProtoFiles contains the .proto files describing the service."
+
+	GRPCRequestDoc.Fields[6].AddExample("", []string{"helloworld.proto"})
+	GRPCRequestDoc.Fields[7].Name = "proto"
+	GRPCRequestDoc.Fields[7].Type = "string"
+	GRPCRequestDoc.Fields[7].Note = ""
+	GRPCRequestDoc.Fields[7].Description = "Proto is an inline .proto definition describing the service."
+	GRPCRequestDoc.Fields[7].Comments[encoder.LineComment] = "Proto is an inline .proto definition describing the service."
+	GRPCRequestDoc.Fields[8].Name = "attack"
+	GRPCRequestDoc.Fields[8].Type = "generators.AttackTypeHolder"
+	GRPCRequestDoc.Fields[8].Note = ""
+	GRPCRequestDoc.Fields[8].Description = "Attack is the type of payload combinations to perform.\n\nBatteringram is inserts the same payload into all defined payload positions at once, pitchfork combines multiple payload sets and clusterbomb generates\npermutations and combinations for all payloads."
+	GRPCRequestDoc.Fields[8].Comments[encoder.LineComment] = "Attack is the type of payload combinations to perform."
+	GRPCRequestDoc.Fields[9].Name = "payloads"
+	GRPCRequestDoc.Fields[9].Type = "map[string]interface{}"
+	GRPCRequestDoc.Fields[9].Note = ""
+	GRPCRequestDoc.Fields[9].Description = "Payloads contains any payloads for the current request.\n\nPayloads support both key-values combinations where a list\nof payloads is provided, or optionally a single file can also\nbe provided as payload which will be read on run-time."
+	GRPCRequestDoc.Fields[9].Comments[encoder.LineComment] = "Payloads contains any payloads for the current request."
+
 	HTTPSignatureTypeHolderDoc.Type = "http.SignatureTypeHolder"
 	HTTPSignatureTypeHolderDoc.Comments[encoder.LineComment] = " SignatureTypeHolder is used to hold internal type of the signature"
 	HTTPSignatureTypeHolderDoc.Description = "SignatureTypeHolder is used to hold internal type of the signature"
@@ -1768,6 +2078,7 @@ func GetTemplateDoc() *encoder.FileDoc {
 			&NETWORKRequestDoc,
 			&NETWORKInputDoc,
 			&NetworkInputTypeHolderDoc,
+			&NETWORKReadFrameDoc,
 			&HEADLESSRequestDoc,
 			&ENGINEActionDoc,
 			&ActionTypeHolderDoc,
@@ -1778,6 +2089,7 @@ func GetTemplateDoc() *encoder.FileDoc {
 			&WHOISRequestDoc,
 			&CODERequestDoc,
 			&JAVASCRIPTRequestDoc,
+			&GRPCRequestDoc,
 			&HTTPSignatureTypeHolderDoc,
 			&VARIABLESVariableDoc,
 		},
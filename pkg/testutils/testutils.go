@@ -207,3 +207,9 @@ func (m *MockProgressClient) IncrementErrorsBy(count int64) {}
 // IncrementFailedRequestsBy increments the number of requests counter by count
 // along with errors.
 func (m *MockProgressClient) IncrementFailedRequestsBy(count int64) {}
+
+// IncrementThrottled increments the counter of responses of hosts throttling requests by 1.
+func (m *MockProgressClient) IncrementThrottled() {}
+
+// AddBackoffHosts adds a delta to the number of hosts being slowed down.
+func (m *MockProgressClient) AddBackoffHosts(delta int) {}
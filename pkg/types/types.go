@@ -46,6 +46,8 @@ type Options struct {
 	ExcludeMatchers goflags.StringSlice
 	// CustomHeaders is the list of custom global headers to send with each request.
 	CustomHeaders goflags.StringSlice
+	// SecretsFile is the list of secrets files for authenticated scans
+	SecretsFile goflags.StringSlice
 	// Vars is the list of custom global vars
 	Vars goflags.RuntimeMap
 	// Severities filters templates based on their severity and only run the matching ones.
@@ -78,6 +80,10 @@ type Options struct {
 	Targets goflags.StringSlice
 	// TargetsFilePath specifies the targets from a file to scan using templates.
 	TargetsFilePath string
+	// InputFileMode specifies the mode (format) of the input file (list, openapi, etc)
+	InputFileMode string
+	// PostmanEnvironmentFile is the postman environment file used to resolve collection variables
+	PostmanEnvironmentFile string
 	// Resume the scan from the state stored in the resume config file
 	Resume string
 	// Output is the file to write found results to.
@@ -174,6 +180,12 @@ type Options struct {
 	RateLimit int
 	// Rate-Limit is the maximum number of requests per minute for specified target
 	RateLimitMinute int
+	// RateLimitHost is the maximum number of requests per second per host
+	RateLimitHost int
+	// DisableAdaptiveRateLimit disables slowing down hosts throttling requests
+	DisableAdaptiveRateLimit bool
+	// MaxHostConcurrency is the maximum number of requests in flight per host
+	MaxHostConcurrency int
 	// PageTimeout is the maximum time to wait for a page in seconds
 	PageTimeout int
 	// InteractionsCacheSize is the number of interaction-url->req to keep in cache at a time.
@@ -198,6 +210,10 @@ type Options struct {
 	OfflineHTTP bool
 	// Force HTTP2 requests
 	ForceAttemptHTTP2 bool
+	// HTTPProtocol is the http protocol used to send the requests of http templates (h3, alt-svc, h2c, h2c-prior-knowledge)
+	HTTPProtocol string
+	// Signature is the signer used to sign the requests of http templates not setting one
+	Signature string
 	// StatsJSON writes stats output in JSON format
 	StatsJSON bool
 	// Headless specifies whether to allow headless mode templates
@@ -3,6 +3,7 @@ package utils
 import (
 	"errors"
 	"io"
+	"net"
 	"net/url"
 	"strings"
 
@@ -34,6 +35,20 @@ func IsURL(input string) bool {
 	return err == nil && u.Scheme != "" && u.Host != ""
 }
 
+// Hostname returns the lowercase hostname of the value which can
+// be a URL, a host:port or a host.
+func Hostname(value string) string {
+	if strings.Contains(value, "://") {
+		if parsed, err := url.Parse(value); err == nil {
+			return strings.ToLower(parsed.Hostname())
+		}
+	}
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		return strings.ToLower(host)
+	}
+	return strings.ToLower(value)
+}
+
 // ReadFromPathOrURL reads and returns the contents of a file or url.
 func ReadFromPathOrURL(templatePath string, catalog catalog.Catalog) (data []byte, err error) {
 	var reader io.Reader
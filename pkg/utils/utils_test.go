@@ -19,3 +19,9 @@ func TestUnwrapError(t *testing.T) {
 	errThree := fmt.Errorf("error with error: %w", errTwo)
 	require.Equal(t, errOne, UnwrapError(errThree))
 }
+
+func TestHostname(t *testing.T) {
+	for _, value := range []string{"https://Example.com/path", "http://example.com:8080", "example.com:443", "example.com"} {
+		require.Equal(t, "example.com", Hostname(value), "could not get host of %s", value)
+	}
+}